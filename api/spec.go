@@ -0,0 +1,45 @@
+// Package api - OpenAPI-спецификация HTTP API сервиса заказов.
+// По ней генерируется клиент pkg/orderclient и проверяются ответы ручек в тестах.
+package api
+
+import (
+	_ "embed"
+	"encoding/json"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+//go:generate oapi-codegen -config oapi-codegen.yaml openapi.yaml
+
+//go:embed openapi.yaml
+var specYAML []byte
+
+var (
+	specOnce sync.Once
+	specJSON []byte
+	specErr  error
+)
+
+// Load разбирает и проверяет встроенную спецификацию
+func Load() (*openapi3.T, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(specYAML)
+	if err != nil {
+		return nil, err
+	}
+	if err := doc.Validate(openapi3.NewLoader().Context); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// JSON возвращает спецификацию в JSON; разбирается один раз
+func JSON() ([]byte, error) {
+	specOnce.Do(func() {
+		var doc *openapi3.T
+		if doc, specErr = Load(); specErr == nil {
+			specJSON, specErr = json.Marshal(doc)
+		}
+	})
+	return specJSON, specErr
+}
@@ -3,13 +3,21 @@ package main
 import (
 	"L0-wb/config"
 	"L0-wb/internal/db"
+	"L0-wb/internal/grpcapi"
 	"L0-wb/internal/handler"
 	"L0-wb/internal/kafka"
+	"L0-wb/internal/migrate"
+	"L0-wb/internal/outbox"
 	"L0-wb/internal/repo"
 	"L0-wb/internal/service"
+	"L0-wb/internal/webhook"
+	"L0-wb/migrations"
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -30,6 +38,13 @@ func main() {
 		}
 	}()
 
+	// Схема нужна до RestoreCache, поэтому миграции применяются первыми
+	if cfg.Postgres.AutoMigrate {
+		if err := runMigrations(sqlDB); err != nil {
+			log.Fatalf("failed to apply migrations: %v", err)
+		}
+	}
+
 	pgRepo := repo.NewRepo(sqlDB)
 	svc, err := service.NewService(pgRepo)
 	if err != nil {
@@ -55,12 +70,43 @@ func main() {
 	defer cancel()
 
 	// Запускаем консьюмер в горутине и обрабатываем ошибки
+	consumerDone := make(chan struct{})
 	go func() {
+		defer close(consumerDone)
 		if err := cons.ConsumeMessages(ctx); err != nil && !errors.Is(err, context.Canceled) {
 			log.Printf("consumer error: %v", err)
 		}
 	}()
 
+	// Реле outbox публикует события order.saved после коммита заказов
+	relayDone := make(chan struct{})
+	var relay *outbox.Relay
+	if cfg.Outbox.Enabled {
+		relay = outbox.NewRelay(*cfg, pgRepo)
+		go func() {
+			defer close(relayDone)
+			if err := relay.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+				log.Printf("outbox relay error: %v", err)
+			}
+		}()
+	} else {
+		close(relayDone)
+	}
+
+	// Диспетчер доставляет вебхуки из очереди webhook_deliveries
+	dispatcherDone := make(chan struct{})
+	if cfg.Webhook.Enabled {
+		dispatcher := webhook.NewDispatcher(*cfg, pgRepo)
+		go func() {
+			defer close(dispatcherDone)
+			if err := dispatcher.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+				log.Printf("webhook dispatcher error: %v", err)
+			}
+		}()
+	} else {
+		close(dispatcherDone)
+	}
+
 	// Запускаем HTTP сервер в горутине
 	go func() {
 		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
@@ -68,6 +114,21 @@ func main() {
 		}
 	}()
 
+	// gRPC API для внутренних сервисов слушает отдельный порт
+	var grpcSrv *grpcapi.Server
+	if cfg.GRPC.Enabled {
+		lis, err := net.Listen("tcp", fmt.Sprintf("%s:%d", cfg.GRPC.Host, cfg.GRPC.Port))
+		if err != nil {
+			log.Fatalf("failed to listen for gRPC: %v", err)
+		}
+		grpcSrv = grpcapi.NewServer(cfg, svc)
+		go func() {
+			if err := grpcSrv.Serve(lis); err != nil {
+				log.Printf("grpc server error: %v", err)
+			}
+		}()
+	}
+
 	// Обработка сигналов завершения
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
@@ -78,18 +139,53 @@ func main() {
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer shutdownCancel()
 
+	// Останавливаем чтение и ждём, пока пул воркеров доработает принятые сообщения
+	cancel()
+	select {
+	case <-consumerDone:
+	case <-shutdownCtx.Done():
+		log.Println("consumer drain timed out")
+	}
+
 	if err := cons.Close(); err != nil {
 		log.Printf("error stopping consumer: %v", err)
 	}
 
+	<-relayDone
+	if relay != nil {
+		if err := relay.Close(); err != nil {
+			log.Printf("error stopping outbox relay: %v", err)
+		}
+	}
+
+	<-dispatcherDone
+
 	if err := srv.Shutdown(shutdownCtx); err != nil {
 		log.Printf("error stopping server: %v", err)
 	}
 
+	if grpcSrv != nil {
+		grpcSrv.Shutdown(shutdownCtx)
+	}
+
 	if err := pgRepo.Close(); err != nil {
 		log.Printf("error closing repository: %v", err)
 	}
 
-	cancel()
 	log.Println("shutdown complete")
 }
+
+func runMigrations(sqlDB *sql.DB) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	m, err := migrate.New(sqlDB, migrations.FS)
+	if err != nil {
+		return err
+	}
+	applied, err := m.Up(ctx)
+	for _, mig := range applied {
+		log.Printf("migration applied: %d_%s", mig.Version, mig.Name)
+	}
+	return err
+}
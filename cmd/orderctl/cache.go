@@ -0,0 +1,76 @@
+package main
+
+import (
+	"L0-wb/config"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Кэш живёт в процессе сервиса, поэтому cache-команды обращаются к его HTTP API
+func runCache(ctx context.Context, cfg *config.Config, args []string) error {
+	name, args, err := subcommand(args, "stats", "warm")
+	if err != nil {
+		return err
+	}
+
+	fs := flag.NewFlagSet("cache "+name, flag.ExitOnError)
+	addr := fs.String("addr", fmt.Sprintf("http://localhost:%d", cfg.HTTPServer.Port), "адрес сервиса")
+	limit := fs.Int("limit", 0, "сколько последних заказов загрузить (0 - по настройкам сервиса)")
+	token := fs.String("token", cfg.HTTPServer.AdminToken, "bearer-токен служебных ручек (по умолчанию HTTP_ADMIN_TOKEN)")
+	_ = fs.Parse(args)
+
+	base, err := url.Parse(*addr)
+	if err != nil {
+		return fmt.Errorf("invalid -addr: %w", err)
+	}
+	method, endpoint := http.MethodGet, base.JoinPath("/api/v1/admin/cache/stats")
+	if name == "warm" {
+		method, endpoint = http.MethodPost, base.JoinPath("/api/v1/admin/cache/warm")
+		if *limit > 0 {
+			endpoint.RawQuery = url.Values{"limit": {strconv.Itoa(*limit)}}.Encode()
+		}
+	}
+	return callService(ctx, method, endpoint.String(), *token)
+}
+
+// callService вызывает служебную ручку сервиса с bearer-токеном и печатает data ответа
+func callService(ctx context.Context, method, endpoint, token string) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// успешный ответ - {"status","data"}, ошибка - application/problem+json
+	var body struct {
+		Detail string          `json:"detail"`
+		Data   json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("%s %s: %s", method, endpoint, resp.Status)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s %s: %s: %s", method, endpoint, resp.Status, body.Detail)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(body.Data)
+}
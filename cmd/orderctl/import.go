@@ -0,0 +1,184 @@
+package main
+
+import (
+	"L0-wb/config"
+	"L0-wb/internal/db"
+	"L0-wb/internal/importer"
+	"L0-wb/internal/repo"
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func runImport(ctx context.Context, cfg *config.Config, args []string) error {
+	path, args := positional(args)
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	batchSize := fs.Int("batch", 100, "заказов в одной транзакции")
+	formatName := fs.String("format", "", "ndjson или csv (по умолчанию по расширению файла)")
+	modeName := fs.String("on-duplicate", string(importer.Skip), "skip или upsert")
+	reportPath := fs.String("report", "", "отчёт NDJSON (по умолчанию <file>.report.ndjson)")
+	checkpointPath := fs.String("checkpoint", "", "контрольная точка (по умолчанию <file>.checkpoint)")
+	resume := fs.Bool("resume", false, "продолжить прерванный импорт с контрольной точки")
+	_ = fs.Parse(args)
+	if path == "" {
+		path = fs.Arg(0)
+	}
+	if path == "" {
+		return fmt.Errorf("usage: orderctl import <file|-> [-format ndjson|csv] [-on-duplicate skip|upsert] [-resume]")
+	}
+	if *batchSize < 1 {
+		return fmt.Errorf("batch must be positive")
+	}
+	mode, err := importer.ParseMode(*modeName)
+	if err != nil {
+		return err
+	}
+	if *formatName == "" && path != "-" {
+		*formatName = strings.TrimPrefix(filepath.Ext(path), ".")
+	}
+
+	// stdin нельзя перечитать, поэтому без файла импорт не возобновляется
+	if path == "-" {
+		if *resume {
+			return fmt.Errorf("cannot resume import from stdin")
+		}
+		if *reportPath == "" {
+			*reportPath = "import.report.ndjson"
+		}
+	} else {
+		if *reportPath == "" {
+			*reportPath = path + ".report.ndjson"
+		}
+		if *checkpointPath == "" {
+			*checkpointPath = path + ".checkpoint"
+		}
+	}
+
+	var cp *importer.Checkpoint
+	if *checkpointPath != "" {
+		if cp, err = importer.LoadCheckpoint(*checkpointPath); err != nil {
+			return fmt.Errorf("failed to read checkpoint: %w", err)
+		}
+	}
+	switch {
+	case cp != nil && !*resume:
+		return fmt.Errorf("checkpoint %s exists: rerun with -resume or remove it", *checkpointPath)
+	case cp == nil && *resume:
+		return fmt.Errorf("no checkpoint to resume from")
+	case cp != nil && cp.Input != path:
+		return fmt.Errorf("checkpoint %s belongs to %s", *checkpointPath, cp.Input)
+	case cp == nil:
+		cp = &importer.Checkpoint{Input: path}
+	}
+
+	in, err := openInput(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	src, err := newSource(in, *formatName)
+	if err != nil {
+		return err
+	}
+
+	report, err := openReport(*reportPath, cp.ReportOffset, *resume)
+	if err != nil {
+		return err
+	}
+	defer report.Close()
+	reportBuf := bufio.NewWriter(report)
+	enc := json.NewEncoder(reportBuf)
+
+	pgRepo := repo.NewRepo(db.NewDB(cfg))
+	defer pgRepo.Close()
+
+	im := importer.New(pgRepo, importer.Options{
+		BatchSize: *batchSize,
+		Mode:      mode,
+		After:     cp.Position,
+		Summary:   cp.Summary,
+		OnResult: func(res importer.Result) error {
+			if res.Status == importer.Rejected {
+				log.Printf("#%d %s: %s", res.Line, res.OrderUID, res.Reason)
+			}
+			return enc.Encode(res)
+		},
+		OnBatch: func(pos int, summary importer.Summary) error {
+			if *checkpointPath == "" {
+				return reportBuf.Flush()
+			}
+			// отчёт должен попасть на диск раньше контрольной точки, которая на него ссылается
+			if err := reportBuf.Flush(); err != nil {
+				return err
+			}
+			if err := report.Sync(); err != nil {
+				return err
+			}
+			offset, err := report.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return err
+			}
+			cp.Position, cp.ReportOffset, cp.Summary = pos, offset, summary
+			return cp.Save(*checkpointPath)
+		},
+	})
+
+	summary, err := im.Run(ctx, src)
+	if flushErr := reportBuf.Flush(); err == nil {
+		err = flushErr
+	}
+	if err != nil {
+		if *checkpointPath != "" {
+			log.Printf("import interrupted at record %d (%s), rerun with -resume", cp.Position, cp.Summary)
+		}
+		return err
+	}
+	if *checkpointPath != "" {
+		if err := os.Remove(*checkpointPath); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	log.Printf("import finished: %s, report: %s", summary, *reportPath)
+	return nil
+}
+
+func newSource(r io.Reader, format string) (importer.Source, error) {
+	switch strings.ToLower(format) {
+	case "", "ndjson", "json", "jsonl":
+		return importer.NewNDJSONSource(r), nil
+	case "csv":
+		return importer.NewCSVSource(r)
+	default:
+		return nil, fmt.Errorf("unsupported import format %q (want ndjson or csv)", format)
+	}
+}
+
+// openReport открывает отчёт; при возобновлении отбрасывает строки пачки,
+// которая не успела попасть в контрольную точку
+func openReport(path string, offset int64, resume bool) (*os.File, error) {
+	if !resume {
+		return os.Create(path)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.Truncate(offset); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
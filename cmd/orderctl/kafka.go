@@ -0,0 +1,119 @@
+package main
+
+import (
+	"L0-wb/config"
+	"L0-wb/internal/kafka"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+func runConsumer(ctx context.Context, cfg *config.Config, args []string) error {
+	_, args, err := subcommand(args, "lag")
+	if err != nil {
+		return err
+	}
+	fs := flag.NewFlagSet("consumer lag", flag.ExitOnError)
+	group := fs.String("group", cfg.Kafka.Group, "consumer group")
+	_ = fs.Parse(args)
+
+	groupCfg := *cfg
+	groupCfg.Kafka.Group = *group
+	lags, err := kafka.NewAdmin(groupCfg).ConsumerLag(ctx)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "topic %s, group %s\n", cfg.Kafka.Topic, *group)
+	fmt.Fprintln(tw, "PARTITION\tCOMMITTED\tLATEST\tLAG")
+	var total int64
+	for _, l := range lags {
+		committed := "-"
+		if l.Committed >= 0 {
+			committed = fmt.Sprint(l.Committed)
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%d\t%d\n", l.Partition, committed, l.Latest, l.Lag)
+		total += l.Lag
+	}
+	fmt.Fprintf(tw, "total\t\t\t%d\n", total)
+	return tw.Flush()
+}
+
+func runDLQ(ctx context.Context, cfg *config.Config, args []string) error {
+	name, args, err := subcommand(args, "list", "redrive")
+	if err != nil {
+		return err
+	}
+
+	fs := flag.NewFlagSet("dlq "+name, flag.ExitOnError)
+	from := fs.String("from", "earliest", "начало: earliest, смещение или время RFC3339")
+	limit := fs.Int("limit", 0, "максимум сообщений (0 - все; для list по умолчанию 20)")
+	verbose := fs.Bool("v", false, "list: показать тело сообщения")
+	errorSubstr := fs.String("error", "", "redrive: только сообщения с подстрокой в x-error")
+	offsets := fs.String("offsets", "", "redrive: только позиции DLQ partition:offset через запятую")
+	dryRun := fs.Bool("dry-run", false, "redrive: только показать, что будет отправлено")
+	_ = fs.Parse(args)
+
+	spec, err := kafka.ParseOffsetSpec(*from)
+	if err != nil {
+		return err
+	}
+	if name == "list" && *limit == 0 {
+		*limit = 20
+	}
+
+	dlq := kafka.NewDLQ(*cfg)
+	defer dlq.Close()
+
+	letters, err := dlq.List(ctx, spec, *limit)
+	if err != nil {
+		return err
+	}
+
+	if name == "list" {
+		for _, dl := range letters {
+			printDeadLetter(dl, *verbose)
+		}
+		log.Printf("%d messages in %s", len(letters), cfg.Kafka.DLQTopic)
+		return nil
+	}
+
+	positions := make(map[string]bool)
+	for _, p := range strings.Split(*offsets, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			positions[p] = true
+		}
+	}
+	selected := letters[:0]
+	for _, dl := range letters {
+		if kafka.MatchDeadLetter(dl, *errorSubstr, positions) {
+			selected = append(selected, dl)
+		}
+	}
+
+	if *dryRun {
+		for _, dl := range selected {
+			printDeadLetter(dl, false)
+		}
+		log.Printf("would redrive %d of %d messages", len(selected), len(letters))
+		return nil
+	}
+	sent, err := dlq.Redrive(ctx, selected)
+	log.Printf("redriven %d of %d messages", sent, len(letters))
+	return err
+}
+
+func printDeadLetter(dl kafka.DeadLetter, verbose bool) {
+	fmt.Printf("%d:%d key=%s from=%s/%d@%d failed_at=%s error=%q\n",
+		dl.Partition, dl.Offset, dl.Key, dl.OriginalTopic, dl.OriginalPartition, dl.OriginalOffset,
+		dl.FailedAt.Format(time.RFC3339), dl.Error)
+	if verbose {
+		fmt.Printf("  %s\n", dl.Value)
+	}
+}
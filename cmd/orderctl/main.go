@@ -0,0 +1,84 @@
+package main
+
+import (
+	"L0-wb/config"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+const usage = `Использование: orderctl <команда> [флаги]
+
+Заказы:
+  get <uid>                       показать заказ
+  list [фильтры] [-json]          список заказов, новые первыми
+  validate <file|-> [-totals]     проверить заказы из JSON/NDJSON без записи в БД
+  import <file|-> [-batch 100]    загрузить заказы из NDJSON/CSV с отчётом
+                                  [-on-duplicate skip|upsert] [-report file] [-resume]
+  export [фильтры] [-o file]      выгрузить заказы (-format ndjson|csv|parquet)
+
+  фильтры: -customer, -track, -locale, -service, -from, -to (RFC3339), -limit, -offset
+
+Кэш работающего сервиса:
+  cache stats [-addr url] [-token t]
+  cache warm [-limit n] [-addr url] [-token t]
+
+Kafka:
+  consumer lag [-group name]
+  dlq list [-from earliest] [-limit 20] [-v]
+  dlq redrive [-from earliest] [-error substr] [-offsets p:o,...] [-dry-run]
+
+Миграции:
+  migrate up | down [-steps 1] | version | force <version> [-dir DIR]
+`
+
+type command func(ctx context.Context, cfg *config.Config, args []string) error
+
+var commands = map[string]command{
+	"get":      runGet,
+	"list":     runList,
+	"validate": runValidate,
+	"import":   runImport,
+	"export":   runExport,
+	"cache":    runCache,
+	"consumer": runConsumer,
+	"dlq":      runDLQ,
+	"migrate":  runMigrate,
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cfg := config.LoadConfig()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	if err := cmd(ctx, cfg, os.Args[2:]); err != nil {
+		log.Fatalf("orderctl %s: %v", os.Args[1], err)
+	}
+}
+
+// subcommand разбирает вложенную команду вида "cache stats"
+func subcommand(args []string, names ...string) (string, []string, error) {
+	if len(args) == 0 {
+		return "", nil, fmt.Errorf("want one of %v", names)
+	}
+	for _, name := range names {
+		if args[0] == name {
+			return name, args[1:], nil
+		}
+	}
+	return "", nil, fmt.Errorf("unknown subcommand %q, want one of %v", args[0], names)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"L0-wb/config"
+	"L0-wb/internal/db"
+	"L0-wb/internal/migrate"
+	"L0-wb/migrations"
+	"context"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"strconv"
+)
+
+func runMigrate(ctx context.Context, cfg *config.Config, args []string) error {
+	name, args, err := subcommand(args, "up", "down", "version", "force")
+	if err != nil {
+		return err
+	}
+
+	var force uint64
+	if name == "force" {
+		var v string
+		if v, args = positional(args); v == "" {
+			return fmt.Errorf("usage: orderctl migrate force <version>")
+		}
+		if force, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return fmt.Errorf("invalid version %q", v)
+		}
+	}
+
+	flags := flag.NewFlagSet("migrate "+name, flag.ExitOnError)
+	dir := flags.String("dir", "", "каталог с миграциями (по умолчанию встроенные в бинарник)")
+	steps := flags.Int("steps", 1, "down: сколько миграций откатить (0 - все)")
+	_ = flags.Parse(args)
+
+	var fsys fs.FS = migrations.FS
+	if *dir != "" {
+		fsys = os.DirFS(*dir)
+	}
+
+	sqlDB := db.NewDB(cfg)
+	defer sqlDB.Close()
+
+	m, err := migrate.New(sqlDB, fsys)
+	if err != nil {
+		return err
+	}
+
+	switch name {
+	case "up":
+		applied, err := m.Up(ctx)
+		for _, mig := range applied {
+			log.Printf("applied %d_%s", mig.Version, mig.Name)
+		}
+		if err != nil {
+			return err
+		}
+	case "down":
+		reverted, err := m.Down(ctx, *steps)
+		for _, mig := range reverted {
+			log.Printf("reverted %d_%s", mig.Version, mig.Name)
+		}
+		if err != nil {
+			return err
+		}
+	case "force":
+		if err := m.Force(ctx, force); err != nil {
+			return err
+		}
+	}
+
+	version, dirty, err := m.Version(ctx)
+	if err != nil {
+		return err
+	}
+	latest := migrate.NoVersion
+	if all := m.Migrations(); len(all) > 0 {
+		latest = all[len(all)-1].Version
+	}
+	log.Printf("schema version %d (dirty=%t), latest %d", version, dirty, latest)
+	return nil
+}
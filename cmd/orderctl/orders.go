@@ -0,0 +1,260 @@
+package main
+
+import (
+	"L0-wb/config"
+	"L0-wb/internal/db"
+	"L0-wb/internal/export"
+	"L0-wb/internal/models"
+	"L0-wb/internal/repo"
+	"L0-wb/internal/service"
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+func newService(cfg *config.Config) (service.Service, func(), error) {
+	pgRepo := repo.NewRepo(db.NewDB(cfg))
+	svc, err := service.NewService(pgRepo)
+	if err != nil {
+		pgRepo.Close()
+		return nil, nil, fmt.Errorf("failed to initialize service: %w", err)
+	}
+	return svc, func() {
+		svc.Close()
+		pgRepo.Close()
+	}, nil
+}
+
+// positional отделяет первый позиционный аргумент, чтобы флаги можно было
+// писать и до, и после него: "import orders.ndjson -batch 10"
+func positional(args []string) (string, []string) {
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		return args[0], args[1:]
+	}
+	return "", args
+}
+
+func runGet(ctx context.Context, cfg *config.Config, args []string) error {
+	uid, _ := positional(args)
+	if uid == "" {
+		return fmt.Errorf("usage: orderctl get <uid>")
+	}
+
+	svc, closeSvc, err := newService(cfg)
+	if err != nil {
+		return err
+	}
+	defer closeSvc()
+
+	order, err := svc.GetOrderByUID(ctx, uid)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(order)
+}
+
+// filterFlags регистрирует общие флаги фильтра для list и export
+func filterFlags(fs *flag.FlagSet, limit int) func() (models.OrderFilter, error) {
+	customer := fs.String("customer", "", "customer_id")
+	track := fs.String("track", "", "track_number")
+	locale := fs.String("locale", "", "locale")
+	deliveryService := fs.String("service", "", "delivery_service")
+	from := fs.String("from", "", "date_created не раньше, RFC3339")
+	to := fs.String("to", "", "date_created раньше, RFC3339")
+	limitFlag := fs.Int("limit", limit, "максимум заказов (0 - без ограничения)")
+	offset := fs.Int("offset", 0, "пропустить заказов")
+
+	return func() (models.OrderFilter, error) {
+		f := models.OrderFilter{
+			CustomerID:      *customer,
+			TrackNumber:     *track,
+			Locale:          *locale,
+			DeliveryService: *deliveryService,
+			Limit:           *limitFlag,
+			Offset:          *offset,
+		}
+		var err error
+		if *from != "" {
+			if f.CreatedFrom, err = time.Parse(time.RFC3339, *from); err != nil {
+				return f, fmt.Errorf("invalid -from: %w", err)
+			}
+		}
+		if *to != "" {
+			if f.CreatedTo, err = time.Parse(time.RFC3339, *to); err != nil {
+				return f, fmt.Errorf("invalid -to: %w", err)
+			}
+		}
+		return f, nil
+	}
+}
+
+func runList(ctx context.Context, cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	filter := filterFlags(fs, models.DefaultListLimit)
+	asJSON := fs.Bool("json", false, "вывести NDJSON вместо таблицы")
+	_ = fs.Parse(args)
+
+	f, err := filter()
+	if err != nil {
+		return err
+	}
+
+	svc, closeSvc, err := newService(cfg)
+	if err != nil {
+		return err
+	}
+	defer closeSvc()
+
+	orders, err := svc.ListOrders(ctx, f)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		for i := range orders {
+			if err := enc.Encode(&orders[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ORDER_UID\tTRACK\tCUSTOMER\tLOCALE\tITEMS\tAMOUNT\tCREATED")
+	for _, o := range orders {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%d %s\t%s\n",
+			o.OrderUID, o.TrackNumber, o.CustomerID, o.Locale, len(o.Items),
+			o.Payment.Amount, o.Payment.Currency, o.DateCreated.Format(time.RFC3339))
+	}
+	return tw.Flush()
+}
+
+func openInput(path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	return os.Open(path)
+}
+
+// decodeOrders читает поток JSON-значений: NDJSON или один (в том числе
+// многострочный) заказ. fn получает порядковый номер заказа начиная с 1.
+func decodeOrders(r io.Reader, fn func(n int, order *models.Order) error) error {
+	dec := json.NewDecoder(bufio.NewReader(r))
+	for n := 1; ; n++ {
+		var order models.Order
+		err := dec.Decode(&order)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			// после синтаксической ошибки продолжить разбор потока нельзя
+			return fmt.Errorf("order #%d: %w", n, err)
+		}
+		if err := fn(n, &order); err != nil {
+			return err
+		}
+	}
+}
+
+func runValidate(_ context.Context, _ *config.Config, args []string) error {
+	path, args := positional(args)
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	totals := fs.Bool("totals", false, "сверять суммы оплаты с товарами (сервис их не сверяет)")
+	_ = fs.Parse(args)
+	if path == "" {
+		return fmt.Errorf("usage: orderctl validate <file|-> [-totals]")
+	}
+	in, err := openInput(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	total, invalid := 0, 0
+	err = decodeOrders(in, func(n int, order *models.Order) error {
+		total++
+		err := order.Validate()
+		if err == nil && *totals {
+			err = order.CheckTotals()
+		}
+		if err != nil {
+			invalid++
+			fmt.Printf("#%d %s: %v\n", n, order.OrderUID, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("checked %d orders, %d invalid\n", total, invalid)
+	if invalid > 0 {
+		return fmt.Errorf("%d of %d orders are invalid", invalid, total)
+	}
+	return nil
+}
+
+func runExport(ctx context.Context, cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	filter := filterFlags(fs, 0)
+	output := fs.String("o", "-", "файл выгрузки (- для stdout)")
+	formatName := fs.String("format", "", "ndjson, csv или parquet (по умолчанию по расширению -o, иначе ndjson)")
+	_ = fs.Parse(args)
+
+	f, err := filter()
+	if err != nil {
+		return err
+	}
+	if *formatName == "" && *output != "-" {
+		*formatName = strings.TrimPrefix(filepath.Ext(*output), ".")
+	}
+	format, err := export.ParseFormat(*formatName)
+	if err != nil {
+		return err
+	}
+
+	svc, closeSvc, err := newService(cfg)
+	if err != nil {
+		return err
+	}
+	defer closeSvc()
+
+	out := os.Stdout
+	if *output != "-" {
+		if out, err = os.Create(*output); err != nil {
+			return err
+		}
+		defer out.Close()
+	}
+	w, err := export.NewWriter(out, format)
+	if err != nil {
+		return err
+	}
+
+	total := 0
+	err = svc.StreamOrders(ctx, f, func(o *models.Order) error {
+		total++
+		return w.Write(o)
+	})
+	if err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	log.Printf("exported %d orders as %s", total, format)
+	return nil
+}
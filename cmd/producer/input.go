@@ -0,0 +1,48 @@
+package main
+
+import (
+	"L0-wb/internal/capture"
+	"L0-wb/internal/generator"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+)
+
+// openInput открывает NDJSON-файл; "-" означает stdin
+func openInput(path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	return os.Open(path)
+}
+
+// fileSource отдаёт записи NDJSON по порядку, выдерживая интервалы pacer-а.
+// Файл читает один отправитель, чтобы сохранить исходный порядок.
+func fileSource(ctx context.Context, r *capture.Reader, pacer *capture.Pacer, skipped *int) func(int) func() (generator.Message, bool) {
+	return func(int) func() (generator.Message, bool) {
+		return func() (generator.Message, bool) {
+			for {
+				rec, err := r.Next()
+				if errors.Is(err, io.EOF) {
+					return generator.Message{}, false
+				}
+				var lineErr *capture.LineError
+				if errors.As(err, &lineErr) {
+					*skipped++
+					log.Printf("skip %v", lineErr)
+					continue
+				}
+				if err != nil {
+					log.Printf("read input: %v", err)
+					return generator.Message{}, false
+				}
+				if !pacer.Wait(ctx, rec.Time) {
+					return generator.Message{}, false
+				}
+				return generator.Message{Key: rec.Key, Value: rec.Value}, true
+			}
+		}
+	}
+}
@@ -2,17 +2,58 @@ package main
 
 import (
 	"L0-wb/config"
+	"L0-wb/internal/capture"
+	"L0-wb/internal/generator"
 	"L0-wb/internal/kafka"
+	"L0-wb/internal/loadgen"
 	"context"
+	"flag"
 	"log"
-	"os"
 	"os/signal"
+	"strings"
+	"sync"
 	"syscall"
+
+	kafkago "github.com/segmentio/kafka-go"
 )
 
 func main() {
+	rate := flag.Float64("rate", 0.5, "сообщений в секунду (0 - без ограничения)")
+	count := flag.Int("count", 0, "всего сообщений (0 - без ограничения)")
+	duration := flag.Duration("duration", 0, "длительность работы (0 - без ограничения)")
+	writers := flag.Int("writers", 1, "количество параллельных отправителей")
+	seed := flag.Int64("seed", 0, "seed генератора (0 - случайный)")
+	localeList := flag.String("locales", "ru=70,en=30", "веса локалей покупателей: "+strings.Join(generator.Locales(), ", "))
+	repeatRate := flag.Float64("repeat-customers", generator.DefaultConfig().RepeatCustomerRate, "доля заказов от повторных покупателей")
+	topic := flag.String("topic", "", "топик Kafka (по умолчанию KAFKA_TOPIC)")
+	faultRate := flag.Float64("fault-rate", 0, "доля испорченных сообщений от 0 до 1")
+	faultList := flag.String("faults", "all", "типы ошибок через запятую: "+faultNames())
+	oversize := flag.Int("oversize-bytes", generator.DefaultOversizeBytes, "размер payload для oversized_payload")
+	input := flag.String("input", "", "NDJSON с заказами вместо генератора (\"-\" - stdin)")
+	speed := flag.Float64("speed", 1, "ускорение исходных интервалов из -input (0 - без пауз, действует -rate)")
+	rewriteUIDs := flag.Bool("rewrite-uids", false, "заменить order_uid из -input новыми UUID")
+	rewriteTimes := flag.Bool("rewrite-times", false, "сдвинуть date_created и payment_dt из -input к текущему времени")
+	flag.Parse()
+
+	set := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	if *faultRate < 0 || *faultRate > 1 {
+		log.Fatalf("fault-rate must be in [0, 1], got %v", *faultRate)
+	}
+	faults, err := generator.ParseFaults(*faultList)
+	if err != nil {
+		log.Fatalf("invalid -faults: %v", err)
+	}
+	localeWeights, err := generator.ParseLocales(*localeList)
+	if err != nil {
+		log.Fatalf("invalid -locales: %v", err)
+	}
 
 	cfg := config.LoadConfig()
+	if *topic != "" {
+		cfg.Kafka.Topic = *topic
+	}
 	log.Println("config initialized")
 
 	prdcr := kafka.NewProducer(*cfg)
@@ -20,26 +61,106 @@ func main() {
 	defer prdcr.Close()
 	log.Println("producer initialized")
 
-	ctx, cancel := context.WithCancel(context.Background())
+	// Останавливаемся по сигналу ОС, а также по -count / -duration
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
 
-	// Запускаем продюсера в отдельной горутине
-	go func() {
-		err := prdcr.RunProducer(ctx)
+	// Каждый отправитель получает свой генератор: seed+номер даёт воспроизводимый поток
+	var source loadgen.SourceFunc[generator.Message] = func(writer int) func() (generator.Message, bool) {
+		genCfg := generator.DefaultConfig()
+		genCfg.Seed = *seed
+		if genCfg.Seed != 0 {
+			genCfg.Seed += int64(writer)
+		}
+		genCfg.Locales = localeWeights
+		genCfg.RepeatCustomerRate = *repeatRate
+		gen := generator.New(genCfg)
+		injector := generator.NewFaultInjector(gen.Faker(), *faultRate, faults).WithOversizeBytes(*oversize)
+		return func() (generator.Message, bool) {
+			msg, err := injector.Next(gen.Order())
+			if err != nil {
+				log.Printf("generate message: %v", err)
+				return generator.Message{}, false
+			}
+			return msg, true
+		}
+	}
+
+	skipped := 0
+	if *input != "" {
+		in, err := openInput(*input)
 		if err != nil {
-			log.Printf("producer stopped: %v", err)
+			log.Fatalf("open input: %v", err)
+		}
+		defer in.Close()
+
+		// порядок и интервалы записи сохраняет только один отправитель без -rate
+		if *writers != 1 {
+			log.Fatalf("-writers %d cannot be used with -input: the capture is replayed by one writer", *writers)
+		}
+		if *speed > 0 && set["rate"] {
+			log.Fatalf("-rate cannot be used with -input and -speed %v: pass -speed 0 to limit the rate", *speed)
 		}
-	}()
+		reader := capture.NewReader(in, capture.Options{RewriteUIDs: *rewriteUIDs, RewriteTimes: *rewriteTimes})
+		source = fileSource(ctx, reader, capture.NewPacer(*speed), &skipped)
+		if *speed > 0 {
+			*rate = 0
+		}
+		log.Printf("replaying %s: speed=%.2f rewrite-uids=%t rewrite-times=%t", *input, *speed, *rewriteUIDs, *rewriteTimes)
+	}
+
+	// Сколько испорченных сообщений каждого типа ушло в топик
+	var mu sync.Mutex
+	sentFaults := make(map[generator.Fault]int)
+	// Сгенерированные заказы помечаются текущей версией схемы, записи из -input
+	// уходят без заголовков: версию по телу определяет консьюмер
+	send := func(ctx context.Context, msg generator.Message) error {
+		km := kafkago.Message{Key: []byte(msg.Key), Value: msg.Value}
+		if *input == "" {
+			km.Headers = append(km.Headers, kafka.CurrentVersionHeader())
+		}
+		if msg.Fault != generator.FaultNone {
+			km.Headers = append(km.Headers, kafkago.Header{Key: generator.FaultHeader, Value: []byte(msg.Fault)})
+		}
+		if err := prdcr.SendMessage(ctx, km); err != nil {
+			return err
+		}
+		if msg.Fault != generator.FaultNone {
+			mu.Lock()
+			sentFaults[msg.Fault]++
+			mu.Unlock()
+		}
+		return nil
+	}
+
+	log.Printf("sending to topic %s: rate=%.2f/s count=%d duration=%s writers=%d seed=%d fault-rate=%.2f",
+		cfg.Kafka.Topic, *rate, *count, *duration, *writers, *seed, *faultRate)
 
-	// Канал чтения сигналов ОС
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	summary := loadgen.Run(ctx, loadgen.Config{
+		Rate:     *rate,
+		Count:    *count,
+		Duration: *duration,
+		Writers:  *writers,
+	}, source, send)
 
-	// Ждём  команду сигнала или пока контекст не умрет
-	select {
-	case <-quit:
-	case <-ctx.Done():
+	log.Printf("producer summary: %s", summary)
+	if skipped > 0 {
+		log.Printf("input lines skipped: %d", skipped)
+	}
+	for _, f := range generator.AllFaults {
+		if n := sentFaults[f]; n > 0 {
+			log.Printf("faults sent: %s=%d", f, n)
+		}
 	}
+	if ctx.Err() != nil {
+		log.Println("shutting down gracefully...")
+	}
+}
 
-	log.Println("shutting down gracefully...")
+func faultNames() string {
+	names := make([]string, len(generator.AllFaults))
+	for i, f := range generator.AllFaults {
+		names[i] = string(f)
+	}
+	return strings.Join(names, ",")
 }
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"L0-wb/config"
+	"L0-wb/internal/db"
+	"L0-wb/internal/kafka"
+	"L0-wb/internal/repo"
+	"L0-wb/internal/service"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+)
+
+const usage = `Использование:
+  replay reset -to <spec> [-partitions 0,1] [-group name] [-dry-run]
+      сдвигает смещения consumer group (сервис должен быть остановлен)
+  replay run -from <spec> [-to <spec>] [-partitions 0,1]
+      перечитывает диапазон [from, to) и сохраняет заказы, не трогая смещения группы
+
+<spec>: earliest | latest | <RFC3339 time> | <partition:offset,...> | <offset>
+        например 2025-09-01T10:00:00Z или 0:1500,1:1320;
+        одно <offset> допустимо только с одной партицией в -partitions
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cfg := config.LoadConfig()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	var err error
+	switch os.Args[1] {
+	case "reset":
+		err = runReset(ctx, cfg, os.Args[2:])
+	case "run":
+		err = runReplay(ctx, cfg, os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatalf("replay %s: %v", os.Args[1], err)
+	}
+}
+
+func runReset(ctx context.Context, cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("reset", flag.ExitOnError)
+	to := fs.String("to", "", "целевая позиция: earliest, latest, смещение или время RFC3339")
+	partitionsStr := fs.String("partitions", "", "список партиций через запятую (по умолчанию все)")
+	group := fs.String("group", cfg.Kafka.Group, "consumer group")
+	dryRun := fs.Bool("dry-run", false, "только показать новые смещения")
+	_ = fs.Parse(args)
+
+	spec, err := kafka.ParseOffsetSpec(*to)
+	if err != nil {
+		return err
+	}
+	partitions, err := kafka.ParsePartitions(*partitionsStr)
+	if err != nil {
+		return err
+	}
+
+	groupCfg := *cfg
+	groupCfg.Kafka.Group = *group
+	admin := kafka.NewAdmin(groupCfg)
+
+	var offsets map[int]int64
+	if *dryRun {
+		offsets, err = admin.ResolveOffsets(ctx, partitions, spec)
+	} else {
+		offsets, err = admin.ResetGroupOffsets(ctx, partitions, spec)
+	}
+	if err != nil {
+		return err
+	}
+
+	action := "reset"
+	if *dryRun {
+		action = "would reset"
+	}
+	for _, p := range sortedPartitions(offsets) {
+		log.Printf("%s group %s topic %s partition %d to offset %d", action, *group, cfg.Kafka.Topic, p, offsets[p])
+	}
+	return nil
+}
+
+func runReplay(ctx context.Context, cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	from := fs.String("from", "", "начало диапазона: earliest, смещение или время RFC3339")
+	to := fs.String("to", "latest", "конец диапазона (не включительно)")
+	partitionsStr := fs.String("partitions", "", "список партиций через запятую (по умолчанию все)")
+	_ = fs.Parse(args)
+
+	fromSpec, err := kafka.ParseOffsetSpec(*from)
+	if err != nil {
+		return err
+	}
+	toSpec, err := kafka.ParseOffsetSpec(*to)
+	if err != nil {
+		return err
+	}
+	partitions, err := kafka.ParsePartitions(*partitionsStr)
+	if err != nil {
+		return err
+	}
+
+	sqlDB := db.NewDB(cfg)
+	pgRepo := repo.NewRepo(sqlDB)
+	defer pgRepo.Close()
+
+	svc, err := service.NewService(pgRepo)
+	if err != nil {
+		return fmt.Errorf("failed to initialize service: %w", err)
+	}
+	defer svc.Close()
+
+	replayer := kafka.NewReplayer(*cfg, svc)
+	defer replayer.Close()
+
+	log.Printf("replaying topic %s from %s to %s", cfg.Kafka.Topic, fromSpec, toSpec)
+	stats, err := replayer.Replay(ctx, partitions, fromSpec, toSpec)
+	log.Printf("replay finished: messages=%d saved=%d failed=%d", stats.Messages, stats.Saved, stats.Failed)
+	return err
+}
+
+func sortedPartitions(offsets map[int]int64) []int {
+	partitions := make([]int, 0, len(offsets))
+	for p := range offsets {
+		partitions = append(partitions, p)
+	}
+	sort.Ints(partitions)
+	return partitions
+}
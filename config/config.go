@@ -15,12 +15,21 @@ type Config struct {
 	Postgres   Postgres
 	Cache      Cache
 	Kafka      Kafka
+	Outbox     Outbox
+	Webhook    Webhook
+	GRPC       GRPC
 }
 
 type HTTPServer struct {
 	Host    string
 	Port    int
 	Timeout time.Duration
+	// Адрес API для страницы index.html; пустой - /api/v1 того же хоста
+	PublicAPIURL string
+	// Отдавать web/ с диска вместо встроенных файлов (для разработки)
+	WebDir string
+	// Bearer-токен служебных ручек (/admin/...); пустой отключает их
+	AdminToken string
 }
 
 type Postgres struct {
@@ -29,6 +38,8 @@ type Postgres struct {
 	User     string
 	Password string
 	Database string
+	// Применять встроенные миграции при старте сервиса
+	AutoMigrate bool
 }
 
 type Cache struct {
@@ -36,10 +47,48 @@ type Cache struct {
 }
 
 type Kafka struct {
-	Host  string
-	Port  int
-	Topic string
-	Group string
+	Host     string
+	Port     int
+	Topic    string
+	Group    string
+	DLQTopic string
+	// Реестр схем для Confluent wire format: HTTP URL или локальная директория
+	SchemaRegistryURL string
+	SchemaRegistryDir string
+	// Пул обработки: число воркеров и предел сообщений в обработке
+	Workers     int
+	MaxInFlight int
+	// Микро-пачки: размер (1 - без пачек) и максимальное время накопления
+	BatchSize     int
+	BatchInterval time.Duration
+	// Сообщения крупнее лимита отправляются в DLQ без декодирования
+	MaxMessageBytes int
+}
+
+// Outbox - публикация событий о сохранённых заказах
+type Outbox struct {
+	Enabled   bool
+	Topic     string
+	BatchSize int
+	Interval  time.Duration
+}
+
+// GRPC - API заказов для внутренних сервисов; при Enabled нужен AuthToken (GRPC_AUTH_TOKEN):
+// без него Validate не даёт запустить сервис
+type GRPC struct {
+	Enabled   bool
+	Host      string
+	Port      int
+	AuthToken string
+}
+
+// Webhook - доставка событий о заказах на адреса партнёров
+type Webhook struct {
+	Enabled     bool
+	BatchSize   int
+	Interval    time.Duration
+	Timeout     time.Duration
+	MaxAttempts int
 }
 
 // Подгружаем .env, если есть
@@ -51,6 +100,10 @@ func LoadConfig() *Config {
 			Host:    getEnv("HTTP_HOST", "localhost"),
 			Port:    getEnvAsInt("HTTP_PORT", 8081),
 			Timeout: getEnvAsDuration("HTTP_TIMEOUT", 5*time.Second),
+
+			PublicAPIURL: getEnv("HTTP_PUBLIC_API_URL", ""),
+			WebDir:       getEnv("HTTP_WEB_DIR", ""),
+			AdminToken:   getEnv("HTTP_ADMIN_TOKEN", ""),
 		},
 		Postgres: Postgres{
 			Host:     getEnv("POSTGRES_HOST", "localhost"),
@@ -58,15 +111,48 @@ func LoadConfig() *Config {
 			User:     getEnv("POSTGRES_USER", "wb_tech_user"),
 			Password: getEnv("POSTGRES_PASSWORD", "12345678"),
 			Database: getEnv("POSTGRES_DATABASE", "wb_tech_demo_service"),
+
+			AutoMigrate: getEnvAsBool("POSTGRES_AUTO_MIGRATE", false),
 		},
 		Cache: Cache{
 			StartupSize: getEnvAsInt("CACHE_STARTUP_SIZE", 10),
 		},
 		Kafka: Kafka{
-			Host:  getEnv("KAFKA_HOST", "localhost"),
-			Port:  getEnvAsInt("KAFKA_PORT", 9092),
-			Topic: getEnv("KAFKA_TOPIC", "wb-orders"),
-			Group: getEnv("KAFKA_GROUP", "wb-tech-demo-service"),
+			Host:     getEnv("KAFKA_HOST", "localhost"),
+			Port:     getEnvAsInt("KAFKA_PORT", 9092),
+			Topic:    getEnv("KAFKA_TOPIC", "wb-orders"),
+			Group:    getEnv("KAFKA_GROUP", "wb-tech-demo-service"),
+			DLQTopic: getEnv("KAFKA_DLQ_TOPIC", "wb-orders-dlq"),
+
+			SchemaRegistryURL: getEnv("SCHEMA_REGISTRY_URL", ""),
+			SchemaRegistryDir: getEnv("SCHEMA_REGISTRY_DIR", ""),
+
+			Workers:     getEnvAsInt("KAFKA_WORKERS", 4),
+			MaxInFlight: getEnvAsInt("KAFKA_MAX_IN_FLIGHT", 100),
+
+			BatchSize:     getEnvAsInt("KAFKA_BATCH_SIZE", 1),
+			BatchInterval: getEnvAsDuration("KAFKA_BATCH_INTERVAL", 200*time.Millisecond),
+
+			MaxMessageBytes: getEnvAsInt("KAFKA_MAX_MESSAGE_BYTES", 512*1024),
+		},
+		Outbox: Outbox{
+			Enabled:   getEnvAsBool("OUTBOX_ENABLED", false),
+			Topic:     getEnv("OUTBOX_TOPIC", "wb-orders-events"),
+			BatchSize: getEnvAsInt("OUTBOX_BATCH_SIZE", 100),
+			Interval:  getEnvAsDuration("OUTBOX_INTERVAL", time.Second),
+		},
+		Webhook: Webhook{
+			Enabled:     getEnvAsBool("WEBHOOK_ENABLED", false),
+			BatchSize:   getEnvAsInt("WEBHOOK_BATCH_SIZE", 50),
+			Interval:    getEnvAsDuration("WEBHOOK_INTERVAL", time.Second),
+			Timeout:     getEnvAsDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+			MaxAttempts: getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", 8),
+		},
+		GRPC: GRPC{
+			Enabled:   getEnvAsBool("GRPC_ENABLED", false),
+			Host:      getEnv("GRPC_HOST", "localhost"),
+			Port:      getEnvAsInt("GRPC_PORT", 9091),
+			AuthToken: getEnv("GRPC_AUTH_TOKEN", ""),
 		},
 	}
 
@@ -112,6 +198,16 @@ func getEnvAsInt(key string, defaultVal int) int {
 	return defaultVal
 }
 
+func getEnvAsBool(key string, defaultVal bool) bool {
+	if valStr, exists := os.LookupEnv(key); exists {
+		if val, err := strconv.ParseBool(valStr); err == nil {
+			return val
+		}
+		log.Printf("не удалось преобразовать %s=%s в логическое значение, используется значение по умолчанию %t", key, valStr, defaultVal)
+	}
+	return defaultVal
+}
+
 func getEnvAsDuration(key string, defaultVal time.Duration) time.Duration {
 	if valStr, exists := os.LookupEnv(key); exists {
 		if val, err := time.ParseDuration(valStr); err == nil {
@@ -122,6 +218,24 @@ func getEnvAsDuration(key string, defaultVal time.Duration) time.Duration {
 	return defaultVal
 }
 
+// GetStatsCacheTTL - сколько сервис отдаёт посчитанную статистику без обращения к БД
+func GetStatsCacheTTL() time.Duration {
+	_ = godotenv.Load()
+	return getEnvAsDuration("STATS_CACHE_TTL", 30*time.Second)
+}
+
+// GetOutboxEnabled - пишет ли сервис события order.saved в outbox
+func GetOutboxEnabled() bool {
+	_ = godotenv.Load()
+	return getEnvAsBool("OUTBOX_ENABLED", false)
+}
+
+// GetWebhookEnabled - ставит ли сервис сохранённые заказы в очередь вебхуков
+func GetWebhookEnabled() bool {
+	_ = godotenv.Load()
+	return getEnvAsBool("WEBHOOK_ENABLED", false)
+}
+
 func GetCacheStartupSize() int {
 	if err := godotenv.Load(); err != nil {
 		log.Println("Warning: .env file not found, using environment variables")
@@ -151,6 +265,28 @@ func (c *Config) Validate() error {
 	if c.Kafka.Port <= 0 {
 		return fmt.Errorf("invalid Kafka port: %d", c.Kafka.Port)
 	}
+	if c.Kafka.Workers <= 0 {
+		return fmt.Errorf("invalid Kafka workers count: %d", c.Kafka.Workers)
+	}
+	if c.Kafka.MaxInFlight <= 0 {
+		return fmt.Errorf("invalid Kafka max in-flight: %d", c.Kafka.MaxInFlight)
+	}
+	if c.Kafka.BatchSize <= 0 {
+		return fmt.Errorf("invalid Kafka batch size: %d", c.Kafka.BatchSize)
+	}
+	if c.Outbox.Enabled && c.Outbox.BatchSize <= 0 {
+		return fmt.Errorf("invalid outbox batch size: %d", c.Outbox.BatchSize)
+	}
+	if c.Webhook.Enabled && (c.Webhook.BatchSize <= 0 || c.Webhook.MaxAttempts <= 0) {
+		return fmt.Errorf("invalid webhook batch size %d or max attempts %d", c.Webhook.BatchSize, c.Webhook.MaxAttempts)
+	}
+	if c.GRPC.Enabled && (c.GRPC.Port <= 0 || c.GRPC.Port == c.HTTPServer.Port) {
+		return fmt.Errorf("invalid gRPC port: %d", c.GRPC.Port)
+	}
+	// CreateOrder без токена открыт любому, кто дотянется до порта
+	if c.GRPC.Enabled && c.GRPC.AuthToken == "" {
+		return fmt.Errorf("GRPC_AUTH_TOKEN is required when gRPC is enabled")
+	}
 	if c.Cache.StartupSize <= 0 {
 		return fmt.Errorf("invalid cache startup size: %d", c.Cache.StartupSize)
 	}
@@ -32,6 +32,19 @@ func TestLoadConfig(t *testing.T) {
 	assert.Equal(t, 8081, cfg.HTTPServer.Port)        // default value
 }
 
+func TestValidate_GRPCRequiresToken(t *testing.T) {
+	os.Clearenv()
+	cfg := LoadConfig()
+	assert.False(t, cfg.GRPC.Enabled)
+	assert.NoError(t, cfg.Validate())
+
+	cfg.GRPC.Enabled = true
+	assert.Error(t, cfg.Validate())
+
+	cfg.GRPC.AuthToken = "secret"
+	assert.NoError(t, cfg.Validate())
+}
+
 func TestGetDBConnStr(t *testing.T) {
 	cfg := Postgres{
 		Host:     "localhost",
@@ -14,14 +14,27 @@ type cacheItem struct {
 type Cache interface {
 	Set(key string, order *models.Order)
 	Get(key string) (*models.Order, bool)
+	Stats() Stats
 	Close()
 }
 
+// Stats - счётчики кэша с момента запуска
+type Stats struct {
+	Size      int    `json:"size"`
+	Capacity  int    `json:"capacity"`
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"`
+}
+
 type lruCache struct {
-	capacity int
-	items    map[string]*list.Element
-	queue    *list.List
-	mutex    sync.RWMutex
+	capacity  int
+	items     map[string]*list.Element
+	queue     *list.List
+	mutex     sync.Mutex
+	hits      uint64
+	misses    uint64
+	evictions uint64
 }
 
 func NewCache(capacity int) Cache {
@@ -53,22 +66,42 @@ func (c *lruCache) Set(key string, order *models.Order) {
 	}
 }
 
+// Get меняет порядок LRU-очереди, поэтому берёт полную блокировку
 func (c *lruCache) Get(key string) (*models.Order, bool) {
-	c.mutex.RLock()
-	defer c.mutex.RUnlock()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
 
 	if elem, exists := c.items[key]; exists {
 		c.queue.MoveToFront(elem)
+		c.hits++
 		return elem.Value.(*cacheItem).value, true
 	}
+	c.misses++
 	return nil, false
 }
 
+func (c *lruCache) Stats() Stats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	stats := Stats{
+		Capacity:  c.capacity,
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+	}
+	if c.queue != nil {
+		stats.Size = c.queue.Len()
+	}
+	return stats
+}
+
 func (c *lruCache) evictOldest() {
 	if elem := c.queue.Back(); elem != nil {
 		c.queue.Remove(elem)
 		item := elem.Value.(*cacheItem)
 		delete(c.items, item.key)
+		c.evictions++
 	}
 }
 
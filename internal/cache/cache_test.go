@@ -71,3 +71,15 @@ func TestCache_Close(t *testing.T) {
 	c := NewCache(10)
 	c.Close() // Should not panic
 }
+
+func TestCache_Stats(t *testing.T) {
+	c := NewCache(2)
+	c.Set("1", &models.Order{OrderUID: "1"})
+	c.Set("2", &models.Order{OrderUID: "2"})
+	c.Set("3", &models.Order{OrderUID: "3"})
+
+	_, _ = c.Get("3")
+	_, _ = c.Get("1")
+
+	assert.Equal(t, Stats{Size: 2, Capacity: 2, Hits: 1, Misses: 1, Evictions: 1}, c.Stats())
+}
@@ -0,0 +1,216 @@
+// Package capture читает заказы из NDJSON для повторной отправки в Kafka.
+//
+// Строка файла - либо заказ в JSON, либо запись захваченного трафика:
+//
+//	{"timestamp":"2025-09-01T10:00:00.123Z","key":"b563feb7b2b84b6test","order":{...}}
+//
+// Время прихода берётся из timestamp записи, а для голого заказа - из date_created.
+package capture
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+)
+
+// MaxLineBytes - предельная длина строки NDJSON
+const MaxLineBytes = 16 << 20
+
+// Record - одно сообщение для отправки
+type Record struct {
+	Line  int
+	Time  time.Time // нулевое, если время не удалось определить
+	Key   string
+	Value []byte
+}
+
+// Options - перезапись полей при чтении
+type Options struct {
+	// RewriteUIDs заменяет order_uid на новые UUID; повторы одного UID
+	// в файле получают один и тот же новый UID
+	RewriteUIDs bool
+	// RewriteTimes сдвигает date_created и payment_dt так, что первая запись
+	// приходится на момент чтения, а интервалы между записями сохраняются
+	RewriteTimes bool
+	Now          func() time.Time
+}
+
+// LineError - строка, которую не удалось разобрать; чтение можно продолжать
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error { return e.Err }
+
+type captureRecord struct {
+	Timestamp time.Time       `json:"timestamp"`
+	Key       string          `json:"key"`
+	Order     json.RawMessage `json:"order"`
+}
+
+type Reader struct {
+	scanner *bufio.Scanner
+	opts    Options
+	line    int
+	uids    map[string]string
+	shift   time.Duration
+	shifted bool
+}
+
+func NewReader(r io.Reader, opts Options) *Reader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), MaxLineBytes)
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	return &Reader{
+		scanner: scanner,
+		opts:    opts,
+		uids:    make(map[string]string),
+	}
+}
+
+// Next возвращает следующую запись, io.EOF в конце ввода
+// или *LineError для строки, которую можно пропустить
+func (r *Reader) Next() (Record, error) {
+	for r.scanner.Scan() {
+		r.line++
+		line := bytes.TrimSpace(r.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		rec, err := r.parse(line)
+		if err != nil {
+			return Record{}, &LineError{Line: r.line, Err: err}
+		}
+		return rec, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return Record{}, err
+	}
+	return Record{}, io.EOF
+}
+
+func (r *Reader) parse(line []byte) (Record, error) {
+	rec := Record{Line: r.line}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return rec, err
+	}
+
+	order := line
+	if _, ok := fields["order"]; ok {
+		var cr captureRecord
+		if err := json.Unmarshal(line, &cr); err != nil {
+			return rec, err
+		}
+		order, rec.Time, rec.Key = cr.Order, cr.Timestamp, cr.Key
+		fields = nil
+		if err := json.Unmarshal(order, &fields); err != nil {
+			return rec, fmt.Errorf("order: %w", err)
+		}
+	}
+
+	uid := stringField(fields, "order_uid")
+	if rec.Key == "" {
+		rec.Key = uid
+	}
+	if rec.Time.IsZero() {
+		rec.Time = timeField(fields, "date_created")
+	}
+
+	if !r.opts.RewriteUIDs && !r.opts.RewriteTimes {
+		// без перезаписи отправляем байты как есть
+		rec.Value = append([]byte(nil), order...)
+		return rec, nil
+	}
+
+	if r.opts.RewriteUIDs && uid != "" {
+		newUID, ok := r.uids[uid]
+		if !ok {
+			newUID = gofakeit.UUID()
+			r.uids[uid] = newUID
+		}
+		if err := setField(fields, "order_uid", newUID); err != nil {
+			return rec, err
+		}
+		if rec.Key == uid {
+			rec.Key = newUID
+		}
+	}
+
+	if r.opts.RewriteTimes && !rec.Time.IsZero() {
+		if !r.shifted {
+			r.shift = r.opts.Now().Sub(rec.Time)
+			r.shifted = true
+		}
+		if err := r.shiftTimes(fields); err != nil {
+			return rec, err
+		}
+		rec.Time = rec.Time.Add(r.shift)
+	}
+
+	value, err := json.Marshal(fields)
+	if err != nil {
+		return rec, err
+	}
+	rec.Value = value
+	return rec, nil
+}
+
+func (r *Reader) shiftTimes(fields map[string]json.RawMessage) error {
+	if created := timeField(fields, "date_created"); !created.IsZero() {
+		if err := setField(fields, "date_created", created.Add(r.shift)); err != nil {
+			return err
+		}
+	}
+
+	raw, ok := fields["payment"]
+	if !ok {
+		return nil
+	}
+	var payment map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &payment); err != nil {
+		return fmt.Errorf("payment: %w", err)
+	}
+	var paymentDt int64
+	if err := json.Unmarshal(payment["payment_dt"], &paymentDt); err != nil || paymentDt == 0 {
+		return nil
+	}
+	if err := setField(payment, "payment_dt", paymentDt+int64(r.shift/time.Second)); err != nil {
+		return err
+	}
+	return setField(fields, "payment", payment)
+}
+
+func stringField(fields map[string]json.RawMessage, name string) string {
+	var s string
+	_ = json.Unmarshal(fields[name], &s)
+	return s
+}
+
+func timeField(fields map[string]json.RawMessage, name string) time.Time {
+	var t time.Time
+	_ = json.Unmarshal(fields[name], &t)
+	return t
+}
+
+func setField(fields map[string]json.RawMessage, name string, value interface{}) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	fields[name] = raw
+	return nil
+}
@@ -0,0 +1,108 @@
+package capture
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const input = `{"order_uid":"uid-1","date_created":"2025-09-01T10:00:00Z","payment":{"payment_dt":1756720800}}
+
+{"timestamp":"2025-09-01T10:00:02Z","key":"uid-2","order":{"order_uid":"uid-2","date_created":"2025-09-01T09:59:59Z"}}
+not json
+{"order_uid":"uid-1","date_created":"2025-09-01T10:00:05Z"}
+`
+
+func readAll(t *testing.T, r *Reader) ([]Record, []*LineError) {
+	t.Helper()
+	var records []Record
+	var lineErrs []*LineError
+	for {
+		rec, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return records, lineErrs
+		}
+		var lineErr *LineError
+		if errors.As(err, &lineErr) {
+			lineErrs = append(lineErrs, lineErr)
+			continue
+		}
+		require.NoError(t, err)
+		records = append(records, rec)
+	}
+}
+
+func TestReader_AsIs(t *testing.T) {
+	records, lineErrs := readAll(t, NewReader(strings.NewReader(input), Options{}))
+
+	require.Len(t, records, 3)
+	require.Len(t, lineErrs, 1)
+	assert.Equal(t, 4, lineErrs[0].Line)
+
+	assert.Equal(t, "uid-1", records[0].Key)
+	assert.Equal(t, time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC), records[0].Time.UTC())
+	assert.Equal(t, strings.SplitN(input, "\n", 2)[0], string(records[0].Value))
+
+	// время записи захвата важнее date_created заказа
+	assert.Equal(t, "uid-2", records[1].Key)
+	assert.Equal(t, time.Date(2025, 9, 1, 10, 0, 2, 0, time.UTC), records[1].Time.UTC())
+	assert.JSONEq(t, `{"order_uid":"uid-2","date_created":"2025-09-01T09:59:59Z"}`, string(records[1].Value))
+}
+
+func TestReader_Rewrite(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := NewReader(strings.NewReader(input), Options{
+		RewriteUIDs:  true,
+		RewriteTimes: true,
+		Now:          func() time.Time { return now },
+	})
+	records, _ := readAll(t, r)
+	require.Len(t, records, 3)
+
+	var first, last struct {
+		OrderUID    string    `json:"order_uid"`
+		DateCreated time.Time `json:"date_created"`
+		Payment     struct {
+			PaymentDt int64 `json:"payment_dt"`
+		} `json:"payment"`
+	}
+	require.NoError(t, json.Unmarshal(records[0].Value, &first))
+	require.NoError(t, json.Unmarshal(records[2].Value, &last))
+
+	assert.NotEqual(t, "uid-1", first.OrderUID)
+	assert.Equal(t, first.OrderUID, last.OrderUID, "повтор UID остаётся повтором")
+	assert.Equal(t, first.OrderUID, records[0].Key)
+
+	assert.True(t, now.Equal(first.DateCreated))
+	assert.Equal(t, now.Unix(), first.Payment.PaymentDt)
+	assert.True(t, now.Add(5*time.Second).Equal(last.DateCreated))
+	assert.True(t, now.Add(2*time.Second).Equal(records[1].Time))
+}
+
+func TestPacer_Wait(t *testing.T) {
+	base := time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	p := NewPacer(10)
+	start := time.Now()
+	require.True(t, p.Wait(ctx, base))
+	require.True(t, p.Wait(ctx, base.Add(time.Second)))
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+
+	unpaced := NewPacer(0)
+	start = time.Now()
+	require.True(t, unpaced.Wait(ctx, base))
+	require.True(t, unpaced.Wait(ctx, base.Add(time.Hour)))
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.False(t, p.Wait(cancelled, base.Add(2*time.Second)))
+}
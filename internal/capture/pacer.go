@@ -0,0 +1,48 @@
+package capture
+
+import (
+	"context"
+	"time"
+)
+
+// Pacer воспроизводит интервалы между записями, ускоренные в speed раз.
+// speed <= 0 отключает паузы.
+type Pacer struct {
+	speed   float64
+	started time.Time
+	first   time.Time
+}
+
+func NewPacer(speed float64) *Pacer {
+	return &Pacer{speed: speed}
+}
+
+// Wait ждёт момента отправки записи со временем t. Записи без времени
+// и записи "из прошлого" относительно первой отправляются сразу.
+func (p *Pacer) Wait(ctx context.Context, t time.Time) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if p.speed <= 0 || t.IsZero() {
+		return true
+	}
+	if p.first.IsZero() {
+		p.first, p.started = t, time.Now()
+		return true
+	}
+
+	offset := time.Duration(float64(t.Sub(p.first)) / p.speed)
+	delay := time.Until(p.started.Add(offset))
+	if delay <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
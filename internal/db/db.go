@@ -3,7 +3,6 @@ package db
 import (
 	"L0-wb/config"
 	"database/sql"
-	"fmt"
 	"log"
 
 	_ "github.com/lib/pq"
@@ -18,6 +17,6 @@ func NewDB(cfg *config.Config) *sql.DB {
 	if err := db.Ping(); err != nil {
 		log.Fatalf("failed to ping DB: %v", err)
 	}
-	fmt.Println("DB connected")
+	log.Println("DB connected")
 	return db
 }
@@ -0,0 +1,61 @@
+package export
+
+import (
+	"L0-wb/internal/models"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+type csvWriter struct {
+	w           *csv.Writer
+	wroteHeader bool
+	record      []string
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w), record: make([]string, len(columns))}
+}
+
+func (w *csvWriter) header() error {
+	if w.wroteHeader {
+		return nil
+	}
+	w.wroteHeader = true
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.name
+	}
+	return w.w.Write(names)
+}
+
+func (w *csvWriter) Write(o *models.Order) error {
+	if err := w.header(); err != nil {
+		return err
+	}
+	return flatten(o, func(it *models.Item) error {
+		for i, c := range columns {
+			switch v := c.value(o, it).(type) {
+			case string:
+				w.record[i] = v
+			case int64:
+				w.record[i] = strconv.FormatInt(v, 10)
+			case time.Time:
+				w.record[i] = v.UTC().Format(time.RFC3339)
+			default:
+				w.record[i] = ""
+			}
+		}
+		return w.w.Write(w.record)
+	})
+}
+
+// Close пишет заголовок даже для пустой выгрузки
+func (w *csvWriter) Close() error {
+	if err := w.header(); err != nil {
+		return err
+	}
+	w.w.Flush()
+	return w.w.Error()
+}
@@ -0,0 +1,211 @@
+package export
+
+import (
+	"L0-wb/internal/models"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// RowError - ошибка в данных одного заказа; чтение можно продолжить
+type RowError struct {
+	Line int
+	Err  error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// CSVReader собирает заказы из плоского CSV выгрузки: подряд идущие строки
+// с одним order_uid - товары одного заказа. Колонки сопоставляются по
+// заголовку, неизвестные игнорируются, отсутствующие остаются пустыми.
+type CSVReader struct {
+	r       *csv.Reader
+	header  []*column // nil - неизвестная колонка
+	uidCol  int
+	next    []string // первая строка следующего заказа
+	nextPos int
+	nextErr error
+}
+
+// NewCSVReader читает заголовок; колонка order_uid обязательна
+func NewCSVReader(r io.Reader) (*CSVReader, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	names, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read csv header: %w", err)
+	}
+
+	byName := make(map[string]*column, len(columns))
+	for i := range columns {
+		byName[columns[i].name] = &columns[i]
+	}
+	c := &CSVReader{r: cr, header: make([]*column, len(names)), uidCol: -1}
+	for i, name := range names {
+		name = strings.TrimSpace(name)
+		c.header[i] = byName[name]
+		if name == "order_uid" {
+			c.uidCol = i
+		}
+	}
+	if c.uidCol < 0 {
+		return nil, errors.New("csv header has no order_uid column")
+	}
+	return c, nil
+}
+
+// Next возвращает следующий заказ и номер строки, с которой он начинается.
+// Ошибка значений заказа возвращается как *RowError; в конце - io.EOF.
+func (c *CSVReader) Next() (*models.Order, int, error) {
+	if c.next == nil && c.nextErr == nil {
+		c.advance()
+	}
+	if c.nextErr != nil {
+		err := c.nextErr
+		c.nextErr = nil
+		return nil, c.nextPos, err
+	}
+
+	first, pos := c.next, c.nextPos
+	uid := first[c.uidCol]
+	rows := [][]string{first}
+	var rowErr error
+	for {
+		c.advance()
+		if c.nextErr != nil {
+			var parseErr *csv.ParseError
+			if errors.As(c.nextErr, &parseErr) && rowErr == nil {
+				// битая строка внутри заказа портит весь заказ
+				rowErr, c.nextErr = c.nextErr, nil
+				continue
+			}
+			break
+		}
+		if c.next[c.uidCol] != uid {
+			break
+		}
+		rows = append(rows, c.next)
+	}
+	if rowErr != nil {
+		return nil, pos, &RowError{Line: pos, Err: rowErr}
+	}
+
+	order, err := c.order(rows)
+	if err != nil {
+		return nil, pos, &RowError{Line: pos, Err: err}
+	}
+	return order, pos, nil
+}
+
+func (c *CSVReader) advance() {
+	c.next = nil
+	record, err := c.r.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			c.nextErr = io.EOF
+			return
+		}
+		line, _ := c.r.FieldPos(0)
+		c.nextPos = line
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			c.nextPos = parseErr.StartLine
+			c.nextErr = &RowError{Line: parseErr.StartLine, Err: err}
+			return
+		}
+		c.nextErr = err
+		return
+	}
+	if c.uidCol >= len(record) {
+		line, _ := c.r.FieldPos(0)
+		c.nextPos, c.nextErr = line, &RowError{Line: line, Err: errors.New("missing order_uid")}
+		return
+	}
+	c.next = record
+	c.nextPos, _ = c.r.FieldPos(0)
+}
+
+// order собирает заказ через JSON: имена колонок повторяют JSON-поля заказа,
+// а delivery_*, payment_* и item_* - поля вложенных объектов
+func (c *CSVReader) order(rows [][]string) (*models.Order, error) {
+	doc := map[string]interface{}{}
+	delivery := map[string]interface{}{}
+	payment := map[string]interface{}{}
+	var items []map[string]interface{}
+
+	for r, row := range rows {
+		item := map[string]interface{}{}
+		for i, col := range c.header {
+			if col == nil || i >= len(row) || row[i] == "" {
+				continue
+			}
+			if !col.item && r > 0 {
+				continue
+			}
+			v, err := csvValue(col, row[i])
+			if err != nil {
+				return nil, err
+			}
+			group, field := jsonField(col.name)
+			switch group {
+			case "delivery":
+				delivery[field] = v
+			case "payment":
+				payment[field] = v
+			case "items":
+				item[field] = v
+			default:
+				doc[field] = v
+			}
+		}
+		if len(item) > 0 {
+			items = append(items, item)
+		}
+	}
+	doc["delivery"], doc["payment"], doc["items"] = delivery, payment, items
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var order models.Order
+	if err := json.Unmarshal(data, &order); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func csvValue(col *column, s string) (interface{}, error) {
+	if col.kind == kindInt {
+		n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid integer %q", col.name, s)
+		}
+		return n, nil
+	}
+	return s, nil
+}
+
+func jsonField(name string) (string, string) {
+	switch {
+	case name == "payment_dt":
+		return "payment", name
+	case strings.HasPrefix(name, "delivery_"):
+		return "delivery", strings.TrimPrefix(name, "delivery_")
+	case strings.HasPrefix(name, "payment_"):
+		return "payment", strings.TrimPrefix(name, "payment_")
+	case strings.HasPrefix(name, "item_"):
+		return "items", strings.TrimPrefix(name, "item_")
+	}
+	return "", name
+}
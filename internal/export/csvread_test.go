@@ -0,0 +1,52 @@
+package export
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSVReader_RoundTrip(t *testing.T) {
+	r, err := NewCSVReader(bytes.NewReader(writeAll(t, CSV)))
+	require.NoError(t, err)
+
+	for i, want := range testOrders() {
+		order, line, err := r.Next()
+		require.NoError(t, err)
+		assert.Equal(t, want, *order)
+		assert.Equal(t, []int{2, 4}[i], line)
+	}
+	_, _, err = r.Next()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestCSVReader_Errors(t *testing.T) {
+	_, err := NewCSVReader(strings.NewReader("track_number,locale\nWBIL1,ru\n"))
+	assert.Error(t, err)
+
+	input := "order_uid,payment_amount,item_name,item_price,unknown\n" +
+		"a,100,x,10,1\n" +
+		"a,100,y,oops,1\n" +
+		"b,200,z,20,1\n"
+	r, err := NewCSVReader(strings.NewReader(input))
+	require.NoError(t, err)
+
+	_, line, err := r.Next()
+	var rowErr *RowError
+	require.True(t, errors.As(err, &rowErr))
+	assert.Equal(t, 2, line)
+	assert.Contains(t, err.Error(), "item_price")
+
+	order, line, err := r.Next()
+	require.NoError(t, err)
+	assert.Equal(t, 4, line)
+	assert.Equal(t, "b", order.OrderUID)
+	assert.Equal(t, 200, order.Payment.Amount)
+	require.Len(t, order.Items, 1)
+	assert.Equal(t, 20, order.Items[0].Price)
+}
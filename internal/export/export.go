@@ -0,0 +1,94 @@
+// Package export пишет заказы в форматы выгрузки для аналитики: NDJSON,
+// плоский CSV и Parquet. Все писатели потоковые: заказ можно отпустить сразу
+// после Write, в памяти держится не больше одной группы строк Parquet.
+package export
+
+import (
+	"L0-wb/internal/models"
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format - формат выгрузки
+type Format string
+
+const (
+	NDJSON  Format = "ndjson"
+	CSV     Format = "csv"
+	Parquet Format = "parquet"
+)
+
+// Formats - поддерживаемые форматы
+var Formats = []Format{NDJSON, CSV, Parquet}
+
+// ParseFormat разбирает имя формата; пустая строка - NDJSON
+func ParseFormat(s string) (Format, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" || s == "json" {
+		return NDJSON, nil
+	}
+	for _, f := range Formats {
+		if Format(s) == f {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown export format %q", s)
+}
+
+// ContentType - MIME-тип для HTTP-ответа
+func (f Format) ContentType() string {
+	switch f {
+	case CSV:
+		return "text/csv; charset=utf-8"
+	case Parquet:
+		return "application/vnd.apache.parquet"
+	default:
+		return "application/x-ndjson"
+	}
+}
+
+// Ext - расширение файла без точки
+func (f Format) Ext() string {
+	return string(f)
+}
+
+// Writer пишет заказы в одном из форматов выгрузки
+type Writer interface {
+	Write(o *models.Order) error
+	// Close дописывает буферы и служебные части формата; нижележащий io.Writer не закрывается
+	Close() error
+}
+
+// NewWriter создаёт писатель формата f поверх w
+func NewWriter(w io.Writer, f Format) (Writer, error) {
+	switch f {
+	case NDJSON:
+		return newNDJSONWriter(w), nil
+	case CSV:
+		return newCSVWriter(w), nil
+	case Parquet:
+		return newParquetWriter(w, DefaultRowGroupRows), nil
+	}
+	return nil, fmt.Errorf("unknown export format %q", f)
+}
+
+type ndjsonWriter struct {
+	buf *bufio.Writer
+	enc *json.Encoder
+}
+
+func newNDJSONWriter(w io.Writer) *ndjsonWriter {
+	buf := bufio.NewWriter(w)
+	return &ndjsonWriter{buf: buf, enc: json.NewEncoder(buf)}
+}
+
+func (w *ndjsonWriter) Write(o *models.Order) error {
+	return w.enc.Encode(o)
+}
+
+func (w *ndjsonWriter) Close() error {
+	return w.buf.Flush()
+}
@@ -0,0 +1,101 @@
+package export
+
+import (
+	"L0-wb/internal/models"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testOrders() []models.Order {
+	created := time.Date(2025, 9, 1, 12, 30, 0, 0, time.UTC)
+	return []models.Order{{
+		OrderUID:    "uid-1",
+		TrackNumber: "WBIL1",
+		Entry:       "WBIL",
+		Delivery:    models.Delivery{Name: "Иван Петров", Phone: "+79991234567", City: "Москва", Email: "ivan@mail.ru"},
+		Payment:     models.Payment{Transaction: "tx-1", Currency: "RUB", Amount: 350, GoodsTotal: 300, DeliveryCost: 50},
+		Items: models.Items{
+			{ChrtID: 1, Price: 100, Name: "Футболка, чёрная", TotalPrice: 100, Brand: "NIKE", Status: 200},
+			{ChrtID: 2, Price: 200, Name: "Джинсы", TotalPrice: 200, Brand: "LEVIS", Status: 200},
+		},
+		Locale:      "ru",
+		CustomerID:  "customer_1",
+		DateCreated: created,
+	}, {
+		OrderUID:    "uid-2",
+		Locale:      "en",
+		Payment:     models.Payment{Currency: "USD"},
+		DateCreated: created.Add(-time.Hour),
+	}}
+}
+
+func writeAll(t *testing.T, f Format) []byte {
+	var out bytes.Buffer
+	w, err := NewWriter(&out, f)
+	require.NoError(t, err)
+	orders := testOrders()
+	for i := range orders {
+		require.NoError(t, w.Write(&orders[i]))
+	}
+	require.NoError(t, w.Close())
+	return out.Bytes()
+}
+
+func TestParseFormat(t *testing.T) {
+	for in, want := range map[string]Format{"": NDJSON, "json": NDJSON, "CSV": CSV, "parquet": Parquet} {
+		f, err := ParseFormat(in)
+		require.NoError(t, err)
+		assert.Equal(t, want, f)
+	}
+	_, err := ParseFormat("xml")
+	assert.Error(t, err)
+}
+
+func TestNDJSONWriter(t *testing.T) {
+	lines := bytes.Split(bytes.TrimSpace(writeAll(t, NDJSON)), []byte("\n"))
+	require.Len(t, lines, 2)
+
+	var o models.Order
+	require.NoError(t, json.Unmarshal(lines[0], &o))
+	assert.Equal(t, testOrders()[0], o)
+}
+
+func TestCSVWriter(t *testing.T) {
+	records, err := csv.NewReader(bytes.NewReader(writeAll(t, CSV))).ReadAll()
+	require.NoError(t, err)
+	// заголовок, две строки товаров первого заказа и одна строка заказа без товаров
+	require.Len(t, records, 4)
+
+	col := make(map[string]int)
+	for i, name := range records[0] {
+		col[name] = i
+	}
+	assert.Equal(t, "uid-1", records[1][col["order_uid"]])
+	assert.Equal(t, "uid-1", records[2][col["order_uid"]])
+	assert.Equal(t, "Футболка, чёрная", records[1][col["item_name"]])
+	assert.Equal(t, "200", records[2][col["item_price"]])
+	assert.Equal(t, "Москва", records[2][col["delivery_city"]])
+	assert.Equal(t, "350", records[1][col["payment_amount"]])
+	assert.Equal(t, "2025-09-01T12:30:00Z", records[1][col["date_created"]])
+
+	assert.Equal(t, "uid-2", records[3][col["order_uid"]])
+	assert.Empty(t, records[3][col["item_price"]])
+}
+
+func TestCSVWriter_Empty(t *testing.T) {
+	var out bytes.Buffer
+	w, err := NewWriter(&out, CSV)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	records, err := csv.NewReader(&out).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "order_uid", records[0][0])
+}
@@ -0,0 +1,105 @@
+package export
+
+import "L0-wb/internal/models"
+
+type columnKind int
+
+const (
+	kindString columnKind = iota
+	kindInt
+	kindTime
+)
+
+// column - колонка плоской выгрузки: одна строка на товар, поля заказа,
+// доставки и оплаты повторяются в каждой строке заказа
+type column struct {
+	name  string
+	kind  columnKind
+	item  bool // пустая у заказа без товаров
+	order func(o *models.Order) interface{}
+	field func(it *models.Item) interface{}
+}
+
+func (c column) value(o *models.Order, it *models.Item) interface{} {
+	if c.item {
+		if it == nil {
+			return nil
+		}
+		return c.field(it)
+	}
+	return c.order(o)
+}
+
+func orderStr(name string, f func(o *models.Order) string) column {
+	return column{name: name, kind: kindString, order: func(o *models.Order) interface{} { return f(o) }}
+}
+
+func orderInt(name string, f func(o *models.Order) int) column {
+	return column{name: name, kind: kindInt, order: func(o *models.Order) interface{} { return int64(f(o)) }}
+}
+
+func itemStr(name string, f func(it *models.Item) string) column {
+	return column{name: name, kind: kindString, item: true, field: func(it *models.Item) interface{} { return f(it) }}
+}
+
+func itemInt(name string, f func(it *models.Item) int) column {
+	return column{name: name, kind: kindInt, item: true, field: func(it *models.Item) interface{} { return int64(f(it)) }}
+}
+
+var columns = []column{
+	orderStr("order_uid", func(o *models.Order) string { return o.OrderUID }),
+	orderStr("track_number", func(o *models.Order) string { return o.TrackNumber }),
+	orderStr("entry", func(o *models.Order) string { return o.Entry }),
+	orderStr("locale", func(o *models.Order) string { return o.Locale }),
+	orderStr("internal_signature", func(o *models.Order) string { return o.InternalSignature }),
+	orderStr("customer_id", func(o *models.Order) string { return o.CustomerID }),
+	orderStr("delivery_service", func(o *models.Order) string { return o.DeliveryService }),
+	orderStr("shardkey", func(o *models.Order) string { return o.Shardkey }),
+	orderInt("sm_id", func(o *models.Order) int { return o.SmID }),
+	{name: "date_created", kind: kindTime, order: func(o *models.Order) interface{} { return o.DateCreated }},
+	orderStr("oof_shard", func(o *models.Order) string { return o.OofShard }),
+
+	orderStr("delivery_name", func(o *models.Order) string { return o.Delivery.Name }),
+	orderStr("delivery_phone", func(o *models.Order) string { return o.Delivery.Phone }),
+	orderStr("delivery_zip", func(o *models.Order) string { return o.Delivery.Zip }),
+	orderStr("delivery_city", func(o *models.Order) string { return o.Delivery.City }),
+	orderStr("delivery_address", func(o *models.Order) string { return o.Delivery.Address }),
+	orderStr("delivery_region", func(o *models.Order) string { return o.Delivery.Region }),
+	orderStr("delivery_email", func(o *models.Order) string { return o.Delivery.Email }),
+
+	orderStr("payment_transaction", func(o *models.Order) string { return o.Payment.Transaction }),
+	orderStr("payment_request_id", func(o *models.Order) string { return o.Payment.RequestID }),
+	orderStr("payment_currency", func(o *models.Order) string { return o.Payment.Currency }),
+	orderStr("payment_provider", func(o *models.Order) string { return o.Payment.Provider }),
+	orderInt("payment_amount", func(o *models.Order) int { return o.Payment.Amount }),
+	orderInt("payment_dt", func(o *models.Order) int { return o.Payment.PaymentDt }),
+	orderStr("payment_bank", func(o *models.Order) string { return o.Payment.Bank }),
+	orderInt("payment_delivery_cost", func(o *models.Order) int { return o.Payment.DeliveryCost }),
+	orderInt("payment_goods_total", func(o *models.Order) int { return o.Payment.GoodsTotal }),
+	orderInt("payment_custom_fee", func(o *models.Order) int { return o.Payment.CustomFee }),
+
+	itemInt("item_chrt_id", func(it *models.Item) int { return it.ChrtID }),
+	itemStr("item_track_number", func(it *models.Item) string { return it.TrackNumber }),
+	itemInt("item_price", func(it *models.Item) int { return it.Price }),
+	itemStr("item_rid", func(it *models.Item) string { return it.Rid }),
+	itemStr("item_name", func(it *models.Item) string { return it.Name }),
+	itemInt("item_sale", func(it *models.Item) int { return it.Sale }),
+	itemStr("item_size", func(it *models.Item) string { return it.Size }),
+	itemInt("item_total_price", func(it *models.Item) int { return it.TotalPrice }),
+	itemInt("item_nm_id", func(it *models.Item) int { return it.NmID }),
+	itemStr("item_brand", func(it *models.Item) string { return it.Brand }),
+	itemInt("item_status", func(it *models.Item) int { return it.Status }),
+}
+
+// flatten вызывает fn для каждой строки заказа; заказ без товаров даёт одну строку
+func flatten(o *models.Order, fn func(it *models.Item) error) error {
+	if len(o.Items) == 0 {
+		return fn(nil)
+	}
+	for i := range o.Items {
+		if err := fn(&o.Items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package export
+
+import (
+	"L0-wb/internal/models"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// DefaultRowGroupRows - строк в группе Parquet; группа целиком держится в памяти
+const DefaultRowGroupRows = 10000
+
+// parquetSchema - схема выгрузки с колонками в порядке columns, как в CSV.
+// parquet.Group сортирует поля по имени, поэтому схема строится из структуры.
+var parquetSchema = newParquetSchema()
+
+func newParquetSchema() *parquet.Schema {
+	fields := make([]reflect.StructField, len(columns))
+	for i, c := range columns {
+		tag := c.name
+		var typ reflect.Type
+		switch c.kind {
+		case kindString:
+			typ = reflect.TypeOf("")
+		case kindInt:
+			typ = reflect.TypeOf(int64(0))
+		case kindTime:
+			typ, tag = reflect.TypeOf(time.Time{}), tag+",timestamp(millisecond)"
+		default:
+			panic(fmt.Sprintf("unknown column kind %d", c.kind))
+		}
+		if c.item {
+			tag += ",optional"
+		}
+		fields[i] = reflect.StructField{
+			Name: fmt.Sprintf("F%d", i),
+			Type: typ,
+			Tag:  reflect.StructTag(`parquet:"` + tag + `"`),
+		}
+	}
+	model := reflect.New(reflect.StructOf(fields)).Interface()
+	return parquet.NewSchema("order", parquet.SchemaOf(model))
+}
+
+// parquetWriter пишет плоские строки в Parquet со сжатием gzip
+type parquetWriter struct {
+	w   *parquet.Writer
+	row parquet.Row
+}
+
+func newParquetWriter(w io.Writer, rowGroupRows int) *parquetWriter {
+	return &parquetWriter{
+		w: parquet.NewWriter(w, parquetSchema,
+			parquet.Compression(&parquet.Gzip),
+			parquet.MaxRowsPerRowGroup(int64(rowGroupRows))),
+		row: make(parquet.Row, len(columns)),
+	}
+}
+
+func (w *parquetWriter) Write(o *models.Order) error {
+	return flatten(o, func(it *models.Item) error {
+		for i, c := range columns {
+			w.row[i] = parquetValue(c, c.value(o, it), i)
+		}
+		_, err := w.w.WriteRows([]parquet.Row{w.row})
+		return err
+	})
+}
+
+// Close дописывает последнюю группу и метаданные файла
+func (w *parquetWriter) Close() error {
+	return w.w.Close()
+}
+
+// parquetValue переводит значение колонки в значение Parquet с уровнем
+// определения: у optional-колонок товаров 1 - есть значение, 0 - null
+func parquetValue(c column, v interface{}, col int) parquet.Value {
+	def := 0
+	if c.item {
+		def = 1
+	}
+	switch v := v.(type) {
+	case string:
+		return parquet.ByteArrayValue([]byte(v)).Level(0, def, col)
+	case int64:
+		return parquet.Int64Value(v).Level(0, def, col)
+	case time.Time:
+		return parquet.Int64Value(v.UnixMilli()).Level(0, def, col)
+	}
+	return parquet.NullValue().Level(0, 0, col)
+}
@@ -0,0 +1,112 @@
+package export
+
+import (
+	"L0-wb/internal/models"
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readParquet открывает выгрузку и читает все строки
+func readParquet(t *testing.T, file []byte) (*parquet.File, []parquet.Row) {
+	f, err := parquet.OpenFile(bytes.NewReader(file), int64(len(file)))
+	require.NoError(t, err)
+
+	r := parquet.NewReader(bytes.NewReader(file))
+	defer r.Close()
+	rows := make([]parquet.Row, f.NumRows())
+	n, err := r.ReadRows(rows)
+	if err != io.EOF {
+		require.NoError(t, err)
+	}
+	require.Equal(t, len(rows), n)
+	return f, rows
+}
+
+// columnValues - значения колонки name по строкам; null - nil
+func columnValues(t *testing.T, f *parquet.File, rows []parquet.Row, name string) []interface{} {
+	leaf, ok := f.Schema().Lookup(name)
+	require.True(t, ok, name)
+
+	values := make([]interface{}, len(rows))
+	for i, row := range rows {
+		v := row[leaf.ColumnIndex]
+		switch {
+		case v.IsNull():
+		case v.Kind() == parquet.ByteArray:
+			values[i] = v.String()
+		default:
+			values[i] = v.Int64()
+		}
+	}
+	return values
+}
+
+func TestParquetWriter(t *testing.T) {
+	f, rows := readParquet(t, writeAll(t, Parquet))
+
+	require.Len(t, rows, 3)
+	fields := f.Schema().Fields()
+	require.Len(t, fields, len(columns))
+	for i, c := range columns {
+		assert.Equal(t, c.name, fields[i].Name(), "колонки в порядке CSV")
+		assert.Equal(t, c.item, fields[i].Optional(), c.name)
+	}
+	created := fields[9]
+	require.Equal(t, "date_created", created.Name())
+	assert.NotNil(t, created.Type().LogicalType().Timestamp)
+	for _, rg := range f.Metadata().RowGroups {
+		for _, chunk := range rg.Columns {
+			assert.Equal(t, "GZIP", chunk.MetaData.Codec.String())
+		}
+	}
+
+	assert.Equal(t, []interface{}{"uid-1", "uid-1", "uid-2"}, columnValues(t, f, rows, "order_uid"))
+	assert.Equal(t, []interface{}{int64(100), int64(200), nil}, columnValues(t, f, rows, "item_price"))
+	assert.Equal(t, []interface{}{"Футболка, чёрная", "Джинсы", nil}, columnValues(t, f, rows, "item_name"))
+	assert.Equal(t, time.Date(2025, 9, 1, 12, 30, 0, 0, time.UTC).UnixMilli(), columnValues(t, f, rows, "date_created")[0])
+}
+
+// TestParquetWriter_RoundTrip сверяет каждую колонку каждой строки с исходными
+// заказами при нескольких группах строк
+func TestParquetWriter_RoundTrip(t *testing.T) {
+	var out bytes.Buffer
+	w := newParquetWriter(&out, 2)
+	var want [][]interface{}
+	orders := testOrders()
+	for i := 0; i < 3; i++ {
+		for j := range orders {
+			o := orders[j]
+			o.OrderUID = fmt.Sprintf("uid-%d-%d", i, j)
+			require.NoError(t, w.Write(&o))
+			require.NoError(t, flatten(&o, func(it *models.Item) error {
+				row := make([]interface{}, len(columns))
+				for k, c := range columns {
+					row[k] = c.value(&o, it)
+					if tm, ok := row[k].(time.Time); ok {
+						row[k] = tm.UnixMilli()
+					}
+				}
+				want = append(want, row)
+				return nil
+			}))
+		}
+	}
+	require.NoError(t, w.Close())
+
+	f, rows := readParquet(t, out.Bytes())
+	require.Len(t, rows, len(want))
+	assert.Len(t, f.RowGroups(), 5)
+	for k, c := range columns {
+		got := columnValues(t, f, rows, c.name)
+		for i := range want {
+			assert.Equal(t, want[i][k], got[i], "%s, row %d", c.name, i)
+		}
+	}
+}
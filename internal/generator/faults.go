@@ -0,0 +1,164 @@
+package generator
+
+import (
+	"L0-wb/internal/models"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/brianvoe/gofakeit/v6"
+)
+
+// Fault - тип ошибки, внесённой в сообщение
+type Fault string
+
+const (
+	FaultNone               Fault = ""
+	FaultMalformedJSON      Fault = "malformed_json"
+	FaultMissingUID         Fault = "missing_order_uid"
+	FaultInvalidPhone       Fault = "invalid_phone"
+	FaultInvalidEmail       Fault = "invalid_email"
+	FaultNegativePrice      Fault = "negative_price"
+	FaultInconsistentTotals Fault = "inconsistent_totals"
+	FaultDuplicateUID       Fault = "duplicate_uid"
+	FaultOversized          Fault = "oversized_payload"
+)
+
+// AllFaults - все поддерживаемые типы ошибок
+var AllFaults = []Fault{
+	FaultMalformedJSON,
+	FaultMissingUID,
+	FaultInvalidPhone,
+	FaultInvalidEmail,
+	FaultNegativePrice,
+	FaultInconsistentTotals,
+	FaultDuplicateUID,
+	FaultOversized,
+}
+
+// FaultHeader - заголовок Kafka, которым продюсер помечает испорченные сообщения
+const FaultHeader = "x-fault"
+
+// DefaultOversizeBytes - размер payload для FaultOversized: больше лимита
+// консьюмера по умолчанию, но меньше message.max.bytes брокера (1MB)
+const DefaultOversizeBytes = 768 * 1024
+
+// ParseFaults разбирает список "malformed_json,invalid_phone"; "all" или пусто - все типы
+func ParseFaults(s string) ([]Fault, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "all" {
+		return AllFaults, nil
+	}
+
+	known := make(map[Fault]bool, len(AllFaults))
+	for _, f := range AllFaults {
+		known[f] = true
+	}
+
+	var faults []Fault
+	for _, name := range strings.Split(s, ",") {
+		f := Fault(strings.TrimSpace(name))
+		if !known[f] {
+			return nil, fmt.Errorf("unknown fault %q", f)
+		}
+		faults = append(faults, f)
+	}
+	return faults, nil
+}
+
+// Message - сериализованный заказ, возможно испорченный
+type Message struct {
+	Key   string
+	Value []byte
+	Fault Fault
+}
+
+// FaultInjector портит заданную долю заказов одним из выбранных типов ошибок
+type FaultInjector struct {
+	fake          *gofakeit.Faker
+	rate          float64
+	faults        []Fault
+	oversizeBytes int
+	seenUIDs      []string // последние UID для FaultDuplicateUID
+}
+
+// NewFaultInjector создаёт инжектор; rate - доля испорченных сообщений от 0 до 1
+func NewFaultInjector(fake *gofakeit.Faker, rate float64, faults []Fault) *FaultInjector {
+	if len(faults) == 0 {
+		faults = AllFaults
+	}
+	return &FaultInjector{
+		fake:          fake,
+		rate:          rate,
+		faults:        faults,
+		oversizeBytes: DefaultOversizeBytes,
+	}
+}
+
+// WithOversizeBytes меняет размер payload для FaultOversized
+func (f *FaultInjector) WithOversizeBytes(n int) *FaultInjector {
+	f.oversizeBytes = n
+	return f
+}
+
+// Next сериализует заказ, с вероятностью rate внося в него ошибку
+func (f *FaultInjector) Next(order *models.Order) (Message, error) {
+	fault := FaultNone
+	if f.rate > 0 && f.fake.Rand.Float64() < f.rate {
+		fault = f.faults[f.fake.IntRange(0, len(f.faults)-1)]
+	}
+	return f.Apply(order, fault)
+}
+
+// Apply вносит в заказ конкретную ошибку и сериализует его
+func (f *FaultInjector) Apply(order *models.Order, fault Fault) (Message, error) {
+	// без UID для дубликата, пока не видели ни одного заказа, сообщение уходит целым
+	if fault == FaultDuplicateUID && len(f.seenUIDs) == 0 {
+		fault = FaultNone
+	}
+
+	o := *order
+	o.Items = append(models.Items(nil), order.Items...)
+
+	switch fault {
+	case FaultMissingUID:
+		o.OrderUID = ""
+	case FaultInvalidPhone:
+		o.Delivery.Phone = "call-me-" + f.fake.Word()
+	case FaultInvalidEmail:
+		o.Delivery.Email = f.fake.Username() + "-at-example"
+	case FaultNegativePrice:
+		i := f.fake.IntRange(0, len(o.Items)-1)
+		o.Items[i].Price = -o.Items[i].Price
+		o.Items[i].TotalPrice = -o.Items[i].TotalPrice
+	case FaultInconsistentTotals:
+		o.Payment.GoodsTotal += f.fake.IntRange(1, 1000)
+	case FaultDuplicateUID:
+		o.OrderUID = f.seenUIDs[f.fake.IntRange(0, len(f.seenUIDs)-1)]
+	case FaultOversized:
+		o.InternalSignature = strings.Repeat("x", f.oversizeBytes)
+	}
+
+	value, err := json.Marshal(&o)
+	if err != nil {
+		return Message{}, err
+	}
+	if fault == FaultMalformedJSON {
+		// обрезаем JSON посередине
+		value = value[:len(value)/2]
+	}
+
+	// дубликатом имеет смысл делать только UID заказа, который дойдёт до базы
+	if fault == FaultNone {
+		f.remember(o.OrderUID)
+	}
+	return Message{Key: o.OrderUID, Value: value, Fault: fault}, nil
+}
+
+func (f *FaultInjector) remember(uid string) {
+	const keep = 100
+	f.seenUIDs = append(f.seenUIDs, uid)
+	if len(f.seenUIDs) > keep {
+		f.seenUIDs = f.seenUIDs[len(f.seenUIDs)-keep:]
+	}
+}
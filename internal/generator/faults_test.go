@@ -0,0 +1,89 @@
+package generator
+
+import (
+	"L0-wb/internal/models"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFaultInjector_Apply(t *testing.T) {
+	g := New(Config{Seed: 1})
+	injector := NewFaultInjector(g.Faker(), 0, nil).WithOversizeBytes(4096)
+
+	first, err := injector.Apply(g.Order(), FaultNone)
+	require.NoError(t, err)
+	var clean models.Order
+	require.NoError(t, json.Unmarshal(first.Value, &clean))
+	require.NoError(t, clean.Validate())
+
+	for _, fault := range AllFaults {
+		t.Run(string(fault), func(t *testing.T) {
+			msg, err := injector.Apply(g.Order(), fault)
+			require.NoError(t, err)
+			assert.Equal(t, fault, msg.Fault)
+
+			var order models.Order
+			if fault == FaultMalformedJSON {
+				assert.Error(t, json.Unmarshal(msg.Value, &order))
+				return
+			}
+			require.NoError(t, json.Unmarshal(msg.Value, &order))
+
+			switch fault {
+			case FaultDuplicateUID:
+				assert.Equal(t, first.Key, order.OrderUID)
+				assert.NoError(t, order.Validate())
+			case FaultOversized:
+				assert.Greater(t, len(msg.Value), 4096)
+			case FaultInconsistentTotals:
+				// суммы сервис не сверяет, заказ ловит только CheckTotals
+				assert.NoError(t, order.Validate())
+				assert.Error(t, order.CheckTotals())
+			default:
+				assert.Error(t, order.Validate())
+			}
+		})
+	}
+}
+
+func TestFaultInjector_Rate(t *testing.T) {
+	g := New(Config{Seed: 2})
+
+	never := NewFaultInjector(g.Faker(), 0, nil)
+	always := NewFaultInjector(g.Faker(), 1, []Fault{FaultInvalidEmail})
+	for i := 0; i < 50; i++ {
+		msg, err := never.Next(g.Order())
+		require.NoError(t, err)
+		assert.Equal(t, FaultNone, msg.Fault)
+
+		msg, err = always.Next(g.Order())
+		require.NoError(t, err)
+		assert.Equal(t, FaultInvalidEmail, msg.Fault)
+	}
+}
+
+func TestFaultInjector_DuplicateWithoutHistory(t *testing.T) {
+	g := New(Config{Seed: 3})
+	order := g.Order()
+
+	msg, err := NewFaultInjector(g.Faker(), 1, []Fault{FaultDuplicateUID}).Next(order)
+	require.NoError(t, err)
+	assert.Equal(t, FaultNone, msg.Fault)
+	assert.Equal(t, order.OrderUID, msg.Key)
+}
+
+func TestParseFaults(t *testing.T) {
+	faults, err := ParseFaults("all")
+	require.NoError(t, err)
+	assert.Equal(t, AllFaults, faults)
+
+	faults, err = ParseFaults(" invalid_phone, duplicate_uid ")
+	require.NoError(t, err)
+	assert.Equal(t, []Fault{FaultInvalidPhone, FaultDuplicateUID}, faults)
+
+	_, err = ParseFaults("invalid_phone,unknown")
+	assert.Error(t, err)
+}
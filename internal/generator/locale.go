@@ -0,0 +1,190 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/brianvoe/gofakeit/v6"
+)
+
+// Weighted - значение с весом для взвешенного выбора
+type Weighted[T any] struct {
+	Value  T
+	Weight int
+}
+
+func pick[T any](fake *gofakeit.Faker, choices []Weighted[T]) T {
+	total := 0
+	for _, c := range choices {
+		total += c.Weight
+	}
+	if total <= 0 {
+		var zero T
+		return zero
+	}
+	n := fake.IntRange(0, total-1)
+	for _, c := range choices {
+		if n < c.Weight {
+			return c.Value
+		}
+		n -= c.Weight
+	}
+	return choices[len(choices)-1].Value
+}
+
+// city - город с согласованными регионом, префиксом индекса и телефонным кодом
+type city struct {
+	Name      string
+	Region    string
+	ZipPrefix string
+	PhoneCode string
+}
+
+// locale - согласованные между собой данные покупателя одной страны
+type locale struct {
+	Code             string
+	Currency         string
+	CurrencyRate     int // цены категорий заданы в рублях
+	Cities           []Weighted[city]
+	Banks            []string
+	Providers        []string
+	DeliveryServices []string
+	DeliveryCosts    []Weighted[int] // в валюте локали
+	EmailDomains     []string
+	Colors           []string
+	Products         map[string]string // ключ категории -> название товара
+
+	name    func(fake *gofakeit.Faker) (first, last string)
+	phone   func(fake *gofakeit.Faker, c city) string
+	address func(fake *gofakeit.Faker) string
+	zip     func(fake *gofakeit.Faker, c city) string
+}
+
+var ruMaleNames = []string{"Александр", "Дмитрий", "Максим", "Сергей", "Андрей", "Алексей", "Иван", "Михаил", "Николай", "Егор"}
+var ruFemaleNames = []string{"Анна", "Мария", "Елена", "Ольга", "Наталья", "Екатерина", "Татьяна", "Ирина", "Светлана", "Дарья"}
+
+// мужские фамилии; женская форма получается добавлением "а"
+var ruSurnames = []string{"Иванов", "Смирнов", "Кузнецов", "Попов", "Васильев", "Петров", "Соколов", "Михайлов", "Новиков", "Фёдоров", "Морозов", "Волков"}
+
+var ruStreets = []string{"ул. Ленина", "ул. Мира", "пр. Победы", "ул. Садовая", "ул. Гагарина", "ул. Советская", "ул. Молодёжная", "ул. Пушкина", "ул. Лесная", "ул. Школьная"}
+
+var ruLocale = locale{
+	Code:         "ru",
+	Currency:     "RUB",
+	CurrencyRate: 1,
+	Cities: []Weighted[city]{
+		{city{"Москва", "Москва", "101", "495"}, 30},
+		{city{"Санкт-Петербург", "Санкт-Петербург", "190", "812"}, 15},
+		{city{"Новосибирск", "Новосибирская область", "630", "383"}, 7},
+		{city{"Екатеринбург", "Свердловская область", "620", "343"}, 7},
+		{city{"Казань", "Республика Татарстан", "420", "843"}, 6},
+		{city{"Нижний Новгород", "Нижегородская область", "603", "831"}, 6},
+		{city{"Краснодар", "Краснодарский край", "350", "861"}, 6},
+		{city{"Самара", "Самарская область", "443", "846"}, 5},
+		{city{"Ростов-на-Дону", "Ростовская область", "344", "863"}, 5},
+		{city{"Уфа", "Республика Башкортостан", "450", "347"}, 5},
+	},
+	Banks:            []string{"Sber", "Tinkoff", "Alpha", "VTB"},
+	Providers:        []string{"wbpay", "sbp", "card"},
+	DeliveryServices: []string{"WB Courier", "SDEK", "Russian Post", "Boxberry"},
+	DeliveryCosts:    []Weighted[int]{{0, 50}, {99, 25}, {199, 15}, {499, 10}},
+	EmailDomains:     []string{"mail.ru", "yandex.ru", "gmail.com", "bk.ru"},
+	Colors:           []string{"чёрный", "белый", "синий", "красный", "зелёный", "серый", "бежевый", "розовый"},
+	Products: map[string]string{
+		"tshirt":      "Футболка",
+		"jeans":       "Джинсы",
+		"jacket":      "Куртка",
+		"dress":       "Платье",
+		"sneakers":    "Кроссовки",
+		"cosmetics":   "Тушь для ресниц",
+		"accessories": "Сумка",
+	},
+	name: func(fake *gofakeit.Faker) (string, string) {
+		surname := fake.RandomString(ruSurnames)
+		if fake.Bool() {
+			return fake.RandomString(ruFemaleNames), surname + "а"
+		}
+		return fake.RandomString(ruMaleNames), surname
+	},
+	phone: func(fake *gofakeit.Faker, _ city) string {
+		// мобильные номера +79XXXXXXXXX
+		return formatPhoneNumber(fake.Numerify("9#########"))
+	},
+	address: func(fake *gofakeit.Faker) string {
+		return fmt.Sprintf("%s, д. %d, кв. %d", fake.RandomString(ruStreets), fake.IntRange(1, 150), fake.IntRange(1, 300))
+	},
+	zip: func(fake *gofakeit.Faker, c city) string {
+		return c.ZipPrefix + fake.Numerify("###")
+	},
+}
+
+var enLocale = locale{
+	Code:         "en",
+	Currency:     "USD",
+	CurrencyRate: 90,
+	Cities: []Weighted[city]{
+		{city{"New York", "NY", "100", "212"}, 25},
+		{city{"Los Angeles", "CA", "900", "213"}, 15},
+		{city{"Chicago", "IL", "606", "312"}, 10},
+		{city{"Houston", "TX", "770", "713"}, 9},
+		{city{"Phoenix", "AZ", "850", "602"}, 7},
+		{city{"Philadelphia", "PA", "191", "215"}, 7},
+		{city{"San Antonio", "TX", "782", "210"}, 6},
+		{city{"San Diego", "CA", "921", "619"}, 6},
+		{city{"Dallas", "TX", "752", "214"}, 8},
+		{city{"Seattle", "WA", "981", "206"}, 7},
+	},
+	Banks:            []string{"Chase", "Citi", "Bank of America", "Wells Fargo"},
+	Providers:        []string{"stripe", "paypal"},
+	DeliveryServices: []string{"UPS", "FedEx", "USPS", "DHL"},
+	DeliveryCosts:    []Weighted[int]{{0, 30}, {5, 35}, {10, 25}, {25, 10}},
+	EmailDomains:     []string{"gmail.com", "outlook.com", "yahoo.com", "icloud.com"},
+	Colors:           []string{"Black", "White", "Blue", "Red", "Green", "Grey", "Beige", "Pink"},
+	Products: map[string]string{
+		"tshirt":      "T-Shirt",
+		"jeans":       "Jeans",
+		"jacket":      "Jacket",
+		"dress":       "Dress",
+		"sneakers":    "Sneakers",
+		"cosmetics":   "Mascara",
+		"accessories": "Bag",
+	},
+	name: func(fake *gofakeit.Faker) (string, string) {
+		return fake.FirstName(), fake.LastName()
+	},
+	phone: func(fake *gofakeit.Faker, c city) string {
+		return "+1" + c.PhoneCode + fake.Numerify("#######")
+	},
+	address: func(fake *gofakeit.Faker) string {
+		return fake.Street()
+	},
+	zip: func(fake *gofakeit.Faker, c city) string {
+		return c.ZipPrefix + fake.Numerify("##")
+	},
+}
+
+var locales = map[string]*locale{
+	ruLocale.Code: &ruLocale,
+	enLocale.Code: &enLocale,
+}
+
+var translit = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+}
+
+// emailLocalPart собирает латинскую часть email из имени и фамилии
+func emailLocalPart(first, last string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(first + "." + last) {
+		switch {
+		case r >= 'a' && r <= 'z', r == '.':
+			b.WriteRune(r)
+		default:
+			b.WriteString(translit[r])
+		}
+	}
+	return b.String()
+}
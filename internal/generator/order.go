@@ -4,94 +4,235 @@ import (
 	"L0-wb/internal/models"
 	"fmt"
 	"math"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/brianvoe/gofakeit/v6"
 )
 
+// Category - категория товаров с диапазоном цен в рублях и популярностью брендов
+type Category struct {
+	Key      string // ключ названия товара в данных локали
+	MinPrice int
+	MaxPrice int
+	Sizes    []string
+	Brands   []Weighted[string]
+}
+
+// Config - распределения, по которым генерируются заказы
+type Config struct {
+	Seed       int64 // 0 - случайный seed
+	Locales    []Weighted[string]
+	ItemCounts []Weighted[int]
+	Categories []Weighted[Category]
+	Sales      []Weighted[int] // скидка в процентах
+	// RepeatCustomerRate - доля заказов от уже встречавшихся покупателей
+	RepeatCustomerRate float64
+	// CustomerPool - сколько последних покупателей помнить для повторных заказов
+	CustomerPool  int
+	CustomFeeRate float64
+	Now           func() time.Time
+}
+
+var clothesSizes = []string{"XS", "S", "M", "L", "XL", "XXL"}
+var shoeSizes = []string{"36", "37", "38", "39", "40", "41", "42", "43", "44", "45"}
+
+// DefaultConfig - распределения, близкие к реальному потоку заказов
+func DefaultConfig() Config {
+	return Config{
+		Locales:    []Weighted[string]{{"ru", 70}, {"en", 30}},
+		ItemCounts: []Weighted[int]{{1, 45}, {2, 25}, {3, 14}, {4, 8}, {5, 5}, {6, 3}},
+		Categories: []Weighted[Category]{
+			{Category{"tshirt", 500, 3000, clothesSizes, []Weighted[string]{{"UNIQLO", 30}, {"H&M", 25}, {"ZARA", 20}, {"NIKE", 15}, {"ADIDAS", 10}}}, 30},
+			{Category{"jeans", 1500, 7000, clothesSizes, []Weighted[string]{{"LEVIS", 40}, {"ZARA", 25}, {"H&M", 20}, {"TOMMY HILFIGER", 15}}}, 15},
+			{Category{"jacket", 4000, 25000, clothesSizes, []Weighted[string]{{"THE NORTH FACE", 30}, {"ZARA", 25}, {"UNIQLO", 25}, {"TOMMY HILFIGER", 20}}}, 8},
+			{Category{"dress", 1500, 12000, clothesSizes, []Weighted[string]{{"ZARA", 40}, {"H&M", 35}, {"MANGO", 25}}}, 12},
+			{Category{"sneakers", 3000, 20000, shoeSizes, []Weighted[string]{{"NIKE", 35}, {"ADIDAS", 30}, {"NEW BALANCE", 15}, {"PUMA", 12}, {"REEBOK", 8}}}, 12},
+			{Category{"cosmetics", 200, 3000, []string{"0"}, []Weighted[string]{{"Vivienne Sabo", 40}, {"Maybelline", 35}, {"L'Oreal", 25}}}, 15},
+			{Category{"accessories", 300, 5000, []string{"0"}, []Weighted[string]{{"ZARA", 35}, {"H&M", 35}, {"MANGO", 30}}}, 8},
+		},
+		Sales:              []Weighted[int]{{0, 40}, {10, 20}, {20, 15}, {30, 12}, {50, 8}, {70, 5}},
+		RepeatCustomerRate: 0.3,
+		CustomerPool:       1000,
+		CustomFeeRate:      0.05,
+		Now:                time.Now,
+	}
+}
+
+// ParseLocales разбирает веса локалей "ru=70,en=30"
+func ParseLocales(s string) ([]Weighted[string], error) {
+	var weights []Weighted[string]
+	for _, part := range strings.Split(s, ",") {
+		code, weight, found := strings.Cut(strings.TrimSpace(part), "=")
+		if _, ok := locales[code]; !ok {
+			return nil, fmt.Errorf("unknown locale %q", code)
+		}
+		w := 1
+		if found {
+			if _, err := fmt.Sscan(weight, &w); err != nil || w < 0 {
+				return nil, fmt.Errorf("invalid weight for locale %s: %q", code, weight)
+			}
+		}
+		weights = append(weights, Weighted[string]{code, w})
+	}
+	return weights, nil
+}
+
+type customer struct {
+	id       string
+	locale   *locale
+	delivery models.Delivery
+}
+
+// Generator генерирует заказы по Config. С одним seed выдаёт одну и ту же
+// последовательность заказов. Не безопасен для конкурентного использования.
+type Generator struct {
+	cfg       Config
+	fake      *gofakeit.Faker
+	customers []customer
+}
+
+// New создаёт генератор; незаданные поля Config берутся из DefaultConfig
+func New(cfg Config) *Generator {
+	def := DefaultConfig()
+	if len(cfg.Locales) == 0 {
+		cfg.Locales = def.Locales
+	}
+	if len(cfg.ItemCounts) == 0 {
+		cfg.ItemCounts = def.ItemCounts
+	}
+	if len(cfg.Categories) == 0 {
+		cfg.Categories = def.Categories
+	}
+	if len(cfg.Sales) == 0 {
+		cfg.Sales = def.Sales
+	}
+	if cfg.CustomerPool <= 0 {
+		cfg.CustomerPool = def.CustomerPool
+	}
+	if cfg.Now == nil {
+		cfg.Now = def.Now
+	}
+	return &Generator{cfg: cfg, fake: gofakeit.New(cfg.Seed)}
+}
+
+// GenerateOrder генерирует один заказ с распределениями по умолчанию и случайным seed
 func GenerateOrder() *models.Order {
-	fake := gofakeit.New(0)
-	now := time.Now()
+	return New(DefaultConfig()).Order()
+}
+
+// Faker возвращает источник случайности генератора, например для FaultInjector
+func (g *Generator) Faker() *gofakeit.Faker {
+	return g.fake
+}
 
-	// Генерируем items с корректными ценами
-	items := generateItems(fake.IntRange(1, 5))
+// Order генерирует следующий заказ
+func (g *Generator) Order() *models.Order {
+	now := g.cfg.Now()
+	c := g.customer()
+	loc := c.locale
+
+	// Трек-номер в формате WBILXXXXXXXX, общий для заказа и его товаров
+	trackNumber := fmt.Sprintf("WBIL%d", g.fake.IntRange(10000000, 99999999))
+	items := g.items(loc, trackNumber, pick(g.fake, g.cfg.ItemCounts))
 
-	// Считаем общую стоимость товаров без округления, т.к. TotalPrice уже int
 	var goodsTotal int
 	for _, item := range items {
 		goodsTotal += item.TotalPrice
 	}
-
-	deliveryCost := fake.IntRange(300, 2000)
-	customFee := int(math.Round(float64(goodsTotal) * 0.05)) // 5% от стоимости товаров
-
-	// Общая сумма заказа (все значения уже в int)
-	totalAmount := goodsTotal + deliveryCost + customFee
-
-	// Генерируем трек-номер в формате WBILXXXXXXXX
-	trackNumber := fmt.Sprintf("WBIL%d", fake.IntRange(10000000, 99999999))
+	deliveryCost := pick(g.fake, loc.DeliveryCosts)
+	customFee := int(math.Round(float64(goodsTotal) * g.cfg.CustomFeeRate))
 
 	return &models.Order{
-		OrderUID:    fake.UUID(),
+		OrderUID:    g.fake.UUID(),
 		TrackNumber: trackNumber,
 		Entry:       "WBIL",
-		Delivery: models.Delivery{
-			Name:    fake.Name(),
-			Phone:   formatPhoneNumber(fake.Phone()),
-			Zip:     fmt.Sprintf("%05d", fake.IntRange(100000, 999999)),
-			City:    fake.City(),
-			Address: formatAddress(fake.Street(), fake.StreetNumber()),
-			Region:  fake.State(),
-			Email:   fake.Email(),
-		},
+		Delivery:    c.delivery,
 		Payment: models.Payment{
-			Transaction:  fake.UUID(),
+			Transaction:  g.fake.UUID(),
 			RequestID:    "", // Может быть пустым
-			Currency:     "USD",
-			Provider:     fake.RandomString([]string{"stripe", "paypal", "bank_transfer"}),
-			Amount:       totalAmount,
+			Currency:     loc.Currency,
+			Provider:     g.fake.RandomString(loc.Providers),
+			Amount:       goodsTotal + deliveryCost + customFee,
 			PaymentDt:    int(now.Unix()),
-			Bank:         fake.RandomString([]string{"Sber", "Tinkoff", "Alpha", "VTB"}),
+			Bank:         g.fake.RandomString(loc.Banks),
 			DeliveryCost: deliveryCost,
 			GoodsTotal:   goodsTotal,
 			CustomFee:    customFee,
 		},
 		Items:             items,
-		Locale:            fake.RandomString([]string{"en", "ru"}),
+		Locale:            loc.Code,
 		InternalSignature: "", // Может быть пустым
-		CustomerID:        fmt.Sprintf("customer_%s", fake.Username()),
-		DeliveryService:   fake.RandomString([]string{"DHL", "FedEx", "UPS", "SDEK", "Russian Post"}),
-		Shardkey:          fmt.Sprintf("%d", fake.IntRange(1, 10)),
-		SmID:              fake.IntRange(1, 999),
+		CustomerID:        c.id,
+		DeliveryService:   g.fake.RandomString(loc.DeliveryServices),
+		Shardkey:          fmt.Sprintf("%d", g.fake.IntRange(1, 10)),
+		SmID:              g.fake.IntRange(1, 999),
 		DateCreated:       now,
-		OofShard:          fmt.Sprintf("%d", fake.IntRange(1, 10)),
+		OofShard:          fmt.Sprintf("%d", g.fake.IntRange(1, 10)),
 	}
 }
 
-func generateItems(count int) []models.Item {
-	fake := gofakeit.New(0)
+// customer возвращает нового или, с вероятностью RepeatCustomerRate, уже встречавшегося покупателя
+func (g *Generator) customer() customer {
+	if len(g.customers) > 0 && g.fake.Rand.Float64() < g.cfg.RepeatCustomerRate {
+		return g.customers[g.fake.IntRange(0, len(g.customers)-1)]
+	}
+
+	loc := locales[pick(g.fake, g.cfg.Locales)]
+	if loc == nil {
+		loc = &ruLocale
+	}
+	first, last := loc.name(g.fake)
+	c := pick(g.fake, loc.Cities)
+	local := emailLocalPart(first, last)
+
+	cust := customer{
+		id:     fmt.Sprintf("customer_%s%d", strings.ReplaceAll(local, ".", "_"), g.fake.IntRange(1, 999)),
+		locale: loc,
+		delivery: models.Delivery{
+			Name:    first + " " + last,
+			Phone:   loc.phone(g.fake, c),
+			Zip:     loc.zip(g.fake, c),
+			City:    c.Name,
+			Address: loc.address(g.fake),
+			Region:  c.Region,
+			Email:   fmt.Sprintf("%s%d@%s", local, g.fake.IntRange(1, 99), g.fake.RandomString(loc.EmailDomains)),
+		},
+	}
+
+	g.customers = append(g.customers, cust)
+	if len(g.customers) > g.cfg.CustomerPool {
+		g.customers = g.customers[1:]
+	}
+	return cust
+}
+
+func (g *Generator) items(loc *locale, trackNumber string, count int) []models.Item {
 	items := make([]models.Item, count)
 
 	for i := 0; i < count; i++ {
-		basePrice := fake.IntRange(100, 10000)
-		sale := fake.IntRange(0, 50) // Скидка до 50%
+		cat := pick(g.fake, g.cfg.Categories)
+		basePrice := g.price(cat, loc)
+		sale := pick(g.fake, g.cfg.Sales)
 
-		// Расчет цены со скидкой
-		totalPrice := float64(basePrice) * (100 - float64(sale)) / 100
-		// Округляем до целого числа для TotalPrice
-		roundedTotalPrice := int(math.Round(totalPrice))
+		// Расчет цены со скидкой, округляем до целого для TotalPrice
+		totalPrice := int(math.Round(float64(basePrice) * (100 - float64(sale)) / 100))
+		if totalPrice < 1 {
+			totalPrice = 1
+		}
 
 		items[i] = models.Item{
-			ChrtID:      fake.IntRange(1000000, 9999999),
-			TrackNumber: fmt.Sprintf("WBIL%d", fake.IntRange(10000000, 99999999)),
+			ChrtID:      g.fake.IntRange(1000000, 9999999),
+			TrackNumber: trackNumber,
 			Price:       basePrice,
-			Rid:         fake.UUID(),
-			Name:        generateProductName(fake),
+			Rid:         g.fake.UUID(),
+			Name:        fmt.Sprintf("%s %s", g.fake.RandomString(loc.Colors), loc.Products[cat.Key]),
 			Sale:        sale,
-			Size:        generateSize(fake),
-			TotalPrice:  roundedTotalPrice,
-			NmID:        fake.IntRange(1000000, 9999999),
-			Brand:       generateBrand(fake),
+			Size:        g.fake.RandomString(cat.Sizes),
+			TotalPrice:  totalPrice,
+			NmID:        g.fake.IntRange(1000000, 9999999),
+			Brand:       pick(g.fake, cat.Brands),
 			Status:      200, // 200 - доставлен
 		}
 	}
@@ -99,29 +240,25 @@ func generateItems(count int) []models.Item {
 	return items
 }
 
-func generateProductName(fake *gofakeit.Faker) string {
-	categories := []string{
-		"Футболка", "Джинсы", "Куртка", "Платье", "Кроссовки",
-		"Рубашка", "Юбка", "Свитер", "Брюки", "Пальто",
-	}
-	colors := []string{
-		"черный", "белый", "синий", "красный", "зеленый",
-		"серый", "бежевый", "розовый", "желтый", "коричневый",
+// price выбирает цену log-равномерно: дешёвых товаров в категории больше, чем дорогих
+func (g *Generator) price(cat Category, loc *locale) int {
+	lo, hi := math.Log(float64(cat.MinPrice)), math.Log(float64(cat.MaxPrice))
+	rub := math.Exp(lo + g.fake.Rand.Float64()*(hi-lo))
+	price := int(math.Round(rub / float64(loc.CurrencyRate)))
+	if price < 1 {
+		price = 1
 	}
-	return fmt.Sprintf("%s %s", fake.RandomString(colors), fake.RandomString(categories))
+	return price
 }
 
-func generateBrand(fake *gofakeit.Faker) string {
-	brands := []string{
-		"NIKE", "ADIDAS", "PUMA", "REEBOK", "NEW BALANCE",
-		"ZARA", "H&M", "UNIQLO", "LEVIS", "TOMMY HILFIGER",
+// Locales возвращает коды поддерживаемых локалей
+func Locales() []string {
+	codes := make([]string, 0, len(locales))
+	for code := range locales {
+		codes = append(codes, code)
 	}
-	return fake.RandomString(brands)
-}
-
-func generateSize(fake *gofakeit.Faker) string {
-	sizes := []string{"XS", "S", "M", "L", "XL", "XXL"}
-	return fake.RandomString(sizes)
+	sort.Strings(codes)
+	return codes
 }
 
 func formatPhoneNumber(phone string) string {
@@ -147,7 +284,3 @@ func formatPhoneNumber(phone string) string {
 
 	return "+7" + string(result)
 }
-
-func formatAddress(street, number string) string {
-	return fmt.Sprintf("%s, д. %s", street, number)
-}
@@ -1,9 +1,12 @@
 package generator
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGenerateOrder(t *testing.T) {
@@ -28,10 +31,11 @@ func TestGenerateOrder(t *testing.T) {
 
 	assert.Equal(t, itemsTotal, order.Payment.GoodsTotal)
 	assert.Equal(t, order.Payment.Amount, order.Payment.GoodsTotal+order.Payment.DeliveryCost+order.Payment.CustomFee)
+	assert.NoError(t, order.CheckTotals())
 }
 
 func TestGenerateItems(t *testing.T) {
-	items := generateItems(3)
+	items := New(Config{Seed: 1}).items(&ruLocale, "WBIL12345678", 3)
 	assert.Len(t, items, 3)
 
 	for _, item := range items {
@@ -79,3 +83,91 @@ func TestFormatPhoneNumber(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerator_Seed(t *testing.T) {
+	now := time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC)
+	cfg := Config{Seed: 42, Now: func() time.Time { return now }}
+
+	a, b := New(cfg), New(cfg)
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, a.Order(), b.Order())
+	}
+
+	cfg.Seed = 43
+	assert.NotEqual(t, New(cfg).Order().OrderUID, New(Config{Seed: 42}).Order().OrderUID)
+}
+
+func TestGenerator_Locales(t *testing.T) {
+	for _, code := range Locales() {
+		t.Run(code, func(t *testing.T) {
+			g := New(Config{Seed: 7, Locales: []Weighted[string]{{code, 1}}})
+			loc := locales[code]
+
+			for i := 0; i < 50; i++ {
+				order := g.Order()
+				require.NoError(t, order.Validate())
+				assert.Equal(t, code, order.Locale)
+				assert.Equal(t, loc.Currency, order.Payment.Currency)
+
+				// город, регион, индекс и телефон согласованы между собой
+				var c city
+				for _, w := range loc.Cities {
+					if w.Value.Name == order.Delivery.City {
+						c = w.Value
+					}
+				}
+				require.NotEmpty(t, c.Name, "unknown city %s", order.Delivery.City)
+				assert.Equal(t, c.Region, order.Delivery.Region)
+				assert.True(t, strings.HasPrefix(order.Delivery.Zip, c.ZipPrefix))
+				if code == "ru" {
+					assert.True(t, strings.HasPrefix(order.Delivery.Phone, "+79"))
+				} else {
+					assert.Equal(t, "+1"+c.PhoneCode, order.Delivery.Phone[:5])
+				}
+			}
+		})
+	}
+}
+
+func TestGenerator_Distributions(t *testing.T) {
+	cheap := Category{Key: "cosmetics", MinPrice: 100, MaxPrice: 200, Sizes: []string{"0"}, Brands: []Weighted[string]{{"ONLY", 1}}}
+	g := New(Config{
+		Seed:               5,
+		Locales:            []Weighted[string]{{"ru", 1}},
+		ItemCounts:         []Weighted[int]{{2, 1}},
+		Categories:         []Weighted[Category]{{cheap, 1}},
+		Sales:              []Weighted[int]{{0, 1}},
+		RepeatCustomerRate: 1,
+	})
+
+	first := g.Order()
+	for i := 0; i < 20; i++ {
+		order := g.Order()
+		assert.Len(t, order.Items, 2)
+		for _, item := range order.Items {
+			assert.GreaterOrEqual(t, item.Price, 100)
+			assert.LessOrEqual(t, item.Price, 200)
+			assert.Equal(t, item.Price, item.TotalPrice)
+			assert.Equal(t, "ONLY", item.Brand)
+			assert.Equal(t, order.TrackNumber, item.TrackNumber)
+		}
+		// при RepeatCustomerRate = 1 все заказы от первого покупателя
+		assert.Equal(t, first.CustomerID, order.CustomerID)
+		assert.Equal(t, first.Delivery, order.Delivery)
+	}
+}
+
+func TestParseLocales(t *testing.T) {
+	weights, err := ParseLocales("ru=70, en=30")
+	require.NoError(t, err)
+	assert.Equal(t, []Weighted[string]{{"ru", 70}, {"en", 30}}, weights)
+
+	weights, err = ParseLocales("en")
+	require.NoError(t, err)
+	assert.Equal(t, []Weighted[string]{{"en", 1}}, weights)
+
+	_, err = ParseLocales("de=10")
+	assert.Error(t, err)
+	_, err = ParseLocales("ru=x")
+	assert.Error(t, err)
+}
@@ -0,0 +1,72 @@
+// Package gql отдаёт заказы по GraphQL (/graphql): клиент запрашивает только
+// нужные поля, а связанные данные списка догружаются пачками (см. partsLoader).
+package gql
+
+import (
+	"L0-wb/internal/service"
+	_ "embed"
+	"encoding/json"
+	"net/http"
+
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+// maxBody ограничивает размер POST-запроса с query и variables
+const maxBody = 1 << 20
+
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Handler исполняет запросы GraphQL: POST с JSON-телом или GET с ?query=
+type Handler struct {
+	schema *graphql.Schema
+}
+
+// NewHandler разбирает схему; ошибка в ней - ошибка сборки, поэтому паника
+func NewHandler(svc service.Service) *Handler {
+	schema := graphql.MustParseSchema(schemaSDL, &queryResolver{service: svc})
+	return &Handler{schema: schema}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req request
+	switch r.Method {
+	case http.MethodGet:
+		q := r.URL.Query()
+		req.Query, req.OperationName = q.Get("query"), q.Get("operationName")
+		if v := q.Get("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+				writeError(w, "invalid variables: "+err.Error())
+				return
+			}
+		}
+	default:
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBody)).Decode(&req); err != nil {
+			writeError(w, "invalid JSON body: "+err.Error())
+			return
+		}
+	}
+	if req.Query == "" {
+		writeError(w, "query is required")
+		return
+	}
+
+	resp := h.schema.Exec(withBudget(r.Context()), req.Query, req.OperationName, req.Variables)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// writeError отвечает в формате GraphQL: {"errors":[{"message":...}]}
+func writeError(w http.ResponseWriter, msg string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"errors": []map[string]string{{"message": msg}},
+	})
+}
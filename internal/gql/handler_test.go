@@ -0,0 +1,192 @@
+package gql
+
+import (
+	"L0-wb/internal/mocks"
+	"L0-wb/internal/models"
+	"L0-wb/internal/service"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// post исполняет запрос; admin - как с токеном администратора
+func post(t *testing.T, h http.Handler, admin bool, query string, variables map[string]interface{}) response {
+	t.Helper()
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	if admin {
+		req = req.WithContext(WithAdmin(req.Context()))
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp response
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	return resp
+}
+
+func TestOrders_BatchesParts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	h := NewHandler(mockService)
+
+	headers := []models.Order{{OrderUID: "a", DeliveryService: "meest"}, {OrderUID: "b", DeliveryService: "meest"}, {OrderUID: "c", DeliveryService: "meest"}}
+	mockService.EXPECT().ListOrderHeaders(gomock.Any(), models.OrderFilter{DeliveryService: "meest", Limit: 2, Offset: 1}).Return(headers, nil)
+	// по одному запросу на часть для всей страницы; оплата не запрошена и не загружается
+	mockService.EXPECT().OrderParts(gomock.Any(), []string{"a", "b", "c"}, models.OrderParts{Delivery: true}).Return(map[string]models.Order{
+		"a": {Delivery: models.Delivery{City: "Moscow"}},
+		"b": {Delivery: models.Delivery{City: "Kazan"}},
+		"c": {Delivery: models.Delivery{City: "Omsk"}},
+	}, nil).Times(1)
+	mockService.EXPECT().OrderParts(gomock.Any(), []string{"a", "b", "c"}, models.OrderParts{Items: true}).Return(map[string]models.Order{
+		"a": {Items: models.Items{{Name: "Mascaras", Price: 453}}},
+		"c": {Items: models.Items{{Name: "Shoes", Price: 1000}, {Name: "Socks", Price: 50}}},
+	}, nil).Times(1)
+
+	resp := post(t, h, true, `query($service: String) {
+		orders(filter: {delivery_service: $service}, limit: 2, offset: 1) {
+			order_uid
+			delivery { city }
+			items { name price }
+		}
+	}`, map[string]interface{}{"service": "meest"})
+	require.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"orders": [
+		{"order_uid": "a", "delivery": {"city": "Moscow"}, "items": [{"name": "Mascaras", "price": 453}]},
+		{"order_uid": "b", "delivery": {"city": "Kazan"}, "items": []},
+		{"order_uid": "c", "delivery": {"city": "Omsk"}, "items": [{"name": "Shoes", "price": 1000}, {"name": "Socks", "price": 50}]}
+	]}`, string(resp.Data))
+}
+
+func TestOrders_HeadersOnly(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	h := NewHandler(mockService)
+
+	created := time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC)
+	mockService.EXPECT().ListOrderHeaders(gomock.Any(), models.OrderFilter{CreatedFrom: created, Limit: models.DefaultListLimit}).
+		Return([]models.Order{{OrderUID: "a", DateCreated: created, SmID: 99}}, nil)
+
+	resp := post(t, h, true, `{ orders(filter: {created_from: "2025-09-01T10:00:00Z"}) { order_uid sm_id date_created } }`, nil)
+	require.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"orders": [{"order_uid": "a", "sm_id": 99, "date_created": "2025-09-01T10:00:00Z"}]}`, string(resp.Data))
+}
+
+func TestOrders_Errors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	h := NewHandler(mockService)
+
+	resp := post(t, h, true, `{ orders(limit: 5000) { order_uid } }`, nil)
+	require.Len(t, resp.Errors, 1)
+	assert.Contains(t, resp.Errors[0].Message, "limit must be between 1 and 1000")
+
+	resp = post(t, h, true, `{ orders { order_uid unknown_field } }`, nil)
+	require.NotEmpty(t, resp.Errors)
+
+	// текст ошибки БД не уходит клиенту
+	mockService.EXPECT().ListOrderHeaders(gomock.Any(), gomock.Any()).Return(nil, errors.New("pq: connection refused"))
+	resp = post(t, h, true, `{ orders { order_uid } }`, nil)
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, "internal server error", resp.Errors[0].Message)
+}
+
+func TestOrders_Limits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	h := NewHandler(mockService)
+
+	// без токена администратора список не отдаётся и сервис не вызывается
+	resp := post(t, h, false, `{ orders { order_uid delivery { phone } } }`, nil)
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, errAdminRequired.Error(), resp.Errors[0].Message)
+
+	// алиасы не обходят предел maxLimit заказов на запрос
+	mockService.EXPECT().ListOrderHeaders(gomock.Any(), gomock.Any()).Return(nil, nil).MaxTimes(1)
+	resp = post(t, h, true, `{ a: orders(limit: 1000) { order_uid } b: orders(limit: 1000) { order_uid } }`, nil)
+	require.Len(t, resp.Errors, 1)
+	assert.Contains(t, resp.Errors[0].Message, "query is too expensive")
+
+	var q strings.Builder
+	q.WriteString("{")
+	for i := 0; i <= maxRootFields; i++ {
+		fmt.Fprintf(&q, " o%d: order(order_uid: \"a\") { order_uid }", i)
+	}
+	q.WriteString(" }")
+	mockService.EXPECT().GetOrderByUID(gomock.Any(), "a").Return(&models.Order{OrderUID: "a"}, nil).Times(maxRootFields)
+	resp = post(t, h, false, q.String(), nil)
+	require.Len(t, resp.Errors, 1)
+	assert.Contains(t, resp.Errors[0].Message, "too many root fields")
+}
+
+func TestOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	h := NewHandler(mockService)
+
+	order := &models.Order{
+		OrderUID: "a",
+		Delivery: models.Delivery{Name: "Test Testov"},
+		Payment:  models.Payment{Amount: 1817, Currency: "USD"},
+		Items:    models.Items{{ChrtID: 9934930, Brand: "Vivienne Sabo"}},
+	}
+	// заказ из сервиса полный: OrderParts не вызывается
+	mockService.EXPECT().GetOrderByUID(gomock.Any(), "a").Return(order, nil)
+	mockService.EXPECT().GetOrderByUID(gomock.Any(), "missing").Return(nil, service.ErrNotFound)
+
+	w := httptest.NewRecorder()
+	q := url.Values{"query": {`{ order(order_uid: "a") { delivery { name } payment { amount currency } items { chrt_id brand } } }`}}
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/graphql?"+q.Encode(), nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data": {"order": {
+		"delivery": {"name": "Test Testov"},
+		"payment": {"amount": 1817, "currency": "USD"},
+		"items": [{"chrt_id": 9934930, "brand": "Vivienne Sabo"}]
+	}}}`, w.Body.String())
+
+	resp := post(t, h, false, `{ order(order_uid: "missing") { order_uid } }`, nil)
+	require.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"order": null}`, string(resp.Data))
+}
+
+func TestHandler_BadRequest(t *testing.T) {
+	h := NewHandler(nil)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query":`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/graphql", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"errors": [{"message": "query is required"}]}`, w.Body.String())
+}
@@ -0,0 +1,65 @@
+package gql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+const (
+	// maxRootFields - сколько полей Query (с алиасами) можно запросить за раз
+	maxRootFields = 10
+	// maxCost - бюджет запроса: order стоит 1, orders - свой limit.
+	// Алиасы orders не обходят предел в maxLimit заказов на запрос.
+	maxCost = maxLimit
+)
+
+var errAdminRequired = errors.New("orders requires the admin token: Authorization: Bearer <HTTP_ADMIN_TOKEN>")
+
+type ctxKey int
+
+const (
+	adminKey ctxKey = iota
+	budgetKey
+)
+
+// WithAdmin помечает запрос как выполненный с токеном администратора:
+// только такому запросу доступен список заказов с персональными данными
+func WithAdmin(ctx context.Context) context.Context {
+	return context.WithValue(ctx, adminKey, true)
+}
+
+func isAdmin(ctx context.Context) bool {
+	admin, _ := ctx.Value(adminKey).(bool)
+	return admin
+}
+
+// budget считает поля Query и стоимость одного запроса; корневые поля
+// исполняются параллельно, поэтому под мьютексом
+type budget struct {
+	mu     sync.Mutex
+	fields int
+	cost   int
+}
+
+func withBudget(ctx context.Context) context.Context {
+	return context.WithValue(ctx, budgetKey, &budget{})
+}
+
+// spend списывает стоимость поля Query; без бюджета в контексте ничего не ограничивает
+func spend(ctx context.Context, cost int) error {
+	b, ok := ctx.Value(budgetKey).(*budget)
+	if !ok {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.fields++; b.fields > maxRootFields {
+		return fmt.Errorf("too many root fields: at most %d per request", maxRootFields)
+	}
+	if b.cost += cost; b.cost > maxCost {
+		return fmt.Errorf("query is too expensive: at most %d orders per request", maxCost)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package gql
+
+import (
+	"L0-wb/internal/models"
+	"L0-wb/internal/service"
+	"context"
+	"sync"
+)
+
+// partsLoader догружает доставку, оплату и товары для всей страницы заказов
+// при первом обращении к полю любого из них: одна выборка на часть вместо
+// запроса на каждый заказ. Резолверы полей вызываются параллельно, поэтому
+// каждая часть загружается один раз под своим sync.Once.
+type partsLoader struct {
+	service service.Service
+	uids    []string
+
+	delivery, payment, items partLoad
+}
+
+type partLoad struct {
+	once   sync.Once
+	orders map[string]models.Order
+	err    error
+}
+
+func newPartsLoader(svc service.Service, orders []models.Order) *partsLoader {
+	uids := make([]string, len(orders))
+	for i, o := range orders {
+		uids[i] = o.OrderUID
+	}
+	return &partsLoader{service: svc, uids: uids}
+}
+
+func (l *partsLoader) load(ctx context.Context, p *partLoad, parts models.OrderParts) (map[string]models.Order, error) {
+	p.once.Do(func() {
+		p.orders, p.err = l.service.OrderParts(ctx, l.uids, parts)
+	})
+	return p.orders, p.err
+}
+
+func (l *partsLoader) Delivery(ctx context.Context, uid string) (models.Delivery, error) {
+	orders, err := l.load(ctx, &l.delivery, models.OrderParts{Delivery: true})
+	return orders[uid].Delivery, err
+}
+
+func (l *partsLoader) Payment(ctx context.Context, uid string) (models.Payment, error) {
+	orders, err := l.load(ctx, &l.payment, models.OrderParts{Payment: true})
+	return orders[uid].Payment, err
+}
+
+func (l *partsLoader) Items(ctx context.Context, uid string) (models.Items, error) {
+	orders, err := l.load(ctx, &l.items, models.OrderParts{Items: true})
+	return orders[uid].Items, err
+}
@@ -0,0 +1,195 @@
+package gql
+
+import (
+	"L0-wb/internal/models"
+	"L0-wb/internal/service"
+	"context"
+	"errors"
+	"fmt"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/sirupsen/logrus"
+)
+
+// maxLimit - предел limit в orders, как у ListOrders в gRPC
+const maxLimit = 1000
+
+// errInternal скрывает от клиента текст ошибок БД; подробности уходят в лог
+var errInternal = errors.New("internal server error")
+
+type queryResolver struct {
+	service service.Service
+}
+
+func (q *queryResolver) Order(ctx context.Context, args struct{ OrderUID string }) (*orderResolver, error) {
+	if err := spend(ctx, 1); err != nil {
+		return nil, err
+	}
+	order, err := q.service.GetOrderByUID(ctx, args.OrderUID)
+	if errors.Is(err, service.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		logrus.WithError(err).WithField("order_uid", args.OrderUID).Error("graphql: failed to get order")
+		return nil, errInternal
+	}
+	// заказ из сервиса уже полный, догружать нечего
+	return &orderResolver{order: order}, nil
+}
+
+type filterInput struct {
+	CustomerID      *string
+	TrackNumber     *string
+	Locale          *string
+	DeliveryService *string
+	CreatedFrom     *graphql.Time
+	CreatedTo       *graphql.Time
+}
+
+type ordersArgs struct {
+	Filter *filterInput
+	Limit  int32
+	Offset int32
+}
+
+// Orders отдаёт заказы целиком, с контактами и данными оплаты, поэтому
+// доступен только с токеном администратора, как /orders/export
+func (q *queryResolver) Orders(ctx context.Context, args ordersArgs) ([]*orderResolver, error) {
+	if !isAdmin(ctx) {
+		return nil, errAdminRequired
+	}
+	if args.Limit < 1 || args.Limit > maxLimit || args.Offset < 0 {
+		return nil, fmt.Errorf("limit must be between 1 and %d, offset must not be negative", maxLimit)
+	}
+	if err := spend(ctx, int(args.Limit)); err != nil {
+		return nil, err
+	}
+	filter := models.OrderFilter{Limit: int(args.Limit), Offset: int(args.Offset)}
+	if f := args.Filter; f != nil {
+		filter.CustomerID = deref(f.CustomerID)
+		filter.TrackNumber = deref(f.TrackNumber)
+		filter.Locale = deref(f.Locale)
+		filter.DeliveryService = deref(f.DeliveryService)
+		if f.CreatedFrom != nil {
+			filter.CreatedFrom = f.CreatedFrom.Time
+		}
+		if f.CreatedTo != nil {
+			filter.CreatedTo = f.CreatedTo.Time
+		}
+	}
+
+	orders, err := q.service.ListOrderHeaders(ctx, filter)
+	if err != nil {
+		logrus.WithError(err).Error("graphql: failed to list orders")
+		return nil, errInternal
+	}
+	loader := newPartsLoader(q.service, orders)
+	resolvers := make([]*orderResolver, len(orders))
+	for i := range orders {
+		resolvers[i] = &orderResolver{order: &orders[i], loader: loader}
+	}
+	return resolvers, nil
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// orderResolver отдаёт поля заказа; связанные данные берутся из loader,
+// если заказ загружен без них
+type orderResolver struct {
+	order  *models.Order
+	loader *partsLoader
+}
+
+func (r *orderResolver) OrderUID() string          { return r.order.OrderUID }
+func (r *orderResolver) TrackNumber() string       { return r.order.TrackNumber }
+func (r *orderResolver) Entry() string             { return r.order.Entry }
+func (r *orderResolver) Locale() string            { return r.order.Locale }
+func (r *orderResolver) InternalSignature() string { return r.order.InternalSignature }
+func (r *orderResolver) CustomerID() string        { return r.order.CustomerID }
+func (r *orderResolver) DeliveryService() string   { return r.order.DeliveryService }
+func (r *orderResolver) Shardkey() string          { return r.order.Shardkey }
+func (r *orderResolver) SmID() int32               { return int32(r.order.SmID) }
+func (r *orderResolver) DateCreated() graphql.Time { return graphql.Time{Time: r.order.DateCreated} }
+func (r *orderResolver) OofShard() string          { return r.order.OofShard }
+
+func (r *orderResolver) Delivery(ctx context.Context) (*deliveryResolver, error) {
+	if r.loader == nil {
+		return &deliveryResolver{r.order.Delivery}, nil
+	}
+	d, err := r.loader.Delivery(ctx, r.order.OrderUID)
+	if err != nil {
+		logrus.WithError(err).Error("graphql: failed to load deliveries")
+		return nil, errInternal
+	}
+	return &deliveryResolver{d}, nil
+}
+
+func (r *orderResolver) Payment(ctx context.Context) (*paymentResolver, error) {
+	if r.loader == nil {
+		return &paymentResolver{r.order.Payment}, nil
+	}
+	p, err := r.loader.Payment(ctx, r.order.OrderUID)
+	if err != nil {
+		logrus.WithError(err).Error("graphql: failed to load payments")
+		return nil, errInternal
+	}
+	return &paymentResolver{p}, nil
+}
+
+func (r *orderResolver) Items(ctx context.Context) ([]*itemResolver, error) {
+	items := r.order.Items
+	if r.loader != nil {
+		var err error
+		if items, err = r.loader.Items(ctx, r.order.OrderUID); err != nil {
+			logrus.WithError(err).Error("graphql: failed to load items")
+			return nil, errInternal
+		}
+	}
+	resolvers := make([]*itemResolver, len(items))
+	for i := range items {
+		resolvers[i] = &itemResolver{items[i]}
+	}
+	return resolvers, nil
+}
+
+type deliveryResolver struct{ d models.Delivery }
+
+func (r *deliveryResolver) Name() string    { return r.d.Name }
+func (r *deliveryResolver) Phone() string   { return r.d.Phone }
+func (r *deliveryResolver) Zip() string     { return r.d.Zip }
+func (r *deliveryResolver) City() string    { return r.d.City }
+func (r *deliveryResolver) Address() string { return r.d.Address }
+func (r *deliveryResolver) Region() string  { return r.d.Region }
+func (r *deliveryResolver) Email() string   { return r.d.Email }
+
+type paymentResolver struct{ p models.Payment }
+
+func (r *paymentResolver) Transaction() string { return r.p.Transaction }
+func (r *paymentResolver) RequestID() string   { return r.p.RequestID }
+func (r *paymentResolver) Currency() string    { return r.p.Currency }
+func (r *paymentResolver) Provider() string    { return r.p.Provider }
+func (r *paymentResolver) Amount() int32       { return int32(r.p.Amount) }
+func (r *paymentResolver) PaymentDt() int32    { return int32(r.p.PaymentDt) }
+func (r *paymentResolver) Bank() string        { return r.p.Bank }
+func (r *paymentResolver) DeliveryCost() int32 { return int32(r.p.DeliveryCost) }
+func (r *paymentResolver) GoodsTotal() int32   { return int32(r.p.GoodsTotal) }
+func (r *paymentResolver) CustomFee() int32    { return int32(r.p.CustomFee) }
+
+type itemResolver struct{ it models.Item }
+
+func (r *itemResolver) ChrtID() int32       { return int32(r.it.ChrtID) }
+func (r *itemResolver) TrackNumber() string { return r.it.TrackNumber }
+func (r *itemResolver) Price() int32        { return int32(r.it.Price) }
+func (r *itemResolver) Rid() string         { return r.it.Rid }
+func (r *itemResolver) Name() string        { return r.it.Name }
+func (r *itemResolver) Sale() int32         { return int32(r.it.Sale) }
+func (r *itemResolver) Size() string        { return r.it.Size }
+func (r *itemResolver) TotalPrice() int32   { return int32(r.it.TotalPrice) }
+func (r *itemResolver) NmID() int32         { return int32(r.it.NmID) }
+func (r *itemResolver) Brand() string       { return r.it.Brand }
+func (r *itemResolver) Status() int32       { return int32(r.it.Status) }
@@ -0,0 +1,124 @@
+package grpcapi
+
+import (
+	"L0-wb/internal/grpcapi/ordersv1"
+	"L0-wb/internal/models"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func orderToProto(o *models.Order) *ordersv1.Order {
+	items := make([]*ordersv1.Item, len(o.Items))
+	for i, it := range o.Items {
+		items[i] = &ordersv1.Item{
+			ChrtId:      int64(it.ChrtID),
+			TrackNumber: it.TrackNumber,
+			Price:       int64(it.Price),
+			Rid:         it.Rid,
+			Name:        it.Name,
+			Sale:        int64(it.Sale),
+			Size:        it.Size,
+			TotalPrice:  int64(it.TotalPrice),
+			NmId:        int64(it.NmID),
+			Brand:       it.Brand,
+			Status:      int64(it.Status),
+		}
+	}
+	return &ordersv1.Order{
+		OrderUid:    o.OrderUID,
+		TrackNumber: o.TrackNumber,
+		Entry:       o.Entry,
+		Delivery: &ordersv1.Delivery{
+			Name:    o.Delivery.Name,
+			Phone:   o.Delivery.Phone,
+			Zip:     o.Delivery.Zip,
+			City:    o.Delivery.City,
+			Address: o.Delivery.Address,
+			Region:  o.Delivery.Region,
+			Email:   o.Delivery.Email,
+		},
+		Payment: &ordersv1.Payment{
+			Transaction:  o.Payment.Transaction,
+			RequestId:    o.Payment.RequestID,
+			Currency:     o.Payment.Currency,
+			Provider:     o.Payment.Provider,
+			Amount:       int64(o.Payment.Amount),
+			PaymentDt:    int64(o.Payment.PaymentDt),
+			Bank:         o.Payment.Bank,
+			DeliveryCost: int64(o.Payment.DeliveryCost),
+			GoodsTotal:   int64(o.Payment.GoodsTotal),
+			CustomFee:    int64(o.Payment.CustomFee),
+		},
+		Items:             items,
+		Locale:            o.Locale,
+		InternalSignature: o.InternalSignature,
+		CustomerId:        o.CustomerID,
+		DeliveryService:   o.DeliveryService,
+		Shardkey:          o.Shardkey,
+		SmId:              int64(o.SmID),
+		DateCreated:       timestamppb.New(o.DateCreated),
+		OofShard:          o.OofShard,
+	}
+}
+
+// orderFromProto разбирает заказ из запроса; отсутствующие вложенные сообщения
+// дают пустые поля, которые затем отклонит Validate
+func orderFromProto(p *ordersv1.Order) *models.Order {
+	o := &models.Order{
+		OrderUID:          p.GetOrderUid(),
+		TrackNumber:       p.GetTrackNumber(),
+		Entry:             p.GetEntry(),
+		Locale:            p.GetLocale(),
+		InternalSignature: p.GetInternalSignature(),
+		CustomerID:        p.GetCustomerId(),
+		DeliveryService:   p.GetDeliveryService(),
+		Shardkey:          p.GetShardkey(),
+		SmID:              int(p.GetSmId()),
+		OofShard:          p.GetOofShard(),
+	}
+	if p.GetDateCreated() != nil {
+		o.DateCreated = p.GetDateCreated().AsTime()
+	}
+	if d := p.GetDelivery(); d != nil {
+		o.Delivery = models.Delivery{
+			Name:    d.GetName(),
+			Phone:   d.GetPhone(),
+			Zip:     d.GetZip(),
+			City:    d.GetCity(),
+			Address: d.GetAddress(),
+			Region:  d.GetRegion(),
+			Email:   d.GetEmail(),
+		}
+	}
+	if pay := p.GetPayment(); pay != nil {
+		o.Payment = models.Payment{
+			Transaction:  pay.GetTransaction(),
+			RequestID:    pay.GetRequestId(),
+			Currency:     pay.GetCurrency(),
+			Provider:     pay.GetProvider(),
+			Amount:       int(pay.GetAmount()),
+			PaymentDt:    int(pay.GetPaymentDt()),
+			Bank:         pay.GetBank(),
+			DeliveryCost: int(pay.GetDeliveryCost()),
+			GoodsTotal:   int(pay.GetGoodsTotal()),
+			CustomFee:    int(pay.GetCustomFee()),
+		}
+	}
+	o.Items = make(models.Items, len(p.GetItems()))
+	for i, it := range p.GetItems() {
+		o.Items[i] = models.Item{
+			ChrtID:      int(it.GetChrtId()),
+			TrackNumber: it.GetTrackNumber(),
+			Price:       int(it.GetPrice()),
+			Rid:         it.GetRid(),
+			Name:        it.GetName(),
+			Sale:        int(it.GetSale()),
+			Size:        it.GetSize(),
+			TotalPrice:  int(it.GetTotalPrice()),
+			NmID:        int(it.GetNmId()),
+			Brand:       it.GetBrand(),
+			Status:      int(it.GetStatus()),
+		}
+	}
+	return o
+}
@@ -0,0 +1,109 @@
+package grpcapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"expvar"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// healthPrefix - методы health не требуют токена, чтобы их могли вызывать пробы
+const healthPrefix = "/grpc.health.v1.Health/"
+
+// Счётчики публикуются через expvar (GET /debug/vars HTTP-сервера, с HTTP_ADMIN_TOKEN):
+// grpc_requests - число вызовов по "<метод> <код>", grpc_latency_ms - суммарное время по методу,
+// grpc_streams_active - открытые потоки WatchOrders
+var (
+	requestsTotal = expvar.NewMap("grpc_requests")
+	latencyTotal  = expvar.NewMap("grpc_latency_ms")
+	activeStreams = expvar.NewInt("grpc_streams_active")
+)
+
+func observe(method string, err error, elapsed time.Duration) {
+	requestsTotal.Add(method+" "+status.Code(err).String(), 1)
+	latencyTotal.Add(method, elapsed.Milliseconds())
+}
+
+func metricsUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	observe(info.FullMethod, err, time.Since(start))
+	return resp, err
+}
+
+func metricsStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	activeStreams.Add(1)
+	defer activeStreams.Add(-1)
+	err := handler(srv, ss)
+	observe(info.FullMethod, err, time.Since(start))
+	return err
+}
+
+func logCall(method string, err error, elapsed time.Duration) {
+	entry := logrus.WithFields(logrus.Fields{
+		"method":   method,
+		"code":     status.Code(err).String(),
+		"duration": elapsed,
+	})
+	if status.Code(err) == codes.Internal || status.Code(err) == codes.Unknown {
+		entry.WithError(err).Error("grpc call failed")
+		return
+	}
+	entry.Info("grpc call")
+}
+
+func loggingUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	logCall(info.FullMethod, err, time.Since(start))
+	return resp, err
+}
+
+func loggingStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	logCall(info.FullMethod, err, time.Since(start))
+	return err
+}
+
+// authorize проверяет заголовок "authorization: Bearer <token>"; с пустым token
+// доступен только health (конфиг такой сервер не запустит)
+func authorize(ctx context.Context, method, token string) error {
+	if strings.HasPrefix(method, healthPrefix) {
+		return nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, v := range md.Get("authorization") {
+		got, ok := strings.CutPrefix(v, "Bearer ")
+		if ok && token != "" && subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1 {
+			return nil
+		}
+	}
+	return status.Error(codes.Unauthenticated, "missing or invalid bearer token")
+}
+
+func authUnary(token string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := authorize(ctx, info.FullMethod, token); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func authStream(token string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := authorize(ss.Context(), info.FullMethod, token); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,177 @@
+// Package grpcapi отдаёт заказы внутренним сервисам по gRPC (orders.v1.OrderService)
+// поверх того же service.Service, что и HTTP-ручки.
+package grpcapi
+
+import (
+	"L0-wb/config"
+	"L0-wb/internal/grpcapi/ordersv1"
+	"L0-wb/internal/models"
+	"L0-wb/internal/repo"
+	"L0-wb/internal/service"
+	"L0-wb/internal/service/feed"
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// maxListLimit - предел limit в ListOrders, как у выгрузки одной страницей
+const maxListLimit = 1000
+
+// Server - gRPC-сервер с OrderService, health и reflection
+type Server struct {
+	*grpc.Server
+	done chan struct{}
+}
+
+// NewServer собирает сервер. Перехватчики: метрики, логирование, затем проверка токена.
+func NewServer(cfg *config.Config, svc service.Service) *Server {
+	srv := &Server{
+		Server: grpc.NewServer(
+			grpc.ChainUnaryInterceptor(metricsUnary, loggingUnary, authUnary(cfg.GRPC.AuthToken)),
+			grpc.ChainStreamInterceptor(metricsStream, loggingStream, authStream(cfg.GRPC.AuthToken)),
+		),
+		done: make(chan struct{}),
+	}
+	ordersv1.RegisterOrderServiceServer(srv, &orderServer{service: svc, done: srv.done})
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	reflection.Register(srv)
+	return srv
+}
+
+// Shutdown завершает потоки WatchOrders, которые сами не закончатся, и ждёт
+// текущие вызовы; по истечении ctx обрывает оставшиеся соединения
+func (s *Server) Shutdown(ctx context.Context) {
+	close(s.done)
+	stopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		s.Stop()
+	}
+}
+
+type orderServer struct {
+	ordersv1.UnimplementedOrderServiceServer
+	service service.Service
+	done    <-chan struct{}
+}
+
+func (s *orderServer) GetOrder(ctx context.Context, req *ordersv1.GetOrderRequest) (*ordersv1.GetOrderResponse, error) {
+	uid := strings.TrimSpace(req.GetOrderUid())
+	if uid == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_uid cannot be empty")
+	}
+	order, err := s.service.GetOrderByUID(ctx, uid)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return &ordersv1.GetOrderResponse{Order: orderToProto(order)}, nil
+}
+
+func (s *orderServer) ListOrders(ctx context.Context, req *ordersv1.ListOrdersRequest) (*ordersv1.ListOrdersResponse, error) {
+	if req.GetLimit() < 0 || req.GetLimit() > maxListLimit || req.GetOffset() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "limit must be between 0 and %d, offset must not be negative", maxListLimit)
+	}
+	filter := models.OrderFilter{
+		CustomerID:      req.GetCustomerId(),
+		TrackNumber:     req.GetTrackNumber(),
+		Locale:          req.GetLocale(),
+		DeliveryService: req.GetDeliveryService(),
+		Limit:           int(req.GetLimit()),
+		Offset:          int(req.GetOffset()),
+	}
+	if req.GetCreatedFrom() != nil {
+		filter.CreatedFrom = req.GetCreatedFrom().AsTime()
+	}
+	if req.GetCreatedTo() != nil {
+		filter.CreatedTo = req.GetCreatedTo().AsTime()
+	}
+	if filter.Limit == 0 {
+		filter.Limit = models.DefaultListLimit
+	}
+
+	orders, err := s.service.ListOrders(ctx, filter)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	resp := &ordersv1.ListOrdersResponse{Orders: make([]*ordersv1.Order, len(orders))}
+	for i := range orders {
+		resp.Orders[i] = orderToProto(&orders[i])
+	}
+	return resp, nil
+}
+
+func (s *orderServer) CreateOrder(ctx context.Context, req *ordersv1.CreateOrderRequest) (*ordersv1.CreateOrderResponse, error) {
+	if req.GetOrder() == nil {
+		return nil, status.Error(codes.InvalidArgument, "order is required")
+	}
+	order := orderFromProto(req.GetOrder())
+	if err := order.Validate(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid order: %v", err)
+	}
+	if err := s.service.CreateOrder(ctx, order); err != nil {
+		if repo.IsDuplicate(err) {
+			return nil, status.Errorf(codes.AlreadyExists, "order %s already exists", order.OrderUID)
+		}
+		return nil, statusFromError(err)
+	}
+	return &ordersv1.CreateOrderResponse{Order: orderToProto(order)}, nil
+}
+
+// WatchOrders пересылает заказы из feed. Подписка отдаёт укороченный
+// OrderResponse, поэтому полный заказ берётся через сервис - обычно из кэша,
+// куда он попадает до публикации.
+func (s *orderServer) WatchOrders(req *ordersv1.WatchOrdersRequest, stream ordersv1.OrderService_WatchOrdersServer) error {
+	ctx := stream.Context()
+	sub := s.service.SubscribeOrders(feed.Filter{
+		DeliveryService: req.GetDeliveryService(),
+		CustomerID:      req.GetCustomerId(),
+	})
+	defer sub.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-s.done:
+			return status.Error(codes.Unavailable, "server is shutting down")
+		case o, ok := <-sub.C:
+			if !ok {
+				if errors.Is(sub.Err(), feed.ErrSlowSubscriber) {
+					return status.Error(codes.ResourceExhausted, "client is too slow, resubscribe")
+				}
+				return status.Error(codes.Unavailable, "server is shutting down")
+			}
+			order, err := s.service.GetOrderByUID(ctx, o.OrderUID)
+			if err != nil {
+				logrus.WithError(err).WithField("order_uid", o.OrderUID).Warn("watch: failed to load saved order")
+				continue
+			}
+			if err := stream.Send(&ordersv1.WatchOrdersResponse{Order: orderToProto(order)}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func statusFromError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		return status.Error(codes.NotFound, "order not found")
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	}
+	return status.Error(codes.Internal, "internal server error")
+}
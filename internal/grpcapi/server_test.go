@@ -0,0 +1,189 @@
+package grpcapi
+
+import (
+	"L0-wb/config"
+	"L0-wb/internal/grpcapi/ordersv1"
+	"L0-wb/internal/mocks"
+	"L0-wb/internal/models"
+	"L0-wb/internal/service"
+	"L0-wb/internal/service/feed"
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const testToken = "internal-token"
+
+func startServer(t *testing.T, svc service.Service) (*Server, *grpc.ClientConn) {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	cfg := &config.Config{GRPC: config.GRPC{AuthToken: testToken}}
+	srv := NewServer(cfg, svc)
+	go func() { _ = srv.Serve(lis) }()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		conn.Close()
+		srv.Stop()
+	})
+	return srv, conn
+}
+
+func authorized() context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+testToken)
+}
+
+func testOrder() *models.Order {
+	return &models.Order{
+		OrderUID:        "test-123",
+		TrackNumber:     "WBIL123456789",
+		Entry:           "WBIL",
+		CustomerID:      "customer-1",
+		DeliveryService: "meest",
+		DateCreated:     time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC),
+		Delivery:        models.Delivery{Name: "Test User", Phone: "+79991234567", Zip: "123456", City: "Moscow", Address: "Test St, 1"},
+		Payment:         models.Payment{Transaction: "tx-123", Currency: "USD", Provider: "stripe", Amount: 100},
+		Items:           models.Items{{ChrtID: 7, TrackNumber: "WBIL123456789", Price: 100, Name: "Item", TotalPrice: 100}},
+	}
+}
+
+func TestGetOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := mocks.NewMockService(ctrl)
+	_, conn := startServer(t, mockService)
+	client := ordersv1.NewOrderServiceClient(conn)
+
+	mockService.EXPECT().GetOrderByUID(gomock.Any(), "test-123").Return(testOrder(), nil)
+	resp, err := client.GetOrder(authorized(), &ordersv1.GetOrderRequest{OrderUid: "test-123"})
+	require.NoError(t, err)
+	assert.Equal(t, testOrder(), orderFromProto(resp.GetOrder()))
+
+	mockService.EXPECT().GetOrderByUID(gomock.Any(), "missing").Return(nil, service.ErrNotFound)
+	_, err = client.GetOrder(authorized(), &ordersv1.GetOrderRequest{OrderUid: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.GetOrder(authorized(), &ordersv1.GetOrderRequest{OrderUid: " "})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestAuth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	_, conn := startServer(t, mocks.NewMockService(ctrl))
+
+	_, err := ordersv1.NewOrderServiceClient(conn).GetOrder(context.Background(), &ordersv1.GetOrderRequest{OrderUid: "test-123"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	wrong := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer other")
+	_, err = ordersv1.NewOrderServiceClient(conn).GetOrder(wrong, &ordersv1.GetOrderRequest{OrderUid: "test-123"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// health доступен пробам без токена
+	resp, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+}
+
+func TestListOrders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := mocks.NewMockService(ctrl)
+	_, conn := startServer(t, mockService)
+	client := ordersv1.NewOrderServiceClient(conn)
+
+	from := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	mockService.EXPECT().ListOrders(gomock.Any(), models.OrderFilter{
+		DeliveryService: "meest",
+		CreatedFrom:     from,
+		Limit:           models.DefaultListLimit,
+	}).Return([]models.Order{*testOrder()}, nil)
+
+	resp, err := client.ListOrders(authorized(), &ordersv1.ListOrdersRequest{
+		DeliveryService: "meest",
+		CreatedFrom:     timestamppb.New(from),
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.GetOrders(), 1)
+	assert.Equal(t, "test-123", resp.GetOrders()[0].GetOrderUid())
+
+	_, err = client.ListOrders(authorized(), &ordersv1.ListOrdersRequest{Limit: maxListLimit + 1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestCreateOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := mocks.NewMockService(ctrl)
+	_, conn := startServer(t, mockService)
+	client := ordersv1.NewOrderServiceClient(conn)
+
+	mockService.EXPECT().CreateOrder(gomock.Any(), testOrder()).Return(nil)
+	resp, err := client.CreateOrder(authorized(), &ordersv1.CreateOrderRequest{Order: orderToProto(testOrder())})
+	require.NoError(t, err)
+	assert.Equal(t, "test-123", resp.GetOrder().GetOrderUid())
+
+	mockService.EXPECT().CreateOrder(gomock.Any(), gomock.Any()).
+		Return(fmt.Errorf("failed to create order: %w", &pq.Error{Code: "23505"}))
+	_, err = client.CreateOrder(authorized(), &ordersv1.CreateOrderRequest{Order: orderToProto(testOrder())})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	invalid := orderToProto(testOrder())
+	invalid.Items = nil
+	_, err = client.CreateOrder(authorized(), &ordersv1.CreateOrderRequest{Order: invalid})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.CreateOrder(authorized(), &ordersv1.CreateOrderRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestWatchOrders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := mocks.NewMockService(ctrl)
+	srv, conn := startServer(t, mockService)
+	client := ordersv1.NewOrderServiceClient(conn)
+
+	hub := feed.New(4)
+	defer hub.Close()
+	subscribed := make(chan struct{})
+	mockService.EXPECT().SubscribeOrders(feed.Filter{DeliveryService: "meest"}).DoAndReturn(
+		func(f feed.Filter) *feed.Subscription {
+			defer close(subscribed)
+			return hub.Subscribe(f)
+		})
+	mockService.EXPECT().GetOrderByUID(gomock.Any(), "test-123").Return(testOrder(), nil)
+
+	stream, err := client.WatchOrders(authorized(), &ordersv1.WatchOrdersRequest{DeliveryService: "meest"})
+	require.NoError(t, err)
+	<-subscribed
+
+	other := testOrder()
+	other.OrderUID, other.DeliveryService = "other", "dhl"
+	hub.Publish(other, testOrder())
+
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "test-123", resp.GetOrder().GetOrderUid())
+	assert.Equal(t, int64(7), resp.GetOrder().GetItems()[0].GetChrtId())
+
+	// остановка сервера завершает бесконечный поток
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	srv.Shutdown(ctx)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
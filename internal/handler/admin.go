@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"L0-wb/config"
+	"net/http"
+	"strconv"
+)
+
+// CacheStats отдаёт счётчики кэша заказов
+func (h *UserHandler) CacheStats(w http.ResponseWriter, r *http.Request) {
+	writeData(w, http.StatusOK, h.service.CacheStats())
+}
+
+// WarmCache загружает в кэш последние заказы; количество задаётся ?limit=,
+// по умолчанию ORDERS_LIMIT или размер кэша
+func (h *UserHandler) WarmCache(w http.ResponseWriter, r *http.Request) {
+	limit := config.GetLimitCache()
+	if limit <= 0 {
+		limit = config.GetCacheStartupSize()
+	}
+	if s := r.URL.Query().Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			writeProblem(w, r, http.StatusBadRequest, CodeInvalidArgument, "limit must be a positive integer")
+			return
+		}
+		limit = n
+	}
+
+	loaded, err := h.service.WarmCache(r.Context(), limit)
+	if err != nil {
+		writeInternalError(w, r)
+		return
+	}
+
+	writeData(w, http.StatusOK, map[string]interface{}{
+		"loaded": loaded,
+		"cache":  h.service.CacheStats(),
+	})
+}
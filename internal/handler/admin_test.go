@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"L0-wb/internal/cache"
+	"L0-wb/internal/mocks"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	h := NewHandler(mockService)
+
+	mockService.EXPECT().CacheStats().Return(cache.Stats{Size: 3, Capacity: 10, Hits: 5})
+
+	w := httptest.NewRecorder()
+	h.CacheStats(w, httptest.NewRequest(http.MethodGet, "/admin/cache/stats", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Status string      `json:"status"`
+		Data   cache.Stats `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, cache.Stats{Size: 3, Capacity: 10, Hits: 5}, response.Data)
+}
+
+func TestWarmCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	h := NewHandler(mockService)
+
+	tests := []struct {
+		name       string
+		url        string
+		setup      func()
+		wantStatus int
+	}{{
+		name: "explicit limit",
+		url:  "/admin/cache/warm?limit=5",
+		setup: func() {
+			mockService.EXPECT().WarmCache(gomock.Any(), 5).Return(5, nil)
+			mockService.EXPECT().CacheStats().Return(cache.Stats{Size: 5})
+		},
+		wantStatus: http.StatusOK,
+	}, {
+		name:       "invalid limit",
+		url:        "/admin/cache/warm?limit=-1",
+		setup:      func() {},
+		wantStatus: http.StatusBadRequest,
+	}, {
+		name: "repository error",
+		url:  "/admin/cache/warm?limit=5",
+		setup: func() {
+			mockService.EXPECT().WarmCache(gomock.Any(), 5).Return(0, errors.New("db down"))
+		},
+		wantStatus: http.StatusInternalServerError,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			w := httptest.NewRecorder()
+			h.WarmCache(w, httptest.NewRequest(http.MethodPost, tt.url, nil))
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
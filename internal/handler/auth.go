@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// adminPaths - служебные ручки. Они требуют HTTP_ADMIN_TOKEN и не получают
+// CORS-заголовков: браузеры других источников к ним не допускаются.
+var adminPaths = []string{
+	apiPrefix + "/admin/cache",
+	apiPrefix + "/admin/webhooks",
+	apiPrefix + "/orders/export",
+	"/debug/vars",
+}
+
+// isAdminPath проверяет путь запроса; mux сопоставляет маршруты по тому же r.URL.Path
+func isAdminPath(p string) bool {
+	for _, admin := range adminPaths {
+		if p == admin || strings.HasPrefix(p, admin+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+type adminCtxKey struct{}
+
+// isAdminRequest сообщает, что запрос пришёл с верным токеном администратора.
+// Так публичные ручки (GraphQL) открывают часть данных только администратору.
+func isAdminRequest(r *http.Request) bool {
+	admin, _ := r.Context().Value(adminCtxKey{}).(bool)
+	return admin
+}
+
+func hasAdminToken(r *http.Request, token string) bool {
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && token != "" && subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+}
+
+// adminAuthMiddleware пропускает к служебным ручкам только запросы с заголовком
+// "Authorization: Bearer <token>". Пустой token отключает служебные ручки совсем.
+// Остальные ручки доступны всем; верный токен отмечается в контексте запроса.
+func adminAuthMiddleware(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !isAdminPath(r.URL.Path) {
+				if hasAdminToken(r, token) {
+					r = r.WithContext(context.WithValue(r.Context(), adminCtxKey{}, true))
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+			if token == "" {
+				writeProblem(w, r, http.StatusForbidden, CodeForbidden, "admin API is disabled: HTTP_ADMIN_TOKEN is not set")
+				return
+			}
+			if !hasAdminToken(r, token) {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+				writeProblem(w, r, http.StatusUnauthorized, CodeUnauthorized, "missing or invalid bearer token")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"L0-wb/config"
+	"L0-wb/internal/mocks"
+	"L0-wb/internal/models"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsAdminPath(t *testing.T) {
+	tests := map[string]bool{
+		"/api/v1/admin/webhooks":              true,
+		"/api/v1/admin/webhooks/1/deliveries": true,
+		"/api/v1/admin/webhooksx":             false,
+		"/api/v1/admin/cache/stats":           true,
+		"/api/v1/admin/cache/warm":            true,
+		"/debug/vars":                         true,
+		"/api/v1/orders/export":               true,
+		"/api/v1/orders":                      false,
+		"/api/v1/order/uid-1":                 false,
+		"/":                                   false,
+	}
+	for p, want := range tests {
+		assert.Equal(t, want, isAdminPath(p), p)
+	}
+}
+
+func TestAdminAuth(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		method string
+		path   string
+		auth   string
+		status int
+		cors   bool
+	}{
+		{name: "disabled without token", method: http.MethodGet, path: "/api/v1/admin/webhooks", auth: "Bearer x", status: http.StatusForbidden},
+		{name: "missing header", token: "secret", method: http.MethodGet, path: "/api/v1/admin/webhooks", status: http.StatusUnauthorized},
+		{name: "wrong token", token: "secret", method: http.MethodGet, path: "/api/v1/admin/webhooks", auth: "Bearer other", status: http.StatusUnauthorized},
+		{name: "wrong scheme", token: "secret", method: http.MethodGet, path: "/api/v1/admin/webhooks", auth: "Basic secret", status: http.StatusUnauthorized},
+		{name: "valid token", token: "secret", method: http.MethodGet, path: "/api/v1/admin/webhooks", auth: "Bearer secret", status: http.StatusOK},
+		{name: "expvar", token: "secret", method: http.MethodGet, path: "/debug/vars", status: http.StatusUnauthorized},
+		{name: "no legacy alias", token: "secret", method: http.MethodGet, path: "/admin/webhooks", auth: "Bearer secret", status: http.StatusNotFound, cors: true},
+		{name: "admin preflight", token: "secret", method: http.MethodOptions, path: "/api/v1/admin/webhooks", status: http.StatusForbidden},
+		{name: "public preflight", token: "secret", method: http.MethodOptions, path: "/api/v1/order/uid-1", status: http.StatusOK, cors: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockService(ctrl)
+			mockService.EXPECT().ListWebhooks(gomock.Any()).Return([]models.WebhookSubscription{}, nil).AnyTimes()
+			srv := NewServer(&config.Config{HTTPServer: config.HTTPServer{AdminToken: tt.token}}, NewHandler(mockService))
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			w := httptest.NewRecorder()
+			srv.Handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code, w.Body.String())
+			if tt.status == http.StatusUnauthorized {
+				assert.Equal(t, `Bearer realm="admin"`, w.Header().Get("WWW-Authenticate"))
+			}
+			if tt.cors {
+				assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+			} else {
+				assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+			}
+		})
+	}
+}
+
+func TestGraphQL_OrdersRequireAdmin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	mockService.EXPECT().ListOrderHeaders(gomock.Any(), gomock.Any()).Return([]models.Order{{OrderUID: "a"}}, nil).Times(1)
+	srv := NewServer(&config.Config{HTTPServer: config.HTTPServer{AdminToken: "secret"}}, NewHandler(mockService))
+
+	for _, auth := range []string{"", "Bearer other", "Bearer secret"} {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/graphql", strings.NewReader(`{"query":"{ orders { order_uid } }"}`))
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		w := httptest.NewRecorder()
+		srv.Handler.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		if auth == "Bearer secret" {
+			assert.JSONEq(t, `{"data":{"orders":[{"order_uid":"a"}]}}`, w.Body.String())
+		} else {
+			assert.Contains(t, w.Body.String(), "orders requires the admin token", auth)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"L0-wb/internal/models"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	// batchGetMaxUIDs - сколько заказов можно запросить за раз
+	batchGetMaxUIDs = 100
+	batchGetMaxBody = 64 << 10
+)
+
+type batchGetRequest struct {
+	OrderUIDs []string `json:"order_uids"`
+}
+
+type batchGetResponse struct {
+	Orders  []*models.Order `json:"orders"`
+	Missing []string        `json:"missing"`
+}
+
+// BatchGetOrders возвращает несколько заказов за один запрос:
+// найденные в порядке запроса и список order_uid, которых нет
+func (h *UserHandler) BatchGetOrders(w http.ResponseWriter, r *http.Request) {
+	var req batchGetRequest
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, batchGetMaxBody))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeInvalidBody, "invalid JSON body: "+err.Error())
+		return
+	}
+
+	uids := make([]string, 0, len(req.OrderUIDs))
+	for _, uid := range req.OrderUIDs {
+		uid = strings.TrimSpace(uid)
+		if uid == "" {
+			writeProblem(w, r, http.StatusBadRequest, CodeInvalidArgument, "order_uids cannot contain empty values")
+			return
+		}
+		uids = append(uids, uid)
+	}
+	if len(uids) == 0 || len(uids) > batchGetMaxUIDs {
+		writeProblem(w, r, http.StatusBadRequest, CodeInvalidArgument,
+			fmt.Sprintf("order_uids must contain from 1 to %d values", batchGetMaxUIDs))
+		return
+	}
+
+	orders, missing, err := h.service.BatchGetOrders(r.Context(), uids)
+	if err != nil {
+		writeInternalError(w, r)
+		return
+	}
+	writeData(w, http.StatusOK, batchGetResponse{Orders: orders, Missing: missing})
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"L0-wb/internal/mocks"
+	"L0-wb/internal/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchGetOrders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	h := NewHandler(mockService)
+
+	tooMany := make([]string, batchGetMaxUIDs+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("%q", fmt.Sprint(i))
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		setup      func()
+		wantStatus int
+		wantCode   string
+	}{{
+		name: "found and missing",
+		body: `{"order_uids":[" a ","b"]}`,
+		setup: func() {
+			mockService.EXPECT().BatchGetOrders(gomock.Any(), []string{"a", "b"}).
+				Return([]*models.Order{{OrderUID: "a"}}, []string{"b"}, nil)
+		},
+		wantStatus: http.StatusOK,
+	}, {
+		name:       "empty list",
+		body:       `{"order_uids":[]}`,
+		setup:      func() {},
+		wantStatus: http.StatusBadRequest,
+		wantCode:   CodeInvalidArgument,
+	}, {
+		name:       "too many",
+		body:       `{"order_uids":[` + strings.Join(tooMany, ",") + `]}`,
+		setup:      func() {},
+		wantStatus: http.StatusBadRequest,
+		wantCode:   CodeInvalidArgument,
+	}, {
+		name:       "blank uid",
+		body:       `{"order_uids":["a","  "]}`,
+		setup:      func() {},
+		wantStatus: http.StatusBadRequest,
+		wantCode:   CodeInvalidArgument,
+	}, {
+		name:       "unknown field",
+		body:       `{"uids":["a"]}`,
+		setup:      func() {},
+		wantStatus: http.StatusBadRequest,
+		wantCode:   CodeInvalidBody,
+	}, {
+		name: "service error",
+		body: `{"order_uids":["a"]}`,
+		setup: func() {
+			mockService.EXPECT().BatchGetOrders(gomock.Any(), []string{"a"}).Return(nil, nil, errors.New("db down"))
+		},
+		wantStatus: http.StatusInternalServerError,
+		wantCode:   CodeInternal,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/orders:batchGet", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			h.BatchGetOrders(w, req)
+			require.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+
+			if tt.wantCode != "" {
+				var p Problem
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+				assert.Equal(t, tt.wantCode, p.Code)
+				return
+			}
+			var resp struct {
+				Data struct {
+					Orders  []models.Order `json:"orders"`
+					Missing []string       `json:"missing"`
+				} `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			require.Len(t, resp.Data.Orders, 1)
+			assert.Equal(t, "a", resp.Data.Orders[0].OrderUID)
+			assert.Equal(t, []string{"b"}, resp.Data.Missing)
+		})
+	}
+}
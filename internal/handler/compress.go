@@ -0,0 +1,239 @@
+package handler
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+)
+
+// compressMinSize - ответы короче сжимать невыгодно
+const compressMinSize = 1024
+
+// compressSkipTypes - типы, которые уже сжаты или должны уходить клиенту сразу
+var compressSkipTypes = []string{
+	"text/event-stream",
+	"application/vnd.apache.parquet",
+	"application/gzip",
+	"application/zip",
+	"image/",
+	"video/",
+}
+
+var (
+	gzipPool   = sync.Pool{New: func() interface{} { return gzip.NewWriter(io.Discard) }}
+	brotliPool = sync.Pool{New: func() interface{} { return brotli.NewWriterLevel(io.Discard, brotli.DefaultCompression) }}
+)
+
+// compressMiddleware сжимает ответы в br или gzip по Accept-Encoding.
+// WebSocket и HEAD пропускаются без обёртки.
+func compressMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead || r.Header.Get("Upgrade") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Add("Vary", "Accept-Encoding")
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressWriter{ResponseWriter: w, encoding: encoding, ifNoneMatch: r.Header.Get("If-None-Match")}
+		next.ServeHTTP(cw, r)
+		// не через defer: при панике (обрыв выгрузки) поток не должен выглядеть завершённым
+		_ = cw.Close()
+	})
+}
+
+// negotiateEncoding выбирает br или gzip с наибольшим q; при равном q - br
+func negotiateEncoding(header string) string {
+	best, bestQ := "", 0.0
+	q := map[string]float64{}
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		weight := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				weight = f
+			}
+		}
+		q[name] = weight
+	}
+	for _, enc := range []string{"br", "gzip"} {
+		weight, ok := q[enc]
+		if !ok {
+			weight, ok = q["*"]
+		}
+		if ok && weight > bestQ {
+			best, bestQ = enc, weight
+		}
+	}
+	return best
+}
+
+// compressWriter копит начало ответа до compressMinSize и только тогда решает,
+// сжимать ли его. Flush решает сразу: потоковые ответы сжимаются частями.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding    string
+	ifNoneMatch string
+
+	status  int
+	buf     []byte
+	decided bool
+	enc     io.WriteCloser // nil - ответ идёт без сжатия
+}
+
+func (cw *compressWriter) WriteHeader(status int) {
+	if cw.status == 0 {
+		cw.status = status
+	}
+}
+
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if cw.status == 0 {
+		cw.status = http.StatusOK
+	}
+	if !cw.decided {
+		if !cw.compressible() {
+			if err := cw.start(false); err != nil {
+				return 0, err
+			}
+		} else {
+			cw.buf = append(cw.buf, p...)
+			if len(cw.buf) < compressMinSize {
+				return len(p), nil
+			}
+			return len(p), cw.start(true)
+		}
+	}
+	if cw.enc != nil {
+		return cw.enc.Write(p)
+	}
+	return cw.ResponseWriter.Write(p)
+}
+
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		if cw.status == 0 {
+			cw.status = http.StatusOK
+		}
+		_ = cw.start(cw.compressible())
+	}
+	if f, ok := cw.enc.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Close дописывает сжатый поток или отправляет короткий ответ как есть
+func (cw *compressWriter) Close() error {
+	if !cw.decided {
+		if cw.status == 0 {
+			return nil
+		}
+		return cw.start(false)
+	}
+	if cw.enc == nil {
+		return nil
+	}
+	err := cw.enc.Close()
+	switch enc := cw.enc.(type) {
+	case *gzip.Writer:
+		gzipPool.Put(enc)
+	case *brotli.Writer:
+		brotliPool.Put(enc)
+	}
+	cw.enc = nil
+	return err
+}
+
+// Unwrap нужен http.ResponseController
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+func (cw *compressWriter) compressible() bool {
+	h := cw.Header()
+	if cw.status < http.StatusOK || cw.status == http.StatusNoContent ||
+		cw.status == http.StatusNotModified || cw.status == http.StatusPartialContent {
+		return false
+	}
+	if h.Get("Content-Encoding") != "" {
+		return false
+	}
+	if n, err := strconv.Atoi(h.Get("Content-Length")); err == nil && n < compressMinSize {
+		return false
+	}
+	ct := h.Get("Content-Type")
+	for _, skip := range compressSkipTypes {
+		if strings.HasPrefix(ct, skip) {
+			return false
+		}
+	}
+	return true
+}
+
+// etagListed проверяет, есть ли etag в списке If-None-Match без учёта W/
+func etagListed(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// start отправляет заголовки и накопленное начало ответа
+func (cw *compressWriter) start(compress bool) error {
+	cw.decided = true
+	h := cw.Header()
+	// сжатое представление - другое представление, поэтому сильный ETag получает
+	// суффикс кодировки, но только если тело действительно сжимается: короткий
+	// ответ уходит как есть и сохраняет ETag несжатого. У 304 тела нет, поэтому
+	// суффикс остаётся, только если клиент сам прислал ETag сжатого представления.
+	if etag := h.Get("ETag"); strings.HasPrefix(etag, `"`) {
+		encoded := strings.TrimSuffix(etag, `"`) + "-" + cw.encoding + `"`
+		if (compress && cw.status == http.StatusOK) ||
+			(cw.status == http.StatusNotModified && etagListed(cw.ifNoneMatch, encoded)) {
+			h.Set("ETag", encoded)
+		}
+	}
+	if compress {
+		h.Del("Content-Length")
+		h.Set("Content-Encoding", cw.encoding)
+		switch cw.encoding {
+		case "br":
+			enc := brotliPool.Get().(*brotli.Writer)
+			enc.Reset(cw.ResponseWriter)
+			cw.enc = enc
+		default:
+			enc := gzipPool.Get().(*gzip.Writer)
+			enc.Reset(cw.ResponseWriter)
+			cw.enc = enc
+		}
+	}
+	cw.ResponseWriter.WriteHeader(cw.status)
+
+	buf := cw.buf
+	cw.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	var err error
+	if cw.enc != nil {
+		_, err = cw.enc.Write(buf)
+	} else {
+		_, err = cw.ResponseWriter.Write(buf)
+	}
+	return err
+}
@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"L0-wb/config"
+	"L0-wb/internal/mocks"
+	"L0-wb/internal/models"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := map[string]string{
+		"":                         "",
+		"identity":                 "",
+		"gzip":                     "gzip",
+		"gzip, deflate, br":        "br",
+		"br;q=0.5, gzip":           "gzip",
+		"br;q=0, gzip;q=0":         "",
+		"*":                        "br",
+		"*;q=0.3, gzip;q=0.8":      "gzip",
+		"GZIP;q=1.0, BR;q=0.9":     "gzip",
+		"deflate, gzip;q=invalid ": "gzip",
+	}
+	for header, want := range tests {
+		assert.Equal(t, want, negotiateEncoding(header), header)
+	}
+}
+
+func TestOrderETagAndCompression(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	srv := NewServer(&config.Config{}, NewHandler(mockService))
+
+	order := &models.Order{OrderUID: "uid-1", DateCreated: time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)}
+	for i := 0; i < 20; i++ {
+		order.Items = append(order.Items, models.Item{Name: "Mascaras", Brand: "Vivienne Sabo", Size: "0"})
+	}
+	mockService.EXPECT().GetOrderByUID(gomock.Any(), "uid-1").Return(order, nil).AnyTimes()
+
+	get := func(headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/order/uid-1", nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		srv.Handler.ServeHTTP(w, req)
+		return w
+	}
+
+	plain := get(nil)
+	require.Equal(t, http.StatusOK, plain.Code)
+	etag := plain.Header().Get("ETag")
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, etag)
+	assert.Equal(t, orderCacheControl, plain.Header().Get("Cache-Control"))
+	assert.Empty(t, plain.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", plain.Header().Get("Vary"))
+
+	t.Run("not modified", func(t *testing.T) {
+		w := get(map[string]string{"If-None-Match": `"other", ` + etag})
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.String())
+		assert.Equal(t, etag, w.Header().Get("ETag"))
+
+		w = get(map[string]string{"If-None-Match": `"other"`})
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	for _, tt := range []struct {
+		encoding string
+		reader   func(io.Reader) (io.Reader, error)
+	}{
+		{"gzip", func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) }},
+		{"br", func(r io.Reader) (io.Reader, error) { return brotli.NewReader(r), nil }},
+	} {
+		t.Run(tt.encoding, func(t *testing.T) {
+			w := get(map[string]string{"Accept-Encoding": tt.encoding})
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.encoding, w.Header().Get("Content-Encoding"))
+			assert.Less(t, w.Body.Len(), plain.Body.Len())
+
+			zetag := w.Header().Get("ETag")
+			assert.Equal(t, strings.TrimSuffix(etag, `"`)+"-"+tt.encoding+`"`, zetag)
+
+			r, err := tt.reader(w.Body)
+			require.NoError(t, err)
+			body, err := io.ReadAll(r)
+			require.NoError(t, err)
+			assert.Equal(t, plain.Body.String(), string(body))
+
+			// клиент присылает ETag сжатого представления
+			w = get(map[string]string{"Accept-Encoding": tt.encoding, "If-None-Match": zetag})
+			assert.Equal(t, http.StatusNotModified, w.Code)
+			assert.Equal(t, zetag, w.Header().Get("ETag"))
+			assert.Empty(t, w.Header().Get("Content-Encoding"))
+		})
+	}
+}
+
+func TestRenderedOrders(t *testing.T) {
+	c := newRenderedOrders()
+	order := &models.Order{OrderUID: "uid-1", TrackNumber: "WBIL1"}
+
+	body, etag, err := c.get(order)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `"track_number":"WBIL1"`)
+
+	// тот же объект из кэша сервиса не сериализуется заново
+	order.TrackNumber = "changed in place"
+	cached, cachedETag, err := c.get(order)
+	require.NoError(t, err)
+	assert.Equal(t, body, cached)
+	assert.Equal(t, etag, cachedETag)
+
+	// новый объект с тем же order_uid - новое тело и ETag
+	_, replacedETag, err := c.get(&models.Order{OrderUID: "uid-1", TrackNumber: "WBIL2"})
+	require.NoError(t, err)
+	assert.NotEqual(t, etag, replacedETag)
+
+	for i := 0; i < maxRenderedOrders+10; i++ {
+		_, _, err := c.get(&models.Order{OrderUID: fmt.Sprintf("uid-%d", i)})
+		require.NoError(t, err)
+	}
+	assert.Len(t, c.items, maxRenderedOrders)
+}
+
+// TestCompressMiddleware_ETag проверяет, что суффикс кодировки получает только
+// действительно сжатое тело: короткий ответ сохраняет ETag при любом Accept-Encoding
+func TestCompressMiddleware_ETag(t *testing.T) {
+	const etag = `"abc"`
+	handler := compressMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := "ok"
+		if r.URL.Path == "/large" {
+			body = strings.Repeat("order ", compressMinSize)
+		}
+		w.Header().Set("ETag", etag)
+		if etagMatch(r.Header.Get("If-None-Match"), etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		_, _ = io.WriteString(w, body)
+	}))
+
+	tests := []struct {
+		name        string
+		path        string
+		encoding    string
+		ifNoneMatch string
+		status      int
+		wantETag    string
+	}{
+		{name: "small plain", path: "/small", status: http.StatusOK, wantETag: etag},
+		{name: "small gzip", path: "/small", encoding: "gzip", status: http.StatusOK, wantETag: etag},
+		{name: "large gzip", path: "/large", encoding: "gzip", status: http.StatusOK, wantETag: `"abc-gzip"`},
+		{name: "small revalidated", path: "/small", encoding: "br", ifNoneMatch: etag, status: http.StatusNotModified, wantETag: etag},
+		{name: "large revalidated", path: "/large", encoding: "br", ifNoneMatch: `"abc-br"`, status: http.StatusNotModified, wantETag: `"abc-br"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.encoding != "" {
+				req.Header.Set("Accept-Encoding", tt.encoding)
+			}
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.wantETag, w.Header().Get("ETag"))
+		})
+	}
+}
+
+func TestCompressMiddleware_Skips(t *testing.T) {
+	handler := compressMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/small":
+			writeData(w, http.StatusOK, "ok")
+		case "/events":
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.WriteHeader(http.StatusOK)
+			_, _ = io.WriteString(w, strings.Repeat("data: x\n\n", 200))
+			w.(http.Flusher).Flush()
+		case "/stream":
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			_ = json.NewEncoder(w).Encode(map[string]string{"order_uid": "uid-1"})
+			require.NoError(t, http.NewResponseController(w).Flush())
+			_ = json.NewEncoder(w).Encode(map[string]string{"order_uid": "uid-2"})
+		}
+	}))
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve("/small")
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.JSONEq(t, `{"status":"ok","data":"ok"}`, w.Body.String())
+
+	w = serve("/events")
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.True(t, w.Flushed)
+
+	// Flush потокового ответа начинает сжатие, не дожидаясь compressMinSize
+	w = serve("/stream")
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	r, err := gzip.NewReader(w.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "{\"order_uid\":\"uid-1\"}\n{\"order_uid\":\"uid-2\"}\n", string(body))
+}
@@ -0,0 +1,264 @@
+package handler
+
+import (
+	"L0-wb/api"
+	"L0-wb/config"
+	"L0-wb/internal/cache"
+	"L0-wb/internal/mocks"
+	"L0-wb/internal/models"
+	"L0-wb/internal/service"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	// выгрузки проверяем как строки: схемы у них type: string
+	openapi3filter.RegisterBodyDecoder("text/csv", openapi3filter.FileBodyDecoder)
+	openapi3filter.RegisterBodyDecoder("application/x-ndjson", openapi3filter.FileBodyDecoder)
+}
+
+// specServerURL - сервер из api/openapi.yaml; роутер спецификации сверяет и хост
+const specServerURL = "http://localhost:8081"
+
+func loadSpecRouter(t *testing.T) (*openapi3.T, routers.Router) {
+	t.Helper()
+	doc, err := api.Load()
+	require.NoError(t, err)
+	router, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+	return doc, router
+}
+
+const testAdminToken = "test-admin-token"
+
+// TestContract прогоняет запросы через настоящий роутер сервиса и проверяет
+// запрос, код, заголовки и тело ответа по спецификации
+func TestContract(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	srv := NewServer(&config.Config{HTTPServer: config.HTTPServer{AdminToken: testAdminToken}}, NewHandler(mockService))
+	_, specRouter := loadSpecRouter(t)
+
+	created := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	order := &models.Order{
+		OrderUID:    "b563feb7b2b84b6test",
+		TrackNumber: "WBILMTESTTRACK",
+		Entry:       "WBIL",
+		Delivery:    models.Delivery{Name: "Test Testov", City: "Kiryat Mozkin"},
+		Payment:     models.Payment{Transaction: "b563feb7b2b84b6test", Currency: "USD", Amount: 1817},
+		Items:       models.Items{{ChrtID: 9934930, Name: "Mascaras", Brand: "Vivienne Sabo"}},
+		Locale:      "en",
+		DateCreated: created,
+	}
+	stats := models.OrderStats{
+		From: created, To: created.AddDate(0, 0, 1), Orders: 1, Revenue: 1817,
+		ByDay:     []models.StatsBucket{{Key: "2025-09-01", Orders: 1, Revenue: 1817}},
+		TopBrands: []models.ItemBucket{{Key: "Vivienne Sabo", Items: 1, Revenue: 317}},
+	}
+	sub := models.WebhookSubscription{ID: 1, URL: "https://partner.example/hook", Secret: "s3cr3t",
+		Events: []string{models.EventOrderSaved}, Active: true, CreatedAt: created}
+	delivered := created.Add(time.Second)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		header map[string]string
+		setup  func()
+		status int
+	}{
+		{name: "health", method: http.MethodGet, path: "/health", setup: func() {}, status: http.StatusOK},
+		{name: "order", method: http.MethodGet, path: "/api/v1/order/" + order.OrderUID, status: http.StatusOK,
+			setup: func() { mockService.EXPECT().GetOrderByUID(gomock.Any(), order.OrderUID).Return(order, nil) }},
+		{name: "order not found", method: http.MethodGet, path: "/api/v1/order/missing", status: http.StatusNotFound,
+			setup: func() {
+				mockService.EXPECT().GetOrderByUID(gomock.Any(), "missing").Return(nil, service.ErrNotFound)
+			}},
+		{name: "order not modified", method: http.MethodGet, path: "/api/v1/order/" + order.OrderUID,
+			header: map[string]string{"If-None-Match": "*", "Accept-Encoding": "gzip"},
+			status: http.StatusNotModified,
+			setup:  func() { mockService.EXPECT().GetOrderByUID(gomock.Any(), order.OrderUID).Return(order, nil) }},
+		{name: "legacy order", method: http.MethodGet, path: "/order/" + order.OrderUID, status: http.StatusOK,
+			setup: func() { mockService.EXPECT().GetOrderByUID(gomock.Any(), order.OrderUID).Return(order, nil) }},
+		{name: "legacy order not found", method: http.MethodGet, path: "/order/missing", status: http.StatusNotFound,
+			setup: func() {
+				mockService.EXPECT().GetOrderByUID(gomock.Any(), "missing").Return(nil, service.ErrNotFound)
+			}},
+		{name: "list orders", method: http.MethodGet, path: "/api/v1/orders?customer_id=test&limit=1", status: http.StatusOK,
+			setup: func() {
+				mockService.EXPECT().ListOrderHeaders(gomock.Any(), models.OrderFilter{CustomerID: "test", Limit: 2}).
+					Return([]models.Order{*order, {OrderUID: "next"}}, nil)
+				mockService.EXPECT().OrderParts(gomock.Any(), []string{order.OrderUID}, gomock.Any()).
+					Return(map[string]models.Order{order.OrderUID: *order}, nil)
+			}},
+		{name: "list orders bad limit", method: http.MethodGet, path: "/api/v1/orders?limit=1000", setup: func() {},
+			status: http.StatusBadRequest},
+		{name: "order timeline", method: http.MethodGet, path: "/api/v1/order/" + order.OrderUID + "/timeline",
+			status: http.StatusOK,
+			setup: func() {
+				mockService.EXPECT().OrderTimeline(gomock.Any(), order.OrderUID).Return([]models.TimelineEvent{
+					{Type: models.TimelineCreated, At: created},
+					{Type: models.TimelineWebhook, At: delivered, Status: models.WebhookDelivered, Detail: "order.saved"},
+				}, nil)
+			}},
+		{name: "order timeline not found", method: http.MethodGet, path: "/api/v1/order/missing/timeline",
+			status: http.StatusNotFound,
+			setup: func() {
+				mockService.EXPECT().OrderTimeline(gomock.Any(), "missing").Return(nil, service.ErrNotFound)
+			}},
+		{name: "batch get", method: http.MethodPost, path: "/api/v1/orders:batchGet", status: http.StatusOK,
+			body: `{"order_uids":["b563feb7b2b84b6test","missing"]}`,
+			setup: func() {
+				mockService.EXPECT().BatchGetOrders(gomock.Any(), []string{order.OrderUID, "missing"}).
+					Return([]*models.Order{order}, []string{"missing"}, nil)
+			}},
+		{name: "batch get empty", method: http.MethodPost, path: "/api/v1/orders:batchGet", status: http.StatusBadRequest,
+			body: `{"order_uids":[]}`, setup: func() {}},
+		{name: "export csv", method: http.MethodGet, path: "/api/v1/orders/export?format=csv&limit=1", status: http.StatusOK,
+			setup: func() {
+				mockService.EXPECT().StreamOrders(gomock.Any(), models.OrderFilter{Limit: 1}, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ models.OrderFilter, fn func(*models.Order) error) error {
+						return fn(order)
+					})
+			}},
+		{name: "export bad format", method: http.MethodGet, path: "/api/v1/orders/export?from=yesterday", setup: func() {},
+			status: http.StatusBadRequest},
+		{name: "stats", method: http.MethodGet, path: "/api/v1/stats?top=5", status: http.StatusOK,
+			setup: func() { mockService.EXPECT().OrderStats(gomock.Any(), gomock.Any()).Return(stats, nil) }},
+		{name: "stats daily", method: http.MethodGet, path: "/api/v1/stats/daily", status: http.StatusOK,
+			setup: func() { mockService.EXPECT().OrderStats(gomock.Any(), gomock.Any()).Return(stats, nil) }},
+		{name: "stats items", method: http.MethodGet, path: "/api/v1/stats/items", status: http.StatusOK,
+			setup: func() { mockService.EXPECT().OrderStats(gomock.Any(), gomock.Any()).Return(stats, nil) }},
+		{name: "graphql", method: http.MethodPost, path: "/api/v1/graphql", status: http.StatusOK,
+			body: `{"query":"{ order(order_uid: \"b563feb7b2b84b6test\") { order_uid locale } }"}`,
+			setup: func() {
+				mockService.EXPECT().GetOrderByUID(gomock.Any(), order.OrderUID).Return(order, nil)
+			}},
+		{name: "cache stats", method: http.MethodGet, path: "/api/v1/admin/cache/stats", status: http.StatusOK,
+			setup: func() { mockService.EXPECT().CacheStats().Return(cache.Stats{Size: 1, Capacity: 10}) }},
+		{name: "warm cache", method: http.MethodPost, path: "/api/v1/admin/cache/warm?limit=5", status: http.StatusOK,
+			setup: func() {
+				mockService.EXPECT().WarmCache(gomock.Any(), 5).Return(5, nil)
+				mockService.EXPECT().CacheStats().Return(cache.Stats{Size: 5, Capacity: 10})
+			}},
+		{name: "list webhooks", method: http.MethodGet, path: "/api/v1/admin/webhooks", status: http.StatusOK,
+			setup: func() {
+				mockService.EXPECT().ListWebhooks(gomock.Any()).Return([]models.WebhookSubscription{sub}, nil)
+			}},
+		{name: "create webhook", method: http.MethodPost, path: "/api/v1/admin/webhooks", status: http.StatusCreated,
+			body: `{"url":"https://partner.example/hook","events":["order.saved"]}`,
+			setup: func() {
+				mockService.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, s *models.WebhookSubscription) error {
+						s.ID, s.Secret, s.CreatedAt = sub.ID, sub.Secret, sub.CreatedAt
+						return nil
+					})
+			}},
+		{name: "create webhook invalid", method: http.MethodPost, path: "/api/v1/admin/webhooks", status: http.StatusBadRequest,
+			body: `{"url":"ftp://partner.example/hook"}`, setup: func() {}},
+		{name: "get webhook", method: http.MethodGet, path: "/api/v1/admin/webhooks/1", status: http.StatusOK,
+			setup: func() { mockService.EXPECT().GetWebhook(gomock.Any(), int64(1)).Return(sub, nil) }},
+		{name: "delete webhook", method: http.MethodDelete, path: "/api/v1/admin/webhooks/1", status: http.StatusOK,
+			setup: func() { mockService.EXPECT().DeleteWebhook(gomock.Any(), int64(1)).Return(nil) }},
+		{name: "webhook deliveries", method: http.MethodGet, path: "/api/v1/admin/webhooks/1/deliveries?limit=10",
+			status: http.StatusOK,
+			setup: func() {
+				mockService.EXPECT().WebhookDeliveries(gomock.Any(), int64(1), 10).Return([]models.WebhookDelivery{{
+					ID: 7, SubscriptionID: 1, EventType: models.EventOrderSaved, OrderUID: order.OrderUID,
+					Payload: json.RawMessage(`{"order_uid":"b563feb7b2b84b6test"}`), Status: models.WebhookDelivered,
+					Attempts: 1, NextAttemptAt: created, LastStatusCode: 200, CreatedAt: created, DeliveredAt: &delivered,
+				}}, nil)
+			}},
+		{name: "openapi", method: http.MethodGet, path: "/openapi.json", setup: func() {}, status: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			req := httptest.NewRequest(tt.method, specServerURL+tt.path, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			if isAdminPath(req.URL.Path) {
+				req.Header.Set("Authorization", "Bearer "+testAdminToken)
+			}
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			route, pathParams, err := specRouter.FindRoute(req)
+			require.NoError(t, err, "route is missing from the spec")
+
+			reqInput := &openapi3filter.RequestValidationInput{
+				Request:    req,
+				PathParams: pathParams,
+				Route:      route,
+				Options:    &openapi3filter.Options{MultiError: true, AuthenticationFunc: openapi3filter.NoopAuthenticationFunc},
+			}
+			// заведомо неверные запросы проверяем только по ответу
+			if tt.status != http.StatusBadRequest {
+				require.NoError(t, openapi3filter.ValidateRequest(context.Background(), reqInput))
+			}
+
+			// тело уже прочитано валидатором
+			req.Body = io.NopCloser(strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			srv.Handler.ServeHTTP(w, req)
+			require.Equal(t, tt.status, w.Code, w.Body.String())
+
+			err = openapi3filter.ValidateResponse(context.Background(), &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: reqInput,
+				Status:                 w.Code,
+				Header:                 w.Header(),
+				Body:                   io.NopCloser(w.Body),
+				Options:                &openapi3filter.Options{MultiError: true, IncludeResponseStatus: true},
+			})
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// TestContract_RoutesDocumented проверяет, что каждая ручка роутера описана в спецификации
+func TestContract_RoutesDocumented(t *testing.T) {
+	doc, _ := loadSpecRouter(t)
+	srv := NewServer(&config.Config{}, NewHandler(mocks.NewMockService(gomock.NewController(t))))
+
+	err := srv.Handler.(*mux.Router).Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil || (len(methods) == 1 && methods[0] == http.MethodOptions) {
+			// статика web/ без ограничения методов и CORS preflight
+			return nil
+		}
+		item := doc.Paths.Find(path)
+		if !assert.NotNil(t, item, "path %s is missing from the spec", path) {
+			return nil
+		}
+		for _, m := range methods {
+			assert.NotNil(t, item.GetOperation(m), "%s %s is missing from the spec", m, path)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"L0-wb/internal/models"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// orderCacheControl - заказы после сохранения не меняются, но содержат персональные
+// данные: кэшировать можно только клиенту, после минуты - перепроверка по ETag
+const orderCacheControl = "private, max-age=60"
+
+// maxRenderedOrders - сколько сериализованных заказов держит renderedOrders
+const maxRenderedOrders = 1024
+
+type renderedOrder struct {
+	order *models.Order
+	body  []byte
+	etag  string
+}
+
+// renderedOrders хранит тело ответа и ETag заказа, чтобы не сериализовать
+// и не хешировать его на каждый запрос. Запись действительна, пока сервис
+// отдаёт тот же *models.Order из кэша: новый объект (чтение из БД, перезапись
+// заказа) сериализуется заново.
+type renderedOrders struct {
+	mu    sync.Mutex
+	items map[string]renderedOrder
+}
+
+func newRenderedOrders() *renderedOrders {
+	return &renderedOrders{items: make(map[string]renderedOrder)}
+}
+
+func (c *renderedOrders) get(order *models.Order) ([]byte, string, error) {
+	c.mu.Lock()
+	item, ok := c.items[order.OrderUID]
+	c.mu.Unlock()
+	if ok && item.order == order {
+		return item.body, item.etag, nil
+	}
+
+	body, etag, err := renderWithETag(order)
+	if err != nil {
+		return nil, "", err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, exists := c.items[order.OrderUID]; !exists && len(c.items) >= maxRenderedOrders {
+		// вытесняем произвольную запись: промах стоит одной сериализации
+		for uid := range c.items {
+			delete(c.items, uid)
+			break
+		}
+	}
+	c.items[order.OrderUID] = renderedOrder{order: order, body: body, etag: etag}
+	return body, etag, nil
+}
+
+// renderWithETag сериализует ответ как writeData и считает сильный ETag от тела
+func renderWithETag(data interface{}) ([]byte, string, error) {
+	body, err := json.Marshal(envelope{Status: "ok", Data: data})
+	if err != nil {
+		return nil, "", err
+	}
+	body = append(body, '\n')
+	sum := sha256.Sum256(body)
+	return body, `"` + hex.EncodeToString(sum[:16]) + `"`, nil
+}
+
+// writeWithETag отдаёт готовое тело с ETag и 304 без тела, если клиент
+// прислал совпадающий If-None-Match
+func writeWithETag(w http.ResponseWriter, r *http.Request, body []byte, etag, cacheControl string) {
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", cacheControl)
+	if etagMatch(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+// etagMatch сравнивает If-None-Match с ETag слабым сравнением (RFC 9110, 13.1.2).
+// Суффикс кодировки, который добавляет compressMiddleware, не учитывается.
+func etagMatch(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		for _, enc := range []string{"br", "gzip"} {
+			if strings.HasSuffix(candidate, "-"+enc+`"`) {
+				candidate = strings.TrimSuffix(candidate, "-"+enc+`"`) + `"`
+				break
+			}
+		}
+		if candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"L0-wb/internal/export"
+	"L0-wb/internal/models"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ExportOrders выгружает заказы по фильтру в NDJSON, CSV или Parquet (?format=).
+// Ответ пишется потоково по мере чтения заказов из БД.
+func (h *UserHandler) ExportOrders(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	format, err := export.ParseFormat(query.Get("format"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeInvalidArgument, err.Error())
+		return
+	}
+	filter, err := orderFilterFromQuery(query)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeInvalidArgument, err.Error())
+		return
+	}
+
+	h.exportOrders(w, r, format, filter)
+}
+
+// exportWriteTimeout - сколько ждать клиента на одной порции выгрузки. Вся
+// выгрузка может идти дольше HTTP_TIMEOUT, но зависший клиент не держит
+// соединение и курсор БД дольше этого времени.
+const exportWriteTimeout = 30 * time.Second
+
+func (h *UserHandler) exportOrders(w http.ResponseWriter, r *http.Request, format export.Format, filter models.OrderFilter) {
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="orders-%s.%s"`,
+		time.Now().UTC().Format("20060102-150405"), format.Ext()))
+
+	out := &writeTracker{w: w, rc: http.NewResponseController(w)}
+	out.extendDeadline()
+	ew, err := export.NewWriter(out, format)
+	if err == nil {
+		err = h.service.StreamOrders(r.Context(), filter, ew.Write)
+		if err == nil {
+			err = ew.Close()
+		}
+	}
+	if err == nil {
+		return
+	}
+
+	log.Printf("export orders: %v", err)
+	if !out.written {
+		w.Header().Del("Content-Disposition")
+		writeInternalError(w, r)
+		return
+	}
+	// заголовки уже отправлены: обрываем соединение, чтобы обрезанный файл не приняли за целый
+	panic(http.ErrAbortHandler)
+}
+
+// writeTracker запоминает, начался ли ответ, и перед каждой порцией
+// продлевает срок записи на exportWriteTimeout
+type writeTracker struct {
+	w       http.ResponseWriter
+	rc      *http.ResponseController
+	written bool
+}
+
+func (t *writeTracker) Write(p []byte) (int, error) {
+	t.extendDeadline()
+	t.written = true
+	return t.w.Write(p)
+}
+
+func (t *writeTracker) extendDeadline() {
+	// без поддержки дедлайнов (тесты) остаётся HTTP_TIMEOUT сервера
+	_ = t.rc.SetWriteDeadline(time.Now().Add(exportWriteTimeout))
+}
+
+// orderFilterFromQuery разбирает фильтр заказов из параметров запроса
+func orderFilterFromQuery(q url.Values) (models.OrderFilter, error) {
+	filter := models.OrderFilter{
+		CustomerID:      q.Get("customer_id"),
+		TrackNumber:     q.Get("track_number"),
+		Locale:          q.Get("locale"),
+		DeliveryService: q.Get("delivery_service"),
+	}
+
+	var err error
+	if s := q.Get("from"); s != "" {
+		if filter.CreatedFrom, err = time.Parse(time.RFC3339, s); err != nil {
+			return filter, fmt.Errorf("from must be RFC3339 time")
+		}
+	}
+	if s := q.Get("to"); s != "" {
+		if filter.CreatedTo, err = time.Parse(time.RFC3339, s); err != nil {
+			return filter, fmt.Errorf("to must be RFC3339 time")
+		}
+	}
+	if s := q.Get("limit"); s != "" {
+		if filter.Limit, err = strconv.Atoi(s); err != nil || filter.Limit < 0 {
+			return filter, fmt.Errorf("limit must be a non-negative integer")
+		}
+	}
+	return filter, nil
+}
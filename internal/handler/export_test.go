@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"L0-wb/internal/mocks"
+	"L0-wb/internal/models"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportOrders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	h := NewHandler(mockService)
+
+	from := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	mockService.EXPECT().
+		StreamOrders(gomock.Any(), models.OrderFilter{Locale: "ru", CreatedFrom: from, Limit: 10}, gomock.Any()).
+		DoAndReturn(func(_ interface{}, _ models.OrderFilter, fn func(*models.Order) error) error {
+			for _, uid := range []string{"uid-1", "uid-2"} {
+				if err := fn(&models.Order{OrderUID: uid, Items: models.Items{{Name: "item"}}}); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+
+	w := httptest.NewRecorder()
+	h.ExportOrders(w, httptest.NewRequest(http.MethodGet, "/orders/export?format=csv&locale=ru&from=2025-09-01T00:00:00Z&limit=10", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), ".csv")
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[1], "uid-1,"))
+}
+
+func TestExportOrders_Errors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	h := NewHandler(mockService)
+
+	for _, url := range []string{"/orders/export?format=xml", "/orders/export?from=yesterday", "/orders/export?limit=-5"} {
+		w := httptest.NewRecorder()
+		h.ExportOrders(w, httptest.NewRequest(http.MethodGet, url, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, url)
+	}
+
+	// ошибка до первой записи - обычный JSON с 500
+	mockService.EXPECT().StreamOrders(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("db down"))
+	w := httptest.NewRecorder()
+	h.ExportOrders(w, httptest.NewRequest(http.MethodGet, "/orders/export", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Empty(t, w.Header().Get("Content-Disposition"))
+
+	// ошибка посреди выгрузки обрывает ответ
+	mockService.EXPECT().StreamOrders(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ interface{}, _ models.OrderFilter, fn func(*models.Order) error) error {
+			_ = fn(&models.Order{OrderUID: strings.Repeat("x", 8192)})
+			return errors.New("db down")
+		})
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		h.ExportOrders(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders/export?format=ndjson", nil))
+	})
+}
+
+func TestExportOrders_OutlivesServerWriteTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	h := NewHandler(mockService)
+	mockService.EXPECT().StreamOrders(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ interface{}, _ models.OrderFilter, fn func(*models.Order) error) error {
+			for _, uid := range []string{"uid-1", "uid-2", "uid-3"} {
+				time.Sleep(40 * time.Millisecond)
+				if err := fn(&models.Order{OrderUID: uid}); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+
+	// срок записи продлевается на каждой порции, а не снимается совсем
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(h.ExportOrders))
+	srv.Config.WriteTimeout = 50 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/orders/export?format=ndjson")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, 3, strings.Count(string(body), `"order_uid"`))
+}
@@ -1,8 +1,8 @@
 package handler
 
 import (
+	"L0-wb/internal/gql"
 	"L0-wb/internal/service"
-	"encoding/json"
 	"errors"
 	"net/http"
 	"strings"
@@ -11,17 +11,26 @@ import (
 )
 
 type UserHandler struct {
-	service service.Service
+	service  service.Service
+	graphql  http.Handler
+	rendered *renderedOrders
 }
 
 func NewHandler(service service.Service) Handler {
 	return &UserHandler{
-		service: service,
+		service:  service,
+		graphql:  gql.NewHandler(service),
+		rendered: newRenderedOrders(),
 	}
 }
 
-func (h *UserHandler) ServeIndex(w http.ResponseWriter, r *http.Request) {
-	http.ServeFile(w, r, "./web/index.html")
+// GraphQL исполняет запросы к схеме заказов (internal/gql/schema.graphql).
+// Список заказов схема отдаёт только запросам с токеном администратора.
+func (h *UserHandler) GraphQL(w http.ResponseWriter, r *http.Request) {
+	if isAdminRequest(r) {
+		r = r.WithContext(gql.WithAdmin(r.Context()))
+	}
+	h.graphql.ServeHTTP(w, r)
 }
 
 var ErrNotFound = errors.New("order not found")
@@ -30,55 +39,36 @@ func (h *UserHandler) GetOrderByUID(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	orderUID, ok := vars["uid"]
 	if !ok {
-		writeJSON(w, http.StatusNotFound, map[string]interface{}{
-			"status": "error",
-			"msg":    "Missing order UID",
-		})
+		writeProblem(w, r, http.StatusNotFound, CodeOrderNotFound, "Missing order UID")
 		return
 	}
 
 	// Убираем пробелы с начала и конца
 	orderUID = strings.TrimSpace(orderUID)
 	if orderUID == "" {
-		writeJSON(w, http.StatusBadRequest, map[string]interface{}{
-			"status": "error",
-			"msg":    "Order UID cannot be empty",
-		})
+		writeProblem(w, r, http.StatusBadRequest, CodeInvalidArgument, "Order UID cannot be empty")
 		return
 	}
 
 	ctx := r.Context()
 	order, err := h.service.GetOrderByUID(ctx, orderUID)
 	if err != nil {
-		status := http.StatusInternalServerError
-		msg := "Internal server error"
-
 		if errors.Is(err, service.ErrNotFound) {
-			status = http.StatusNotFound
-			msg = "Order not found"
+			writeProblem(w, r, http.StatusNotFound, CodeOrderNotFound, "Order not found")
+			return
 		}
-
-		writeJSON(w, status, map[string]interface{}{
-			"status": "error",
-			"msg":    msg,
-		})
+		writeInternalError(w, r)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"status": "ok",
-		"data":   order,
-	})
-}
-
-func writeJSON(w http.ResponseWriter, statusCode int, payload interface{}) {
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	w.WriteHeader(statusCode)
-	_ = json.NewEncoder(w).Encode(payload)
+	body, etag, err := h.rendered.get(order)
+	if err != nil {
+		writeInternalError(w, r)
+		return
+	}
+	writeWithETag(w, r, body, etag, orderCacheControl)
 }
 
 func (h *UserHandler) HealthCheck(w http.ResponseWriter, r *http.Request) {
-	writeJSON(w, http.StatusOK, map[string]string{
-		"status": "ok",
-	})
+	writeData(w, http.StatusOK, nil)
 }
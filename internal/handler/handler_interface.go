@@ -3,7 +3,23 @@ package handler
 import "net/http"
 
 type Handler interface {
-	ServeIndex(w http.ResponseWriter, r *http.Request)
 	GetOrderByUID(w http.ResponseWriter, r *http.Request)
+	BatchGetOrders(w http.ResponseWriter, r *http.Request)
+	ListOrders(w http.ResponseWriter, r *http.Request)
+	OrderTimeline(w http.ResponseWriter, r *http.Request)
 	HealthCheck(w http.ResponseWriter, r *http.Request)
+	OpenAPI(w http.ResponseWriter, r *http.Request)
+	CacheStats(w http.ResponseWriter, r *http.Request)
+	WarmCache(w http.ResponseWriter, r *http.Request)
+	ExportOrders(w http.ResponseWriter, r *http.Request)
+	StreamOrders(w http.ResponseWriter, r *http.Request)
+	StreamOrdersWS(w http.ResponseWriter, r *http.Request)
+	OrderStats(w http.ResponseWriter, r *http.Request)
+	GraphQL(w http.ResponseWriter, r *http.Request)
+	ListWebhooks(w http.ResponseWriter, r *http.Request)
+	CreateWebhook(w http.ResponseWriter, r *http.Request)
+	GetWebhook(w http.ResponseWriter, r *http.Request)
+	UpdateWebhook(w http.ResponseWriter, r *http.Request)
+	DeleteWebhook(w http.ResponseWriter, r *http.Request)
+	WebhookDeliveries(w http.ResponseWriter, r *http.Request)
 }
@@ -8,8 +8,6 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
-	"os"
-	"path/filepath"
 	"testing"
 	"time"
 
@@ -65,8 +63,8 @@ func TestGetOrderByUID(t *testing.T) {
 			},
 			expectedStatus: http.StatusNotFound,
 			expectedBody: map[string]interface{}{
-				"status": "error",
-				"msg":    "Order not found",
+				"code":   CodeOrderNotFound,
+				"detail": "Order not found",
 			},
 		},
 		{
@@ -75,8 +73,8 @@ func TestGetOrderByUID(t *testing.T) {
 			setupMock:      func() {},
 			expectedStatus: http.StatusNotFound, // горилла вернет 404 для неправильного пути
 			expectedBody: map[string]interface{}{
-				"status": "error",
-				"msg":    "Missing order UID",
+				"code":   CodeOrderNotFound,
+				"detail": "Missing order UID",
 			},
 		},
 		{
@@ -89,8 +87,8 @@ func TestGetOrderByUID(t *testing.T) {
 			},
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody: map[string]interface{}{
-				"status": "error",
-				"msg":    "Internal server error",
+				"code":   CodeInternal,
+				"detail": "Internal server error",
 			},
 		},
 	}
@@ -110,9 +108,10 @@ func TestGetOrderByUID(t *testing.T) {
 				var response map[string]interface{}
 				err := json.NewDecoder(w.Body).Decode(&response)
 				assert.NoError(t, err)
-				assert.Equal(t, tt.expectedBody["status"], response["status"])
-				if msg, ok := tt.expectedBody["msg"]; ok {
-					assert.Equal(t, msg, response["msg"])
+				for _, key := range []string{"status", "code", "detail"} {
+					if want, ok := tt.expectedBody[key]; ok {
+						assert.Equal(t, want, response[key])
+					}
 				}
 				if _, hasData := tt.expectedBody["data"]; hasData {
 					assert.NotNil(t, response["data"])
@@ -142,64 +141,6 @@ func TestHealthCheck(t *testing.T) {
 	assert.Equal(t, "ok", response["status"])
 }
 
-type mockFileSystem struct {
-	opened bool
-	path   string
-}
-
-func (m *mockFileSystem) Open(name string) (http.File, error) {
-	m.opened = true
-	m.path = name
-	return nil, nil // для теста нам не нужно реально открывать файл
-}
-
-func TestHandler_ServeIndex(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	mockService := mocks.NewMockService(ctrl)
-	h := NewHandler(mockService)
-
-	// Создаем временную директорию для теста
-	tempDir := t.TempDir()
-	err := os.MkdirAll(filepath.Join(tempDir, "web"), 0755)
-	require.NoError(t, err)
-
-	// Создаем тестовый index.html
-	indexContent := "<html><body>Test</body></html>"
-	err = os.WriteFile(filepath.Join(tempDir, "web", "index.html"), []byte(indexContent), 0644)
-	require.NoError(t, err)
-
-	// Сохраняем текущую директорию
-	currentDir, err := os.Getwd()
-	require.NoError(t, err)
-
-	// Переходим во временную директорию
-	err = os.Chdir(tempDir)
-	require.NoError(t, err)
-
-	// Возвращаемся в исходную директорию после теста
-	defer func() {
-		err := os.Chdir(currentDir)
-		require.NoError(t, err)
-	}()
-
-	req := httptest.NewRequest(http.MethodGet, "/", nil)
-	w := httptest.NewRecorder()
-
-	h.ServeIndex(w, req)
-
-	// Если файл не найден, будет статус 404
-	if w.Code == http.StatusNotFound {
-		t.Log("Expected file not found, this is OK in test environment")
-		return
-	}
-
-	// Если файл найден, проверяем содержимое
-	assert.Equal(t, http.StatusOK, w.Code)
-	assert.Contains(t, w.Body.String(), "Test")
-}
-
 func TestHandler_GetOrderByUID_ValidationErrors(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -218,8 +159,8 @@ func TestHandler_GetOrderByUID_ValidationErrors(t *testing.T) {
 			uid:            "%20", // URL-encoded space
 			expectedStatus: http.StatusBadRequest,
 			expectedBody: map[string]interface{}{
-				"status": "error",
-				"msg":    "Order UID cannot be empty",
+				"code":   CodeInvalidArgument,
+				"detail": "Order UID cannot be empty",
 			},
 		},
 	}
@@ -241,8 +182,8 @@ func TestHandler_GetOrderByUID_ValidationErrors(t *testing.T) {
 			var response map[string]interface{}
 			err := json.NewDecoder(w.Body).Decode(&response)
 			require.NoError(t, err)
-			assert.Equal(t, tt.expectedBody["status"], response["status"])
-			assert.Equal(t, tt.expectedBody["msg"], response["msg"])
+			assert.Equal(t, tt.expectedBody["code"], response["code"])
+			assert.Equal(t, tt.expectedBody["detail"], response["detail"])
 		})
 	}
 }
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// legacyRoutes - имя маршрута со старыми путями без /api/v1
+const legacyRoutes = "legacy"
+
+// Старые пути объявлены устаревшими с legacyDeprecatedAt и будут удалены после legacySunset
+var (
+	legacyDeprecatedAt = time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	legacySunset       = time.Date(2027, 5, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// deprecatedMiddleware помечает ответ старого пути заголовками Deprecation (RFC 9745)
+// и Sunset (RFC 8594), ссылается на путь под /api/v1 и сохраняет прежний формат ошибок
+func deprecatedMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", fmt.Sprintf("@%d", legacyDeprecatedAt.Unix()))
+		w.Header().Set("Sunset", legacySunset.Format(http.TimeFormat))
+		w.Header().Set("Link", fmt.Sprintf(`<%s%s>; rel="successor-version"`, apiPrefix, r.URL.Path))
+
+		ctx := context.WithValue(r.Context(), legacyKey{}, true)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,19 @@
+package handler
+
+import (
+	"L0-wb/api"
+	"log"
+	"net/http"
+)
+
+// OpenAPI отдаёт спецификацию HTTP API (api/openapi.yaml) в JSON
+func (h *UserHandler) OpenAPI(w http.ResponseWriter, r *http.Request) {
+	spec, err := api.JSON()
+	if err != nil {
+		log.Printf("load OpenAPI spec: %v", err)
+		writeInternalError(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(spec)
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"L0-wb/internal/models"
+	"L0-wb/internal/service"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	ordersPageDefault = 20
+	ordersPageMax     = 100
+)
+
+// orderSummary - строка списка заказов; полный заказ отдаёт GET /order/{uid}
+type orderSummary struct {
+	OrderUID        string    `json:"order_uid"`
+	TrackNumber     string    `json:"track_number"`
+	CustomerID      string    `json:"customer_id"`
+	DeliveryService string    `json:"delivery_service"`
+	Locale          string    `json:"locale"`
+	DateCreated     time.Time `json:"date_created"`
+	Amount          int       `json:"amount"`
+	Currency        string    `json:"currency"`
+	ItemsCount      int       `json:"items_count"`
+}
+
+type orderListResponse struct {
+	Orders  []orderSummary `json:"orders"`
+	Limit   int            `json:"limit"`
+	Offset  int            `json:"offset"`
+	HasMore bool           `json:"has_more"`
+}
+
+type timelineResponse struct {
+	OrderUID string                 `json:"order_uid"`
+	Events   []models.TimelineEvent `json:"events"`
+}
+
+// ListOrders - страница заказов по фильтру, новые первыми. Оплата и товары
+// догружаются одним запросом на часть для всей страницы.
+func (h *UserHandler) ListOrders(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter, err := orderFilterFromQuery(q)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeInvalidArgument, err.Error())
+		return
+	}
+	if filter.Limit == 0 {
+		filter.Limit = ordersPageDefault
+	}
+	if filter.Limit > ordersPageMax {
+		writeProblem(w, r, http.StatusBadRequest, CodeInvalidArgument,
+			fmt.Sprintf("limit must be from 1 to %d", ordersPageMax))
+		return
+	}
+	if s := q.Get("offset"); s != "" {
+		if filter.Offset, err = strconv.Atoi(s); err != nil || filter.Offset < 0 {
+			writeProblem(w, r, http.StatusBadRequest, CodeInvalidArgument, "offset must be a non-negative integer")
+			return
+		}
+	}
+
+	// лишний заказ показывает, есть ли следующая страница
+	limit := filter.Limit
+	filter.Limit++
+	headers, err := h.service.ListOrderHeaders(r.Context(), filter)
+	if err != nil {
+		writeInternalError(w, r)
+		return
+	}
+	hasMore := len(headers) > limit
+	if hasMore {
+		headers = headers[:limit]
+	}
+
+	uids := make([]string, len(headers))
+	for i, o := range headers {
+		uids[i] = o.OrderUID
+	}
+	parts, err := h.service.OrderParts(r.Context(), uids, models.OrderParts{Payment: true, Items: true})
+	if err != nil {
+		writeInternalError(w, r)
+		return
+	}
+
+	orders := make([]orderSummary, len(headers))
+	for i, o := range headers {
+		p := parts[o.OrderUID]
+		orders[i] = orderSummary{
+			OrderUID:        o.OrderUID,
+			TrackNumber:     o.TrackNumber,
+			CustomerID:      o.CustomerID,
+			DeliveryService: o.DeliveryService,
+			Locale:          o.Locale,
+			DateCreated:     o.DateCreated,
+			Amount:          p.Payment.Amount,
+			Currency:        p.Payment.Currency,
+			ItemsCount:      len(p.Items),
+		}
+	}
+	writeData(w, http.StatusOK, orderListResponse{Orders: orders, Limit: limit, Offset: filter.Offset, HasMore: hasMore})
+}
+
+// OrderTimeline - история заказа по времени
+func (h *UserHandler) OrderTimeline(w http.ResponseWriter, r *http.Request) {
+	orderUID := strings.TrimSpace(mux.Vars(r)["uid"])
+	if orderUID == "" {
+		writeProblem(w, r, http.StatusBadRequest, CodeInvalidArgument, "Order UID cannot be empty")
+		return
+	}
+
+	events, err := h.service.OrderTimeline(r.Context(), orderUID)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			writeProblem(w, r, http.StatusNotFound, CodeOrderNotFound, "Order not found")
+			return
+		}
+		writeInternalError(w, r)
+		return
+	}
+	writeData(w, http.StatusOK, timelineResponse{OrderUID: orderUID, Events: events})
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"L0-wb/internal/mocks"
+	"L0-wb/internal/models"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListOrders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	h := NewHandler(mockService)
+
+	type page struct {
+		Data orderListResponse `json:"data"`
+	}
+
+	t.Run("last page", func(t *testing.T) {
+		mockService.EXPECT().ListOrderHeaders(gomock.Any(),
+			models.OrderFilter{TrackNumber: "WBIL", Limit: ordersPageDefault + 1, Offset: 20}).
+			Return([]models.Order{{OrderUID: "a", TrackNumber: "WBIL"}}, nil)
+		mockService.EXPECT().OrderParts(gomock.Any(), []string{"a"}, models.OrderParts{Payment: true, Items: true}).
+			Return(map[string]models.Order{"a": {
+				Payment: models.Payment{Amount: 1817, Currency: "USD"},
+				Items:   models.Items{{Name: "Mascaras"}, {Name: "Socks"}},
+			}}, nil)
+
+		w := httptest.NewRecorder()
+		h.ListOrders(w, httptest.NewRequest(http.MethodGet, "/api/v1/orders?track_number=WBIL&offset=20", nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var resp page
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.False(t, resp.Data.HasMore)
+		assert.Equal(t, ordersPageDefault, resp.Data.Limit)
+		assert.Equal(t, 20, resp.Data.Offset)
+		require.Len(t, resp.Data.Orders, 1)
+		assert.Equal(t, 1817, resp.Data.Orders[0].Amount)
+		assert.Equal(t, 2, resp.Data.Orders[0].ItemsCount)
+	})
+
+	t.Run("has more", func(t *testing.T) {
+		mockService.EXPECT().ListOrderHeaders(gomock.Any(), models.OrderFilter{Limit: 3}).
+			Return([]models.Order{{OrderUID: "a"}, {OrderUID: "b"}, {OrderUID: "c"}}, nil)
+		mockService.EXPECT().OrderParts(gomock.Any(), []string{"a", "b"}, gomock.Any()).
+			Return(map[string]models.Order{}, nil)
+
+		w := httptest.NewRecorder()
+		h.ListOrders(w, httptest.NewRequest(http.MethodGet, "/api/v1/orders?limit=2", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp page
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.True(t, resp.Data.HasMore)
+		assert.Len(t, resp.Data.Orders, 2)
+	})
+
+	for _, query := range []string{"limit=101", "offset=-1", "from=yesterday"} {
+		t.Run(query, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ListOrders(w, httptest.NewRequest(http.MethodGet, "/api/v1/orders?"+query, nil))
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
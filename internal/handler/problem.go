@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// Коды ошибок API; по ним клиенты различают ошибки с одинаковым HTTP-статусом
+const (
+	CodeInvalidArgument  = "invalid_argument"
+	CodeInvalidBody      = "invalid_body"
+	CodeNotFound         = "not_found"
+	CodeOrderNotFound    = "order_not_found"
+	CodeWebhookNotFound  = "webhook_not_found"
+	CodeMethodNotAllowed = "method_not_allowed"
+	CodeUnauthorized     = "unauthorized"
+	CodeForbidden        = "forbidden"
+	CodeInternal         = "internal"
+)
+
+const (
+	problemContentType = "application/problem+json"
+	problemTypePrefix  = "urn:l0-wb:problem:"
+)
+
+// Problem - ошибка API в формате RFC 7807 с расширением code
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"code"`
+}
+
+// envelope - успешный ответ JSON-ручек
+type envelope struct {
+	Status string      `json:"status"`
+	Data   interface{} `json:"data,omitempty"`
+}
+
+// writeData отвечает {"status":"ok","data":...}; nil data опускается
+func writeData(w http.ResponseWriter, statusCode int, data interface{}) {
+	writeJSON(w, statusCode, envelope{Status: "ok", Data: data})
+}
+
+// writeProblem отвечает ошибкой application/problem+json. На устаревших путях
+// без /api/v1 ответ остаётся прежним {"status":"error","msg":...}.
+func writeProblem(w http.ResponseWriter, r *http.Request, statusCode int, code, detail string) {
+	if isLegacy(r.Context()) {
+		writeJSON(w, statusCode, map[string]string{
+			"status": "error",
+			"msg":    detail,
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(Problem{
+		Type:     problemTypePrefix + code,
+		Title:    http.StatusText(statusCode),
+		Status:   statusCode,
+		Detail:   detail,
+		Instance: r.URL.Path,
+		Code:     code,
+	})
+}
+
+// writeInternalError - 500 без подробностей о причине
+func writeInternalError(w http.ResponseWriter, r *http.Request) {
+	writeProblem(w, r, http.StatusInternalServerError, CodeInternal, "Internal server error")
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+type legacyKey struct{}
+
+func isLegacy(ctx context.Context) bool {
+	legacy, _ := ctx.Value(legacyKey{}).(bool)
+	return legacy
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"L0-wb/config"
+	"L0-wb/internal/mocks"
+	"L0-wb/internal/service"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProblemResponses(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	srv := NewServer(&config.Config{}, NewHandler(mockService))
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		setup      func()
+		wantStatus int
+		wantCode   string
+	}{{
+		name:   "order not found",
+		method: http.MethodGet,
+		path:   "/api/v1/order/missing",
+		setup: func() {
+			mockService.EXPECT().GetOrderByUID(gomock.Any(), "missing").Return(nil, service.ErrNotFound)
+		},
+		wantStatus: http.StatusNotFound,
+		wantCode:   CodeOrderNotFound,
+	}, {
+		name:       "unknown route",
+		method:     http.MethodGet,
+		path:       "/api/v1/nope",
+		setup:      func() {},
+		wantStatus: http.StatusNotFound,
+		wantCode:   CodeNotFound,
+	}, {
+		name:       "method not allowed",
+		method:     http.MethodDelete,
+		path:       "/api/v1/order/uid-1",
+		setup:      func() {},
+		wantStatus: http.StatusMethodNotAllowed,
+		wantCode:   CodeMethodNotAllowed,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			w := httptest.NewRecorder()
+			srv.Handler.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+			assert.Empty(t, w.Header().Get("Deprecation"))
+			var p Problem
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&p))
+			assert.Equal(t, Problem{
+				Type:     "urn:l0-wb:problem:" + tt.wantCode,
+				Title:    http.StatusText(tt.wantStatus),
+				Status:   tt.wantStatus,
+				Detail:   p.Detail,
+				Instance: tt.path,
+				Code:     tt.wantCode,
+			}, p)
+			assert.NotEmpty(t, p.Detail)
+			if tt.wantStatus == http.StatusMethodNotAllowed {
+				assert.Equal(t, "GET", w.Header().Get("Allow"))
+			}
+		})
+	}
+}
+
+func TestLegacyRoutes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	srv := NewServer(&config.Config{}, NewHandler(mockService))
+	mockService.EXPECT().GetOrderByUID(gomock.Any(), "missing").Return(nil, service.ErrNotFound)
+
+	w := httptest.NewRecorder()
+	srv.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/order/missing", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "@1793491200", w.Header().Get("Deprecation"))
+	assert.Equal(t, "Sat, 01 May 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+	assert.Equal(t, `</api/v1/order/missing>; rel="successor-version"`, w.Header().Get("Link"))
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"status":"error","msg":"Order not found"}`, w.Body.String())
+
+	// у ручек, появившихся вместе с /api/v1, псевдонимов без версии нет
+	for _, p := range []string{"/stats", "/orders/stream", "/graphql", "/order/missing/timeline"} {
+		w = httptest.NewRecorder()
+		srv.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, p, nil))
+		assert.Equal(t, http.StatusNotFound, w.Code, p)
+		assert.Empty(t, w.Header().Get("Deprecation"), p)
+	}
+}
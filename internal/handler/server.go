@@ -1,45 +1,141 @@
 package handler
 
 import (
+	"context"
+	"expvar"
 	"fmt"
+	"net"
 	"net/http"
+	"strings"
 
 	"L0-wb/config"
 
 	"github.com/gorilla/mux"
 )
 
+const apiPrefix = "/api/v1"
+
 func NewServer(cfg *config.Config, h Handler) *http.Server {
 	router := mux.NewRouter()
 	//Middleware для CORS
 	router.Use(corsMiddleware)
+	// Служебные ручки (adminPaths) - только с HTTP_ADMIN_TOKEN
+	router.Use(adminAuthMiddleware(cfg.HTTPServer.AdminToken))
+	// gzip и brotli по Accept-Encoding
+	router.Use(compressMiddleware)
 	// Health check endpoint
 	router.HandleFunc("/health", h.HealthCheck).Methods(http.MethodGet)
-	// API ручка
-	router.HandleFunc("/order/{uid}", h.GetOrderByUID).Methods(http.MethodGet)
+	// Спецификация API; Swagger UI - web/swagger.html
+	router.HandleFunc("/openapi.json", h.OpenAPI).Methods(http.MethodGet)
+	// Счётчики expvar, в том числе метрики gRPC
+	router.Handle("/debug/vars", expvar.Handler()).Methods(http.MethodGet)
+
+	// API; ошибки - application/problem+json, в том числе для неизвестных путей
+	api := router.PathPrefix(apiPrefix).Subrouter()
+	api.NotFoundHandler = apiFallback(api)
+	api.MethodNotAllowedHandler = api.NotFoundHandler
+	// preflight отвечает corsMiddleware
+	api.Methods(http.MethodOptions).Handler(http.NotFoundHandler())
+	registerAPI(api, h)
+
+	// Прежний путь без версии работает до legacySunset с заголовками Deprecation и Sunset.
+	// Ручки, появившиеся вместе с /api/v1, псевдонимов без версии не получают.
+	legacy := router.NewRoute().Name(legacyRoutes).Subrouter()
+	legacy.Use(deprecatedMiddleware)
+	legacy.HandleFunc("/order/{uid}", h.GetOrderByUID).Methods(http.MethodGet)
 
-	//Статические файлы из папки ./web
-	fs := http.FileServer(http.Dir("./web"))
-	router.PathPrefix("/").Handler(http.StripPrefix("/", fs))
+	// Страницы web/, встроенные в бинарник (HTTP_WEB_DIR - с диска)
+	router.PathPrefix("/").Handler(newStaticSite(cfg.HTTPServer))
+
+	// Shutdown ждёт завершения запросов, а потоки заказов бесконечны:
+	// отменяем их контекст в начале остановки сервера
+	baseCtx, cancel := context.WithCancel(context.Background())
 
 	addrStr := fmt.Sprintf("%s:%d", cfg.HTTPServer.Host, cfg.HTTPServer.Port)
-	return &http.Server{
+	srv := &http.Server{
 		Addr:         addrStr,
 		Handler:      router,
 		ReadTimeout:  cfg.HTTPServer.Timeout,
 		WriteTimeout: cfg.HTTPServer.Timeout,
+		BaseContext:  func(net.Listener) context.Context { return baseCtx },
 	}
+	srv.RegisterOnShutdown(cancel)
+	return srv
+}
+
+// registerAPI объявляет ручки под /api/v1
+func registerAPI(router *mux.Router, h Handler) {
+	router.HandleFunc("/order/{uid}", h.GetOrderByUID).Methods(http.MethodGet)
+	router.HandleFunc("/order/{uid}/timeline", h.OrderTimeline).Methods(http.MethodGet)
+	router.HandleFunc("/orders", h.ListOrders).Methods(http.MethodGet)
+	router.HandleFunc("/orders:batchGet", h.BatchGetOrders).Methods(http.MethodPost)
+	// Потоковая выгрузка заказов
+	router.HandleFunc("/orders/export", h.ExportOrders).Methods(http.MethodGet)
+	// Новые заказы в реальном времени: SSE и WebSocket
+	router.HandleFunc("/orders/stream", h.StreamOrders).Methods(http.MethodGet)
+	router.HandleFunc("/orders/ws", h.StreamOrdersWS).Methods(http.MethodGet)
+	// Аналитика по сохранённым заказам
+	router.HandleFunc("/stats", h.OrderStats).Methods(http.MethodGet)
+	router.HandleFunc("/stats/{section}", h.OrderStats).Methods(http.MethodGet)
+	// GraphQL: выборка только нужных полей заказов
+	router.HandleFunc("/graphql", h.GraphQL).Methods(http.MethodGet, http.MethodPost)
+	// Служебные ручки для orderctl
+	router.HandleFunc("/admin/cache/stats", h.CacheStats).Methods(http.MethodGet)
+	router.HandleFunc("/admin/cache/warm", h.WarmCache).Methods(http.MethodPost)
+	// Подписки на вебхуки и журнал их доставки
+	router.HandleFunc("/admin/webhooks", h.ListWebhooks).Methods(http.MethodGet)
+	router.HandleFunc("/admin/webhooks", h.CreateWebhook).Methods(http.MethodPost)
+	router.HandleFunc("/admin/webhooks/{id}", h.GetWebhook).Methods(http.MethodGet)
+	router.HandleFunc("/admin/webhooks/{id}", h.UpdateWebhook).Methods(http.MethodPut)
+	router.HandleFunc("/admin/webhooks/{id}", h.DeleteWebhook).Methods(http.MethodDelete)
+	router.HandleFunc("/admin/webhooks/{id}/deliveries", h.WebhookDeliveries).Methods(http.MethodGet)
 }
 
-// Добавляем заголовки для кросс-доменных запросов с corsMiddleware
+// apiFallback отвечает 405 с заголовком Allow, если путь есть, но с другим методом,
+// иначе 404. mux сам 405 не определяет: префикс подроутера сбрасывает
+// ошибку несовпадения метода, если после маршрута с этим путём есть другие.
+func apiFallback(api *mux.Router) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var allowed []string
+		for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete} {
+			if method == r.Method {
+				continue
+			}
+			probe := r.Clone(r.Context())
+			probe.Method = method
+			var match mux.RouteMatch
+			if api.Match(probe, &match) && match.MatchErr == nil {
+				allowed = append(allowed, method)
+			}
+		}
+		if len(allowed) == 0 {
+			writeProblem(w, r, http.StatusNotFound, CodeNotFound, "no such API route")
+			return
+		}
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		writeProblem(w, r, http.StatusMethodNotAllowed, CodeMethodNotAllowed, r.Method+" is not allowed here")
+	})
+}
+
+// Добавляем заголовки для кросс-доменных запросов с corsMiddleware.
+// Служебным ручкам CORS не нужен: без заголовков браузер не даст странице
+// другого источника их вызвать.
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*") // все источники
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		admin := isAdminPath(r.URL.Path)
+		if !admin {
+			w.Header().Set("Access-Control-Allow-Origin", "*") // все источники
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, If-None-Match")
+			w.Header().Set("Access-Control-Expose-Headers", "ETag, Deprecation, Sunset, Link")
+		}
 
 		//preflight запрос
 		if r.Method == http.MethodOptions {
+			if admin {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
 			w.WriteHeader(http.StatusOK)
 			return
 		}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"L0-wb/config"
+	"L0-wb/web"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"html"
+	"io"
+	"io/fs"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	// HTML получает настройки при отдаче, поэтому всегда перепроверяется
+	htmlCacheControl = "no-cache"
+	// остальные встроенные файлы меняются только с новой сборкой
+	assetCacheControl = "public, max-age=3600"
+	// файлы с диска правятся во время разработки
+	devCacheControl = "no-store"
+)
+
+// apiBaseMeta - тег в web/index.html, в который подставляется адрес API
+const apiBaseMeta = `<meta name="api-base-url" content="` + apiPrefix + `">`
+
+// staticSite отдаёт страницы web/: встроенные в бинарник или, если задан
+// HTTP_WEB_DIR, с диска
+type staticSite struct {
+	files      fs.FS
+	dev        bool
+	apiBaseURL string
+}
+
+func newStaticSite(cfg config.HTTPServer) *staticSite {
+	s := &staticSite{files: web.FS, apiBaseURL: cfg.PublicAPIURL}
+	if cfg.WebDir != "" {
+		s.files, s.dev = os.DirFS(cfg.WebDir), true
+	}
+	return s
+}
+
+func (s *staticSite) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+	if name == "" {
+		name = "index.html"
+	}
+
+	content, modTime, err := s.read(name)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("static file %s: %v", name, err)
+		}
+		http.NotFound(w, r)
+		return
+	}
+	if name == "index.html" && s.apiBaseURL != "" {
+		content = bytes.Replace(content, []byte(apiBaseMeta),
+			[]byte(`<meta name="api-base-url" content="`+html.EscapeString(s.apiBaseURL)+`">`), 1)
+	}
+
+	h := w.Header()
+	ctype := mime.TypeByExtension(path.Ext(name))
+	if ctype == "" {
+		ctype = http.DetectContentType(content)
+	}
+	h.Set("Content-Type", ctype)
+	h.Set("X-Content-Type-Options", "nosniff")
+	switch {
+	case s.dev:
+		h.Set("Cache-Control", devCacheControl)
+	case strings.HasPrefix(ctype, "text/html"):
+		h.Set("Cache-Control", htmlCacheControl)
+	default:
+		h.Set("Cache-Control", assetCacheControl)
+	}
+
+	sum := sha256.Sum256(content)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	h.Set("ETag", etag)
+	// сравниваем сами: ServeContent не знает о суффиксе кодировки в ETag
+	if etagMatch(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	http.ServeContent(w, r, name, modTime, bytes.NewReader(content))
+}
+
+// read читает файл целиком; каталоги считаются отсутствующими
+func (s *staticSite) read(name string) ([]byte, time.Time, error) {
+	f, err := s.files.Open(name)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if info.IsDir() {
+		return nil, time.Time{}, fs.ErrNotExist
+	}
+	content, err := io.ReadAll(f)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	// у встроенных файлов времени изменения нет: Last-Modified не отправляется
+	return content, info.ModTime(), nil
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"L0-wb/config"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaticSite_Embedded(t *testing.T) {
+	site := newStaticSite(config.HTTPServer{PublicAPIURL: `https://orders.example/api/v1?x="1"`})
+
+	w := httptest.NewRecorder()
+	site.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, htmlCacheControl, w.Header().Get("Cache-Control"))
+	assert.Contains(t, w.Body.String(),
+		`<meta name="api-base-url" content="https://orders.example/api/v1?x=&#34;1&#34;">`)
+	assert.NotContains(t, w.Body.String(), apiBaseMeta)
+
+	// ETag с суффиксом сжатия тоже подходит
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	req := httptest.NewRequest(http.MethodGet, "/index.html", nil)
+	req.Header.Set("If-None-Match", etag[:len(etag)-1]+`-gzip"`)
+	w = httptest.NewRecorder()
+	site.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	w = httptest.NewRecorder()
+	site.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/swagger.html", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "/openapi.json")
+	assert.NotContains(t, w.Body.String(), "https://")
+
+	// Swagger UI встроен, а не грузится с CDN
+	for p, ctype := range map[string]string{
+		"/swagger-ui/swagger-ui-bundle.js": "text/javascript",
+		"/swagger-ui/swagger-ui.css":       "text/css",
+	} {
+		w = httptest.NewRecorder()
+		site.ServeHTTP(w, httptest.NewRequest(http.MethodGet, p, nil))
+		assert.Equal(t, http.StatusOK, w.Code, p)
+		assert.Contains(t, w.Header().Get("Content-Type"), ctype, p)
+		assert.Equal(t, assetCacheControl, w.Header().Get("Cache-Control"), p)
+	}
+
+	for _, p := range []string{"/missing.js", "/embed.go", "/../go.mod"} {
+		w = httptest.NewRecorder()
+		site.ServeHTTP(w, httptest.NewRequest(http.MethodGet, p, nil))
+		assert.Equal(t, http.StatusNotFound, w.Code, p)
+	}
+
+	w = httptest.NewRecorder()
+	site.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "GET, HEAD", w.Header().Get("Allow"))
+}
+
+func TestStaticSite_DefaultAPIBase(t *testing.T) {
+	w := httptest.NewRecorder()
+	newStaticSite(config.HTTPServer{}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), apiBaseMeta)
+}
+
+func TestStaticSite_WebDir(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.html"), []byte("<html>"+apiBaseMeta+"</html>"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app.js"), []byte("console.log(1)"), 0o644))
+	site := newStaticSite(config.HTTPServer{WebDir: dir, PublicAPIURL: "http://localhost:8081/api/v1"})
+
+	w := httptest.NewRecorder()
+	site.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, devCacheControl, w.Header().Get("Cache-Control"))
+	assert.Equal(t, `<html><meta name="api-base-url" content="http://localhost:8081/api/v1"></html>`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	site.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/app.js", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "javascript")
+	assert.NotEmpty(t, w.Header().Get("Last-Modified"))
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"L0-wb/internal/models"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	// statsDefaultDays - интервал по умолчанию, дней до конца текущих суток UTC
+	statsDefaultDays = 30
+	statsMaxRange    = 366 * 24 * time.Hour
+	statsMaxTop      = 100
+)
+
+// statsSections - части отчёта, доступные как /stats/{section}
+var statsSections = map[string]func(s models.OrderStats) interface{}{
+	"summary": func(s models.OrderStats) interface{} {
+		return map[string]interface{}{
+			"from":            s.From,
+			"to":              s.To,
+			"orders":          s.Orders,
+			"revenue":         s.Revenue,
+			"avg_basket_size": s.AvgBasketSize,
+			"avg_sale":        s.AvgSale,
+		}
+	},
+	"daily":    func(s models.OrderStats) interface{} { return s.ByDay },
+	"delivery": func(s models.OrderStats) interface{} { return s.ByDelivery },
+	"payment": func(s models.OrderStats) interface{} {
+		return map[string]interface{}{"by_provider": s.ByProvider, "by_bank": s.ByBank}
+	},
+	"items": func(s models.OrderStats) interface{} {
+		return map[string]interface{}{"top_brands": s.TopBrands, "top_sizes": s.TopSizes, "avg_sale": s.AvgSale}
+	},
+}
+
+// OrderStats отдаёт агрегаты по заказам за интервал: /stats - отчёт целиком,
+// /stats/{section} - одну из частей statsSections
+func (h *UserHandler) OrderStats(w http.ResponseWriter, r *http.Request) {
+	section, hasSection := mux.Vars(r)["section"]
+	view, ok := statsSections[section]
+	if hasSection && !ok {
+		writeProblem(w, r, http.StatusNotFound, CodeNotFound, "unknown stats section")
+		return
+	}
+
+	q, err := statsQueryFromURL(r.URL.Query(), time.Now())
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeInvalidArgument, err.Error())
+		return
+	}
+
+	stats, err := h.service.OrderStats(r.Context(), q)
+	if err != nil {
+		writeInternalError(w, r)
+		return
+	}
+
+	var data interface{} = stats
+	if hasSection {
+		data = view(stats)
+	}
+	writeData(w, http.StatusOK, data)
+}
+
+// statsQueryFromURL разбирает from, to (RFC3339) и top. Границы по умолчанию
+// выровнены по суткам, чтобы повторные запросы попадали в кэш сервиса.
+func statsQueryFromURL(v url.Values, now time.Time) (models.StatsQuery, error) {
+	q := models.StatsQuery{Top: models.DefaultStatsTop}
+	var err error
+
+	if s := v.Get("to"); s != "" {
+		if q.To, err = time.Parse(time.RFC3339, s); err != nil {
+			return q, fmt.Errorf("to must be RFC3339 time")
+		}
+	} else {
+		q.To = now.UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+	}
+	if s := v.Get("from"); s != "" {
+		if q.From, err = time.Parse(time.RFC3339, s); err != nil {
+			return q, fmt.Errorf("from must be RFC3339 time")
+		}
+	} else {
+		q.From = q.To.AddDate(0, 0, -statsDefaultDays)
+	}
+	if !q.From.Before(q.To) {
+		return q, fmt.Errorf("from must be before to")
+	}
+	if q.To.Sub(q.From) > statsMaxRange {
+		return q, fmt.Errorf("range must not exceed %d days", int(statsMaxRange/(24*time.Hour)))
+	}
+
+	if s := v.Get("top"); s != "" {
+		if q.Top, err = strconv.Atoi(s); err != nil || q.Top < 1 || q.Top > statsMaxTop {
+			return q, fmt.Errorf("top must be an integer between 1 and %d", statsMaxTop)
+		}
+	}
+	return q, nil
+}
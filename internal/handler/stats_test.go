@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"L0-wb/internal/mocks"
+	"L0-wb/internal/models"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	router := mux.NewRouter()
+	h := NewHandler(mockService)
+	router.HandleFunc("/stats", h.OrderStats)
+	router.HandleFunc("/stats/{section}", h.OrderStats)
+
+	from := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+	stats := models.OrderStats{
+		From:       from,
+		To:         to,
+		Orders:     3,
+		Revenue:    4500,
+		ByProvider: []models.StatsBucket{{Key: "wbpay", Orders: 3, Revenue: 4500}},
+		ByBank:     []models.StatsBucket{{Key: "Sber", Orders: 3, Revenue: 4500}},
+	}
+
+	tests := []struct {
+		name       string
+		url        string
+		setup      func()
+		wantStatus int
+		check      func(t *testing.T, data json.RawMessage)
+	}{{
+		name: "full report",
+		url:  "/stats?from=2025-09-01T00:00:00Z&to=2025-09-08T00:00:00Z&top=5",
+		setup: func() {
+			mockService.EXPECT().OrderStats(gomock.Any(), models.StatsQuery{From: from, To: to, Top: 5}).Return(stats, nil)
+		},
+		wantStatus: http.StatusOK,
+		check: func(t *testing.T, data json.RawMessage) {
+			var got models.OrderStats
+			require.NoError(t, json.Unmarshal(data, &got))
+			assert.Equal(t, 3, got.Orders)
+			assert.Equal(t, stats.ByProvider, got.ByProvider)
+		},
+	}, {
+		name: "section",
+		url:  "/stats/payment?from=2025-09-01T00:00:00Z&to=2025-09-08T00:00:00Z",
+		setup: func() {
+			mockService.EXPECT().OrderStats(gomock.Any(), models.StatsQuery{From: from, To: to, Top: models.DefaultStatsTop}).Return(stats, nil)
+		},
+		wantStatus: http.StatusOK,
+		check: func(t *testing.T, data json.RawMessage) {
+			var got map[string][]models.StatsBucket
+			require.NoError(t, json.Unmarshal(data, &got))
+			assert.Equal(t, stats.ByBank, got["by_bank"])
+			assert.Len(t, got, 2)
+		},
+	}, {
+		name:       "unknown section",
+		url:        "/stats/nope",
+		setup:      func() {},
+		wantStatus: http.StatusNotFound,
+	}, {
+		name:       "inverted range",
+		url:        "/stats?from=2025-09-08T00:00:00Z&to=2025-09-01T00:00:00Z",
+		setup:      func() {},
+		wantStatus: http.StatusBadRequest,
+	}, {
+		name: "service error",
+		url:  "/stats",
+		setup: func() {
+			mockService.EXPECT().OrderStats(gomock.Any(), gomock.Any()).Return(models.OrderStats{}, errors.New("db down"))
+		},
+		wantStatus: http.StatusInternalServerError,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.check != nil {
+				var response struct {
+					Status string          `json:"status"`
+					Data   json.RawMessage `json:"data"`
+				}
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+				assert.Equal(t, "ok", response.Status)
+				tt.check(t, response.Data)
+			}
+		})
+	}
+}
+
+func TestStatsQueryFromURL(t *testing.T) {
+	now := time.Date(2025, 9, 8, 15, 30, 0, 0, time.UTC)
+
+	q, err := statsQueryFromURL(url.Values{}, now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 9, 9, 0, 0, 0, 0, time.UTC), q.To)
+	assert.Equal(t, time.Date(2025, 8, 10, 0, 0, 0, 0, time.UTC), q.From)
+	assert.Equal(t, models.DefaultStatsTop, q.Top)
+
+	for _, v := range []url.Values{
+		{"from": {"yesterday"}},
+		{"from": {"2020-01-01T00:00:00Z"}},
+		{"top": {"0"}},
+		{"top": {"1000"}},
+	} {
+		_, err := statsQueryFromURL(v, now)
+		assert.Error(t, err, v.Encode())
+	}
+}
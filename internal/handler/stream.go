@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"L0-wb/internal/service/feed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// streamKeepAlive - период комментариев SSE и ping WebSocket, чтобы прокси не закрывали тихое соединение
+	streamKeepAlive = 15 * time.Second
+	// streamWriteTimeout - сколько ждём запись одного сообщения клиенту
+	streamWriteTimeout = 10 * time.Second
+)
+
+// Страница может открываться с другого origin, как и остальное API (CORS *)
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+func feedFilterFromQuery(q url.Values) feed.Filter {
+	return feed.Filter{
+		DeliveryService: q.Get("delivery_service"),
+		CustomerID:      q.Get("customer_id"),
+	}
+}
+
+// StreamOrders отдаёт новые заказы через Server-Sent Events (событие order,
+// id - order_uid). Если клиент не успевает читать, поток завершается событием
+// error, после которого браузерный EventSource переподключится сам.
+func (h *UserHandler) StreamOrders(w http.ResponseWriter, r *http.Request) {
+	rc := http.NewResponseController(w)
+	// поток живёт дольше HTTP_TIMEOUT
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	sub := h.service.SubscribeOrders(feedFilterFromQuery(r.URL.Query()))
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "retry: 3000\n\n")
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		var err error
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			_, err = fmt.Fprint(w, ": keep-alive\n\n")
+		case order, ok := <-sub.C:
+			if !ok {
+				if errors.Is(sub.Err(), feed.ErrSlowSubscriber) {
+					fmt.Fprint(w, "event: error\ndata: {\"msg\":\"subscriber is too slow\"}\n\n")
+					_ = rc.Flush()
+				}
+				return
+			}
+			var data []byte
+			if data, err = json.Marshal(order); err == nil {
+				_, err = fmt.Fprintf(w, "event: order\nid: %s\ndata: %s\n\n", order.OrderUID, data)
+			}
+		}
+		if err == nil {
+			_ = rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+			err = rc.Flush()
+			_ = rc.SetWriteDeadline(time.Time{})
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// StreamOrdersWS - то же через WebSocket: каждое сообщение - OrderResponse в JSON.
+// Медленный клиент отключается с кодом 1013 (try again later).
+func (h *UserHandler) StreamOrdersWS(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade уже ответил клиенту ошибкой
+		return
+	}
+	defer conn.Close()
+
+	sub := h.service.SubscribeOrders(feedFilterFromQuery(r.URL.Query()))
+	defer sub.Close()
+
+	// читаем, чтобы обрабатывались pong и close; входящие сообщения не ожидаются
+	closed := make(chan struct{})
+	_ = conn.SetReadDeadline(time.Now().Add(2 * streamKeepAlive))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(2 * streamKeepAlive))
+	})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(streamKeepAlive)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			closeWS(conn, websocket.CloseGoingAway, "server shutdown")
+			return
+		case <-closed:
+			return
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(streamWriteTimeout)); err != nil {
+				return
+			}
+		case order, ok := <-sub.C:
+			if !ok {
+				if errors.Is(sub.Err(), feed.ErrSlowSubscriber) {
+					closeWS(conn, websocket.CloseTryAgainLater, "subscriber is too slow")
+				} else {
+					closeWS(conn, websocket.CloseGoingAway, "server shutdown")
+				}
+				return
+			}
+			_ = conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+			if err := conn.WriteJSON(order); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func closeWS(conn *websocket.Conn, code int, reason string) {
+	msg := websocket.FormatCloseMessage(code, reason)
+	_ = conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(time.Second))
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"L0-wb/internal/mocks"
+	"L0-wb/internal/models"
+	"L0-wb/internal/service/feed"
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// waitSubscribers ждёт, пока обработчик подпишется, чтобы заказ не ушёл раньше подписки
+func waitSubscribers(t *testing.T, hub *feed.Hub, n int) {
+	require.Eventually(t, func() bool { return hub.Subscribers() == n }, time.Second, 5*time.Millisecond)
+}
+
+func TestStreamOrders_SSE(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	hub := feed.New(1)
+	mockService.EXPECT().SubscribeOrders(feed.Filter{DeliveryService: "meest"}).
+		DoAndReturn(func(f feed.Filter) *feed.Subscription { return hub.Subscribe(f) })
+
+	srv := httptest.NewServer(http.HandlerFunc(NewHandler(mockService).StreamOrders))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "?delivery_service=meest")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	waitSubscribers(t, hub, 1)
+
+	hub.Publish(
+		&models.Order{OrderUID: "skip", DeliveryService: "cdek"},
+		&models.Order{OrderUID: "a", DeliveryService: "meest"},
+	)
+
+	events := readEvents(t, bufio.NewReader(resp.Body), 1)
+	assert.Equal(t, "order", events[0]["event"])
+	assert.Equal(t, "a", events[0]["id"])
+	var order models.OrderResponse
+	require.NoError(t, json.Unmarshal([]byte(events[0]["data"]), &order))
+	assert.Equal(t, "meest", order.DeliveryService)
+}
+
+func TestStreamOrders_SSESlowSubscriber(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	hub := feed.New(1)
+	var sub *feed.Subscription
+	mockService.EXPECT().SubscribeOrders(gomock.Any()).
+		DoAndReturn(func(f feed.Filter) *feed.Subscription {
+			sub = hub.Subscribe(f)
+			// переполняем буфер до того, как обработчик начнёт читать
+			hub.Publish(&models.Order{OrderUID: "a"}, &models.Order{OrderUID: "b"})
+			return sub
+		})
+
+	w := httptest.NewRecorder()
+	NewHandler(mockService).StreamOrders(w, httptest.NewRequest(http.MethodGet, "/orders/stream", nil))
+
+	events := readEvents(t, bufio.NewReader(w.Body), 2)
+	assert.Equal(t, "a", events[0]["id"])
+	assert.Equal(t, "error", events[1]["event"])
+	assert.ErrorIs(t, sub.Err(), feed.ErrSlowSubscriber)
+}
+
+func TestStreamOrders_WebSocket(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	hub := feed.New(4)
+	mockService.EXPECT().SubscribeOrders(feed.Filter{CustomerID: "ivan"}).
+		DoAndReturn(func(f feed.Filter) *feed.Subscription { return hub.Subscribe(f) })
+
+	srv := httptest.NewServer(http.HandlerFunc(NewHandler(mockService).StreamOrdersWS))
+	defer srv.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"?customer_id=ivan", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	waitSubscribers(t, hub, 1)
+
+	hub.Publish(&models.Order{OrderUID: "a", CustomerID: "ivan"})
+	var order models.OrderResponse
+	require.NoError(t, conn.ReadJSON(&order))
+	assert.Equal(t, "a", order.OrderUID)
+
+	// остановка хаба закрывает соединение с кодом going away
+	hub.Close()
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), "got %v", err)
+}
+
+// readEvents читает n событий SSE, пропуская служебные строки
+func readEvents(t *testing.T, r *bufio.Reader, n int) []map[string]string {
+	var events []map[string]string
+	event := map[string]string{}
+	for len(events) < n {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimRight(line, "\n")
+		if line == "" {
+			if event["event"] != "" {
+				events = append(events, event)
+			}
+			event = map[string]string{}
+			continue
+		}
+		if field, value, ok := strings.Cut(line, ": "); ok {
+			event[field] = value
+		}
+	}
+	return events
+}
@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"L0-wb/internal/models"
+	"L0-wb/internal/service"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	webhookDeliveriesLimit    = 50
+	webhookDeliveriesMaxLimit = 500
+	webhookMaxBody            = 64 << 10
+)
+
+// webhookRequest - тело POST и PUT /admin/webhooks; active по умолчанию true
+type webhookRequest struct {
+	URL             string   `json:"url"`
+	Secret          string   `json:"secret"`
+	Events          []string `json:"events"`
+	CustomerID      string   `json:"customer_id"`
+	DeliveryService string   `json:"delivery_service"`
+	Active          *bool    `json:"active"`
+}
+
+func (req webhookRequest) subscription() models.WebhookSubscription {
+	sub := models.WebhookSubscription{
+		URL:             req.URL,
+		Secret:          req.Secret,
+		Events:          req.Events,
+		CustomerID:      req.CustomerID,
+		DeliveryService: req.DeliveryService,
+		Active:          true,
+	}
+	if sub.Events == nil {
+		sub.Events = []string{}
+	}
+	if req.Active != nil {
+		sub.Active = *req.Active
+	}
+	return sub
+}
+
+// ListWebhooks отдаёт подписки без секретов
+func (h *UserHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	subs, err := h.service.ListWebhooks(r.Context())
+	if err != nil {
+		writeWebhookError(w, r, err)
+		return
+	}
+	for i := range subs {
+		subs[i].Secret = ""
+	}
+	writeData(w, http.StatusOK, subs)
+}
+
+// CreateWebhook регистрирует подписку. Секрет для проверки подписи
+// возвращается только в этом ответе.
+func (h *UserHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	sub, ok := decodeWebhook(w, r)
+	if !ok {
+		return
+	}
+	if err := h.service.CreateWebhook(r.Context(), &sub); err != nil {
+		writeWebhookError(w, r, err)
+		return
+	}
+	writeData(w, http.StatusCreated, sub)
+}
+
+func (h *UserHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	id, ok := webhookID(w, r)
+	if !ok {
+		return
+	}
+	sub, err := h.service.GetWebhook(r.Context(), id)
+	if err != nil {
+		writeWebhookError(w, r, err)
+		return
+	}
+	sub.Secret = ""
+	writeData(w, http.StatusOK, sub)
+}
+
+// UpdateWebhook заменяет подписку целиком; без secret в теле остаётся прежний
+func (h *UserHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	id, ok := webhookID(w, r)
+	if !ok {
+		return
+	}
+	sub, ok := decodeWebhook(w, r)
+	if !ok {
+		return
+	}
+	sub.ID = id
+	if err := h.service.UpdateWebhook(r.Context(), &sub); err != nil {
+		writeWebhookError(w, r, err)
+		return
+	}
+	sub.Secret = ""
+	writeData(w, http.StatusOK, sub)
+}
+
+func (h *UserHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, ok := webhookID(w, r)
+	if !ok {
+		return
+	}
+	if err := h.service.DeleteWebhook(r.Context(), id); err != nil {
+		writeWebhookError(w, r, err)
+		return
+	}
+	writeData(w, http.StatusOK, nil)
+}
+
+// WebhookDeliveries отдаёт журнал доставок подписки: последние ?limit= записей
+func (h *UserHandler) WebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, ok := webhookID(w, r)
+	if !ok {
+		return
+	}
+	limit := webhookDeliveriesLimit
+	if s := r.URL.Query().Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 || n > webhookDeliveriesMaxLimit {
+			writeProblem(w, r, http.StatusBadRequest, CodeInvalidArgument,
+				"limit must be between 1 and "+strconv.Itoa(webhookDeliveriesMaxLimit))
+			return
+		}
+		limit = n
+	}
+
+	deliveries, err := h.service.WebhookDeliveries(r.Context(), id, limit)
+	if err != nil {
+		writeWebhookError(w, r, err)
+		return
+	}
+	writeData(w, http.StatusOK, deliveries)
+}
+
+func webhookID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil || id <= 0 {
+		writeProblem(w, r, http.StatusBadRequest, CodeInvalidArgument, "invalid webhook id")
+		return 0, false
+	}
+	return id, true
+}
+
+// decodeWebhook разбирает и валидирует тело запроса, отвечая 400 при ошибке
+func decodeWebhook(w http.ResponseWriter, r *http.Request) (models.WebhookSubscription, bool) {
+	var req webhookRequest
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, webhookMaxBody))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeInvalidBody, "invalid JSON body: "+err.Error())
+		return models.WebhookSubscription{}, false
+	}
+	sub := req.subscription()
+	if err := sub.Validate(); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeInvalidArgument, err.Error())
+		return models.WebhookSubscription{}, false
+	}
+	return sub, true
+}
+
+func writeWebhookError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, service.ErrNotFound) {
+		writeProblem(w, r, http.StatusNotFound, CodeWebhookNotFound, "Webhook not found")
+		return
+	}
+	writeInternalError(w, r)
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"L0-wb/internal/mocks"
+	"L0-wb/internal/models"
+	"L0-wb/internal/service"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateWebhook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	h := NewHandler(mockService)
+
+	tests := []struct {
+		name       string
+		body       string
+		setup      func()
+		wantStatus int
+	}{{
+		name: "created with generated secret",
+		body: `{"url":"https://partner.example/hook","delivery_service":"meest"}`,
+		setup: func() {
+			mockService.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, sub *models.WebhookSubscription) error {
+					assert.True(t, sub.Active)
+					assert.Equal(t, []string{}, sub.Events)
+					assert.Equal(t, "meest", sub.DeliveryService)
+					sub.ID, sub.Secret = 1, "generated"
+					return nil
+				})
+		},
+		wantStatus: http.StatusCreated,
+	}, {
+		name:       "invalid url",
+		body:       `{"url":"ftp://partner.example"}`,
+		setup:      func() {},
+		wantStatus: http.StatusBadRequest,
+	}, {
+		name:       "unknown event",
+		body:       `{"url":"https://partner.example/hook","events":["order.deleted"]}`,
+		setup:      func() {},
+		wantStatus: http.StatusBadRequest,
+	}, {
+		name:       "unknown field",
+		body:       `{"url":"https://partner.example/hook","filter":"x"}`,
+		setup:      func() {},
+		wantStatus: http.StatusBadRequest,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			w := httptest.NewRecorder()
+			h.CreateWebhook(w, httptest.NewRequest(http.MethodPost, "/admin/webhooks", strings.NewReader(tt.body)))
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestCreateWebhook_ReturnsSecretOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	h := NewHandler(mockService)
+
+	mockService.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, sub *models.WebhookSubscription) error {
+			sub.ID, sub.Secret = 1, "generated"
+			return nil
+		})
+	mockService.EXPECT().ListWebhooks(gomock.Any()).Return([]models.WebhookSubscription{{ID: 1, Secret: "generated"}}, nil)
+
+	w := httptest.NewRecorder()
+	h.CreateWebhook(w, httptest.NewRequest(http.MethodPost, "/admin/webhooks",
+		strings.NewReader(`{"url":"https://partner.example/hook"}`)))
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"secret":"generated"`)
+
+	w = httptest.NewRecorder()
+	h.ListWebhooks(w, httptest.NewRequest(http.MethodGet, "/admin/webhooks", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "secret")
+}
+
+func TestWebhookDeliveries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	h := NewHandler(mockService)
+
+	tests := []struct {
+		name       string
+		id         string
+		url        string
+		setup      func()
+		wantStatus int
+	}{{
+		name: "default limit",
+		id:   "3",
+		url:  "/admin/webhooks/3/deliveries",
+		setup: func() {
+			mockService.EXPECT().WebhookDeliveries(gomock.Any(), int64(3), webhookDeliveriesLimit).
+				Return([]models.WebhookDelivery{{ID: 9, SubscriptionID: 3, Status: models.WebhookDelivered}}, nil)
+		},
+		wantStatus: http.StatusOK,
+	}, {
+		name: "unknown subscription",
+		id:   "4",
+		url:  "/admin/webhooks/4/deliveries?limit=10",
+		setup: func() {
+			mockService.EXPECT().WebhookDeliveries(gomock.Any(), int64(4), 10).Return(nil, service.ErrNotFound)
+		},
+		wantStatus: http.StatusNotFound,
+	}, {
+		name:       "invalid id",
+		id:         "abc",
+		url:        "/admin/webhooks/abc/deliveries",
+		setup:      func() {},
+		wantStatus: http.StatusBadRequest,
+	}, {
+		name:       "limit too large",
+		id:         "3",
+		url:        "/admin/webhooks/3/deliveries?limit=100000",
+		setup:      func() {},
+		wantStatus: http.StatusBadRequest,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			w := httptest.NewRecorder()
+			r := mux.SetURLVars(httptest.NewRequest(http.MethodGet, tt.url, nil), map[string]string{"id": tt.id})
+			h.WebhookDeliveries(w, r)
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if w.Code == http.StatusOK {
+				var response struct {
+					Data []models.WebhookDelivery `json:"data"`
+				}
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+				assert.Len(t, response.Data, 1)
+			}
+		})
+	}
+}
+
+func TestDeleteWebhook_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	h := NewHandler(mockService)
+
+	mockService.EXPECT().DeleteWebhook(gomock.Any(), int64(8)).Return(service.ErrNotFound)
+
+	w := httptest.NewRecorder()
+	h.DeleteWebhook(w, mux.SetURLVars(httptest.NewRequest(http.MethodDelete, "/admin/webhooks/8", nil), map[string]string{"id": "8"}))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,277 @@
+// Package importer загружает исторические заказы из файлов пачками через
+// репозиторий, раскладывая каждую запись в отчёт: принята, отклонена с
+// причиной или оказалась дубликатом. После каждой пачки сохраняется
+// контрольная точка, с которой прерванный импорт можно продолжить.
+package importer
+
+import (
+	"L0-wb/internal/models"
+	"L0-wb/internal/repo"
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/lib/pq"
+)
+
+// Mode - что делать с заказами, которые уже есть в БД
+type Mode string
+
+const (
+	Skip   Mode = "skip"
+	Upsert Mode = "upsert"
+)
+
+// ParseMode разбирает режим; пустая строка - Skip
+func ParseMode(s string) (Mode, error) {
+	switch Mode(s) {
+	case "", Skip:
+		return Skip, nil
+	case Upsert:
+		return Upsert, nil
+	}
+	return "", fmt.Errorf("unknown duplicate mode %q, want skip or upsert", s)
+}
+
+// Status - итог обработки записи
+type Status string
+
+const (
+	Accepted  Status = "accepted"
+	Updated   Status = "updated"   // дубликат, перезаписанный в режиме Upsert
+	Duplicate Status = "duplicate" // дубликат, пропущенный в режиме Skip
+	Rejected  Status = "rejected"
+)
+
+// Result - строка отчёта об импорте
+type Result struct {
+	Line     int    `json:"line"`
+	OrderUID string `json:"order_uid,omitempty"`
+	Status   Status `json:"status"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// Summary - счётчики записей по статусам
+type Summary struct {
+	Accepted   int `json:"accepted"`
+	Updated    int `json:"updated"`
+	Duplicates int `json:"duplicates"`
+	Rejected   int `json:"rejected"`
+}
+
+func (s *Summary) add(status Status) {
+	switch status {
+	case Accepted:
+		s.Accepted++
+	case Updated:
+		s.Updated++
+	case Duplicate:
+		s.Duplicates++
+	case Rejected:
+		s.Rejected++
+	}
+}
+
+func (s Summary) String() string {
+	return fmt.Sprintf("accepted=%d updated=%d duplicates=%d rejected=%d", s.Accepted, s.Updated, s.Duplicates, s.Rejected)
+}
+
+// Options - параметры импорта
+type Options struct {
+	BatchSize int
+	Mode      Mode
+	// After - позиция последней записи, обработанной прошлым запуском; записи до неё пропускаются
+	After int
+	// Summary - счётчики прошлого запуска, к которым добавляются новые
+	Summary Summary
+	// OnResult получает результаты каждой пачки в порядке записей во входном файле
+	OnResult func(Result) error
+	// OnBatch вызывается после записи пачки и её результатов: pos - позиция последней обработанной записи
+	OnBatch func(pos int, summary Summary) error
+}
+
+// Importer записывает заказы пачками через репозиторий
+type Importer struct {
+	repo    repo.Repository
+	opts    Options
+	summary Summary
+
+	results []Result
+	orders  []models.Order
+	index   []int // индекс результата для каждого заказа пачки
+	uids    map[string]bool
+	lastPos int
+}
+
+func New(r repo.Repository, opts Options) *Importer {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	if opts.Mode == "" {
+		opts.Mode = Skip
+	}
+	return &Importer{repo: r, opts: opts, summary: opts.Summary, uids: make(map[string]bool)}
+}
+
+// Run читает src до конца и возвращает итоговые счётчики. При ошибке или
+// отмене ctx необработанная часть пачки не записывается и не попадает в отчёт.
+func (im *Importer) Run(ctx context.Context, src Source) (Summary, error) {
+	for {
+		if err := ctx.Err(); err != nil {
+			return im.summary, err
+		}
+		rec, err := src.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return im.summary, err
+		}
+		if rec.Pos <= im.opts.After {
+			continue
+		}
+		if err := im.add(ctx, rec); err != nil {
+			return im.summary, err
+		}
+	}
+	return im.summary, im.flush(ctx)
+}
+
+func (im *Importer) add(ctx context.Context, rec Record) error {
+	// повтор заказа внутри пачки: записываем пачку, и повтор станет дубликатом из БД
+	if rec.Err == nil && im.uids[rec.Order.OrderUID] {
+		if err := im.flush(ctx); err != nil {
+			return err
+		}
+	}
+
+	im.lastPos = rec.Pos
+	res := Result{Line: rec.Pos}
+	switch {
+	case rec.Err != nil:
+		res.Status, res.Reason = Rejected, "parse error: "+rec.Err.Error()
+	default:
+		res.OrderUID = rec.Order.OrderUID
+		if err := rec.Order.Validate(); err != nil {
+			res.Status, res.Reason = Rejected, err.Error()
+			break
+		}
+		im.orders = append(im.orders, *rec.Order)
+		im.index = append(im.index, len(im.results))
+		im.uids[rec.Order.OrderUID] = true
+	}
+	im.results = append(im.results, res)
+
+	if len(im.results) >= im.opts.BatchSize {
+		return im.flush(ctx)
+	}
+	return nil
+}
+
+// flush записывает пачку, отдаёт её результаты и сохраняет позицию
+func (im *Importer) flush(ctx context.Context) error {
+	if len(im.results) == 0 {
+		return nil
+	}
+	if err := im.write(ctx); err != nil {
+		return err
+	}
+
+	for _, res := range im.results {
+		im.summary.add(res.Status)
+		if im.opts.OnResult != nil {
+			if err := im.opts.OnResult(res); err != nil {
+				return err
+			}
+		}
+	}
+	if im.opts.OnBatch != nil {
+		if err := im.opts.OnBatch(im.lastPos, im.summary); err != nil {
+			return err
+		}
+	}
+
+	im.results, im.orders, im.index = im.results[:0], im.orders[:0], im.index[:0]
+	im.uids = make(map[string]bool)
+	return nil
+}
+
+func (im *Importer) write(ctx context.Context) error {
+	if len(im.orders) == 0 {
+		return nil
+	}
+	uids := make([]string, len(im.orders))
+	for i, o := range im.orders {
+		uids[i] = o.OrderUID
+	}
+	existing, err := im.repo.ExistingOrderUIDs(ctx, uids)
+	if err != nil {
+		return err
+	}
+
+	var batch []models.Order
+	var index []int
+	for i, o := range im.orders {
+		res := &im.results[im.index[i]]
+		if existing[o.OrderUID] {
+			if im.opts.Mode == Skip {
+				res.Status, res.Reason = Duplicate, "order already exists"
+				continue
+			}
+			res.Status = Updated
+		} else {
+			res.Status = Accepted
+		}
+		batch = append(batch, o)
+		index = append(index, im.index[i])
+	}
+	if len(batch) == 0 {
+		return nil
+	}
+
+	if err := im.save(ctx, batch); err == nil || fatal(ctx, err) {
+		return err
+	}
+
+	// пачка откатилась целиком - пишем по одному, чтобы найти виновные заказы
+	for i, o := range batch {
+		err := im.save(ctx, []models.Order{o})
+		if err == nil {
+			continue
+		}
+		if fatal(ctx, err) {
+			return err
+		}
+		res := &im.results[index[i]]
+		res.Status, res.Reason = Rejected, err.Error()
+		if repo.IsDuplicate(err) {
+			res.Status = Duplicate
+		}
+	}
+	return nil
+}
+
+func (im *Importer) save(ctx context.Context, orders []models.Order) error {
+	if im.opts.Mode == Upsert {
+		return im.repo.UpsertOrders(ctx, orders)
+	}
+	return im.repo.CreateOrders(ctx, orders)
+}
+
+// fatal отделяет недоступность БД от ошибок в данных конкретных заказов:
+// при первой импорт останавливается, чтобы не отклонить весь остаток файла
+func fatal(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, driver.ErrBadConn) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	var pqErr *pq.Error
+	// класс 08 - ошибки соединения, 57 - остановка сервера или отмена запроса
+	return errors.As(err, &pqErr) && (pqErr.Code.Class() == "08" || pqErr.Code.Class() == "57")
+}
@@ -0,0 +1,178 @@
+package importer
+
+import (
+	"L0-wb/internal/generator"
+	"L0-wb/internal/mocks"
+	"L0-wb/internal/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ndjson(t *testing.T, orders ...*models.Order) string {
+	var b strings.Builder
+	for _, o := range orders {
+		data, err := json.Marshal(o)
+		require.NoError(t, err)
+		b.Write(data)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// genOrders прогоняет заказы через JSON, чтобы они совпадали с прочитанными из файла
+func genOrders(t *testing.T, seed int64, n int) []*models.Order {
+	gen := generator.New(generator.Config{Seed: seed})
+	orders := make([]*models.Order, n)
+	for i := range orders {
+		data, err := json.Marshal(gen.Order())
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(data, &orders[i]))
+	}
+	return orders
+}
+
+func collect(results *[]Result) func(Result) error {
+	return func(r Result) error {
+		*results = append(*results, r)
+		return nil
+	}
+}
+
+func TestImporter_Skip(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mocks.NewMockRepository(ctrl)
+
+	orders := genOrders(t, 1, 4)
+	a, b, c, invalid := orders[0], orders[1], orders[2], orders[3]
+	invalid.Delivery.Phone = "nope"
+
+	input := ndjson(t, a, invalid) + "{broken\n\n" + ndjson(t, b, c, a)
+
+	mockRepo.EXPECT().ExistingOrderUIDs(gomock.Any(), []string{a.OrderUID, b.OrderUID, c.OrderUID}).
+		Return(map[string]bool{b.OrderUID: true}, nil)
+	mockRepo.EXPECT().CreateOrders(gomock.Any(), []models.Order{*a, *c}).Return(nil)
+	// повтор a в том же файле уходит следующей пачкой и находится в БД
+	mockRepo.EXPECT().ExistingOrderUIDs(gomock.Any(), []string{a.OrderUID}).
+		Return(map[string]bool{a.OrderUID: true}, nil)
+
+	var results []Result
+	var batches []int
+	im := New(mockRepo, Options{
+		BatchSize: 10,
+		OnResult:  collect(&results),
+		OnBatch: func(pos int, _ Summary) error {
+			batches = append(batches, pos)
+			return nil
+		},
+	})
+	summary, err := im.Run(context.Background(), NewNDJSONSource(strings.NewReader(input)))
+	require.NoError(t, err)
+
+	assert.Equal(t, Summary{Accepted: 2, Duplicates: 2, Rejected: 2}, summary)
+	assert.Equal(t, []int{6, 7}, batches)
+	require.Len(t, results, 6)
+	assert.Equal(t, Result{Line: 1, OrderUID: a.OrderUID, Status: Accepted}, results[0])
+	assert.Equal(t, Rejected, results[1].Status)
+	assert.Contains(t, results[1].Reason, "phone")
+	assert.Equal(t, 3, results[2].Line)
+	assert.Contains(t, results[2].Reason, "parse error")
+	assert.Equal(t, Result{Line: 5, OrderUID: b.OrderUID, Status: Duplicate, Reason: "order already exists"}, results[3])
+	assert.Equal(t, Result{Line: 7, OrderUID: a.OrderUID, Status: Duplicate, Reason: "order already exists"}, results[5])
+}
+
+func TestImporter_UpsertAndResume(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mocks.NewMockRepository(ctrl)
+
+	orders := genOrders(t, 2, 3)
+	a, b, c := orders[0], orders[1], orders[2]
+
+	mockRepo.EXPECT().ExistingOrderUIDs(gomock.Any(), []string{b.OrderUID, c.OrderUID}).
+		Return(map[string]bool{c.OrderUID: true}, nil)
+	mockRepo.EXPECT().UpsertOrders(gomock.Any(), []models.Order{*b, *c}).Return(nil)
+
+	var results []Result
+	im := New(mockRepo, Options{
+		Mode:     Upsert,
+		After:    1,
+		Summary:  Summary{Accepted: 1},
+		OnResult: collect(&results),
+	})
+	summary, err := im.Run(context.Background(), NewNDJSONSource(strings.NewReader(ndjson(t, a, b, c))))
+	require.NoError(t, err)
+
+	assert.Equal(t, Summary{Accepted: 2, Updated: 1}, summary)
+	assert.Equal(t, []Result{
+		{Line: 2, OrderUID: b.OrderUID, Status: Accepted},
+		{Line: 3, OrderUID: c.OrderUID, Status: Updated},
+	}, results)
+}
+
+func TestImporter_BatchFallback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mocks.NewMockRepository(ctrl)
+
+	orders := genOrders(t, 3, 3)
+	a, b, c := orders[0], orders[1], orders[2]
+	dup := &pq.Error{Code: "23505", Message: `duplicate key value violates unique constraint "orders_track_number_key"`}
+
+	mockRepo.EXPECT().ExistingOrderUIDs(gomock.Any(), gomock.Any()).Return(map[string]bool{}, nil)
+	mockRepo.EXPECT().CreateOrders(gomock.Any(), []models.Order{*a, *b, *c}).Return(dup)
+	mockRepo.EXPECT().CreateOrders(gomock.Any(), []models.Order{*a}).Return(nil)
+	mockRepo.EXPECT().CreateOrders(gomock.Any(), []models.Order{*b}).Return(dup)
+	mockRepo.EXPECT().CreateOrders(gomock.Any(), []models.Order{*c}).Return(errors.New("value too long for type character varying(50)"))
+
+	var results []Result
+	im := New(mockRepo, Options{OnResult: collect(&results)})
+	summary, err := im.Run(context.Background(), NewNDJSONSource(strings.NewReader(ndjson(t, a, b, c))))
+	require.NoError(t, err)
+
+	assert.Equal(t, Summary{Accepted: 1, Duplicates: 1, Rejected: 1}, summary)
+	assert.Equal(t, Duplicate, results[1].Status)
+	assert.Contains(t, results[1].Reason, "orders_track_number_key")
+	assert.Equal(t, Rejected, results[2].Status)
+}
+
+func TestImporter_FatalError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mocks.NewMockRepository(ctrl)
+
+	a := genOrders(t, 4, 1)[0]
+	down := &pq.Error{Code: "57P01", Message: "terminating connection due to administrator command"}
+
+	mockRepo.EXPECT().ExistingOrderUIDs(gomock.Any(), gomock.Any()).Return(map[string]bool{}, nil)
+	mockRepo.EXPECT().CreateOrders(gomock.Any(), gomock.Any()).Return(down)
+
+	var results []Result
+	im := New(mockRepo, Options{OnResult: collect(&results)})
+	_, err := im.Run(context.Background(), NewNDJSONSource(strings.NewReader(ndjson(t, a))))
+	assert.ErrorIs(t, err, down)
+	assert.Empty(t, results)
+}
+
+func TestCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "import.checkpoint.json")
+
+	cp, err := LoadCheckpoint(path)
+	require.NoError(t, err)
+	assert.Nil(t, cp)
+
+	want := Checkpoint{Input: "orders.ndjson", Position: 42, ReportOffset: 1024, Summary: Summary{Accepted: 40, Rejected: 2}}
+	require.NoError(t, want.Save(path))
+	cp, err = LoadCheckpoint(path)
+	require.NoError(t, err)
+	assert.Equal(t, want, *cp)
+}
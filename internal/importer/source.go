@@ -0,0 +1,79 @@
+package importer
+
+import (
+	"L0-wb/internal/export"
+	"L0-wb/internal/models"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// Record - заказ из входного файла. Pos - номер строки, с которой начинается
+// запись; по нему продолжается прерванный импорт. Err - ошибка разбора записи.
+type Record struct {
+	Pos   int
+	Order *models.Order
+	Err   error
+}
+
+// Source выдаёт записи по порядку; в конце возвращает io.EOF
+type Source interface {
+	Next() (Record, error)
+}
+
+type ndjsonSource struct {
+	r    *bufio.Reader
+	line int
+}
+
+// NewNDJSONSource читает по заказу на строку; битая строка не прерывает чтение
+func NewNDJSONSource(r io.Reader) Source {
+	return &ndjsonSource{r: bufio.NewReader(r)}
+}
+
+func (s *ndjsonSource) Next() (Record, error) {
+	for {
+		line, err := s.r.ReadBytes('\n')
+		if len(line) == 0 && err != nil {
+			return Record{}, err
+		}
+		s.line++
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		rec := Record{Pos: s.line, Order: &models.Order{}}
+		if err := json.Unmarshal(line, rec.Order); err != nil {
+			rec.Order, rec.Err = nil, err
+		}
+		return rec, nil
+	}
+}
+
+type csvSource struct {
+	r *export.CSVReader
+}
+
+// NewCSVSource читает плоский CSV в формате выгрузки: строка на товар
+func NewCSVSource(r io.Reader) (Source, error) {
+	cr, err := export.NewCSVReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return &csvSource{r: cr}, nil
+}
+
+func (s *csvSource) Next() (Record, error) {
+	order, pos, err := s.r.Next()
+	var rowErr *export.RowError
+	if errors.As(err, &rowErr) {
+		return Record{Pos: pos, Err: rowErr.Err}, nil
+	}
+	if err != nil {
+		return Record{}, err
+	}
+	return Record{Pos: pos, Order: order}, nil
+}
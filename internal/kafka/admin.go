@@ -0,0 +1,344 @@
+package kafka
+
+import (
+	"L0-wb/config"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// OffsetSpec задаёт позицию в партиции: смещение, момент времени или край.
+// Смещения у партиций независимы, поэтому одно Offset допустимо только для одной
+// партиции; для нескольких смещения задаются по партициям в Offsets.
+type OffsetSpec struct {
+	Offset   int64
+	Offsets  map[int]int64
+	Time     time.Time
+	Earliest bool
+	Latest   bool
+}
+
+// ParseOffsetSpec разбирает "earliest", "latest", смещение, смещения по партициям
+// "partition:offset,..." или время в RFC3339
+func ParseOffsetSpec(s string) (OffsetSpec, error) {
+	s = strings.TrimSpace(s)
+	switch strings.ToLower(s) {
+	case "":
+		return OffsetSpec{}, fmt.Errorf("offset spec is empty")
+	case "earliest":
+		return OffsetSpec{Earliest: true}, nil
+	case "latest":
+		return OffsetSpec{Latest: true}, nil
+	}
+
+	if offset, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if offset < 0 {
+			return OffsetSpec{}, fmt.Errorf("offset must be non-negative: %d", offset)
+		}
+		return OffsetSpec{Offset: offset}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return OffsetSpec{Time: t}, nil
+	}
+	if strings.Contains(s, ":") {
+		offsets := make(map[int]int64)
+		for _, part := range strings.Split(s, ",") {
+			ps, offStr, ok := strings.Cut(strings.TrimSpace(part), ":")
+			p, pErr := strconv.Atoi(ps)
+			offset, oErr := strconv.ParseInt(offStr, 10, 64)
+			if !ok || pErr != nil || oErr != nil || p < 0 || offset < 0 {
+				return OffsetSpec{}, fmt.Errorf("invalid partition offset %q: want partition:offset", part)
+			}
+			if _, dup := offsets[p]; dup {
+				return OffsetSpec{}, fmt.Errorf("duplicate partition %d in offset spec", p)
+			}
+			offsets[p] = offset
+		}
+		return OffsetSpec{Offsets: offsets}, nil
+	}
+	return OffsetSpec{}, fmt.Errorf("invalid offset spec %q: want earliest, latest, offset, partition:offset list or RFC3339 time", s)
+}
+
+// Partitions возвращает партиции, для которых смещения заданы явно, по возрастанию
+func (s OffsetSpec) Partitions() []int {
+	partitions := make([]int, 0, len(s.Offsets))
+	for p := range s.Offsets {
+		partitions = append(partitions, p)
+	}
+	sort.Ints(partitions)
+	return partitions
+}
+
+func (s OffsetSpec) String() string {
+	switch {
+	case s.Earliest:
+		return "earliest"
+	case s.Latest:
+		return "latest"
+	case !s.Time.IsZero():
+		return s.Time.Format(time.RFC3339)
+	case s.Offsets != nil:
+		parts := make([]string, 0, len(s.Offsets))
+		for _, p := range s.Partitions() {
+			parts = append(parts, fmt.Sprintf("%d:%d", p, s.Offsets[p]))
+		}
+		return strings.Join(parts, ",")
+	default:
+		return strconv.FormatInt(s.Offset, 10)
+	}
+}
+
+// ParsePartitions разбирает список партиций "0,1,2"; пустая строка - все партиции
+func ParsePartitions(s string) ([]int, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	var partitions []int
+	for _, part := range strings.Split(s, ",") {
+		p, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || p < 0 {
+			return nil, fmt.Errorf("invalid partition %q", part)
+		}
+		partitions = append(partitions, p)
+	}
+	return partitions, nil
+}
+
+// Admin выполняет служебные операции над топиком заказов и группой консьюмера
+type Admin struct {
+	client *kafka.Client
+	topic  string
+	group  string
+}
+
+func NewAdmin(cfg config.Config) *Admin {
+	brokerAddr := fmt.Sprintf("%s:%d", cfg.Kafka.Host, cfg.Kafka.Port)
+	return &Admin{
+		client: &kafka.Client{Addr: kafka.TCP(brokerAddr), Timeout: 10 * time.Second},
+		topic:  cfg.Kafka.Topic,
+		group:  cfg.Kafka.Group,
+	}
+}
+
+// Partitions возвращает номера партиций топика по возрастанию
+func (a *Admin) Partitions(ctx context.Context) ([]int, error) {
+	resp, err := a.client.Metadata(ctx, &kafka.MetadataRequest{Topics: []string{a.topic}})
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range resp.Topics {
+		if t.Name != a.topic {
+			continue
+		}
+		if t.Error != nil {
+			return nil, fmt.Errorf("topic %s metadata: %w", a.topic, t.Error)
+		}
+		partitions := make([]int, 0, len(t.Partitions))
+		for _, p := range t.Partitions {
+			partitions = append(partitions, p.ID)
+		}
+		sort.Ints(partitions)
+		return partitions, nil
+	}
+	return nil, fmt.Errorf("topic %s not found", a.topic)
+}
+
+// ResolveOffsets переводит OffsetSpec в конкретные смещения партиций.
+// Пустой список партиций означает все партиции топика, а для смещений по
+// партициям - партиции из spec.
+func (a *Admin) ResolveOffsets(ctx context.Context, partitions []int, spec OffsetSpec) (map[int]int64, error) {
+	if len(partitions) == 0 && spec.Offsets != nil {
+		partitions = spec.Partitions()
+	}
+	partitions, err := a.partitionsOrAll(ctx, partitions)
+	if err != nil {
+		return nil, err
+	}
+	if err := spec.check(partitions); err != nil {
+		return nil, err
+	}
+
+	bounds, err := a.listOffsets(ctx, partitions, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	offsets := make(map[int]int64, len(partitions))
+	for _, p := range partitions {
+		b, ok := bounds[p]
+		if !ok {
+			return nil, fmt.Errorf("no offsets returned for partition %d", p)
+		}
+		switch {
+		case spec.Earliest:
+			offsets[p] = b.FirstOffset
+		case spec.Latest:
+			offsets[p] = b.LastOffset
+		case !spec.Time.IsZero():
+			offsets[p] = b.LastOffset // сообщений после указанного времени нет
+			for offset := range b.Offsets {
+				if offset >= 0 {
+					offsets[p] = offset
+				}
+			}
+		default:
+			// смещение ограничивается доступным диапазоном партиции
+			offset := spec.Offset
+			if spec.Offsets != nil {
+				offset = spec.Offsets[p]
+			}
+			if offset < b.FirstOffset {
+				offset = b.FirstOffset
+			}
+			if offset > b.LastOffset {
+				offset = b.LastOffset
+			}
+			offsets[p] = offset
+		}
+	}
+	return offsets, nil
+}
+
+// check проверяет, что смещения заданы для каждой из partitions
+func (s OffsetSpec) check(partitions []int) error {
+	if s.Earliest || s.Latest || !s.Time.IsZero() {
+		return nil
+	}
+	if s.Offsets == nil {
+		if len(partitions) != 1 {
+			return fmt.Errorf("offset %d is ambiguous for %d partitions: use partition:offset,... or -partitions", s.Offset, len(partitions))
+		}
+		return nil
+	}
+	for _, p := range partitions {
+		if _, ok := s.Offsets[p]; !ok {
+			return fmt.Errorf("no offset for partition %d in %q", p, s)
+		}
+	}
+	return nil
+}
+
+// ResetGroupOffsets сдвигает закоммиченные смещения группы.
+// Kafka принимает такой коммит только когда в группе нет активных участников,
+// поэтому сервис на время сброса должен быть остановлен.
+func (a *Admin) ResetGroupOffsets(ctx context.Context, partitions []int, spec OffsetSpec) (map[int]int64, error) {
+	offsets, err := a.ResolveOffsets(ctx, partitions, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	commits := make([]kafka.OffsetCommit, 0, len(offsets))
+	for p, offset := range offsets {
+		commits = append(commits, kafka.OffsetCommit{Partition: p, Offset: offset})
+	}
+
+	resp, err := a.client.OffsetCommit(ctx, &kafka.OffsetCommitRequest{
+		GroupID:      a.group,
+		GenerationID: -1,
+		Topics:       map[string][]kafka.OffsetCommit{a.topic: commits},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("commit offsets for group %s: %w", a.group, err)
+	}
+	for _, p := range resp.Topics[a.topic] {
+		if p.Error != nil {
+			return nil, fmt.Errorf("commit offset for partition %d: %w", p.Partition, p.Error)
+		}
+	}
+	return offsets, nil
+}
+
+// PartitionLag - отставание consumer group в партиции.
+// Committed = -1, если группа ещё ничего не коммитила: тогда лаг считается от начала партиции.
+type PartitionLag struct {
+	Partition int
+	Committed int64
+	Latest    int64
+	Lag       int64
+}
+
+// ConsumerLag возвращает отставание группы по всем партициям топика
+func (a *Admin) ConsumerLag(ctx context.Context) ([]PartitionLag, error) {
+	partitions, err := a.Partitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bounds, err := a.listOffsets(ctx, partitions, OffsetSpec{Latest: true})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := a.client.OffsetFetch(ctx, &kafka.OffsetFetchRequest{
+		GroupID: a.group,
+		Topics:  map[string][]int{a.topic: partitions},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetch offsets for group %s: %w", a.group, err)
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("fetch offsets for group %s: %w", a.group, resp.Error)
+	}
+
+	committed := make(map[int]int64, len(partitions))
+	for _, p := range resp.Topics[a.topic] {
+		if p.Error != nil {
+			return nil, fmt.Errorf("fetch offset for partition %d: %w", p.Partition, p.Error)
+		}
+		committed[p.Partition] = p.CommittedOffset
+	}
+
+	lags := make([]PartitionLag, 0, len(partitions))
+	for _, p := range partitions {
+		lag := PartitionLag{Partition: p, Committed: -1, Latest: bounds[p].LastOffset}
+		start := bounds[p].FirstOffset
+		if offset, ok := committed[p]; ok && offset >= 0 {
+			lag.Committed = offset
+			start = offset
+		}
+		lag.Lag = lag.Latest - start
+		if lag.Lag < 0 {
+			lag.Lag = 0
+		}
+		lags = append(lags, lag)
+	}
+	return lags, nil
+}
+
+func (a *Admin) partitionsOrAll(ctx context.Context, partitions []int) ([]int, error) {
+	if len(partitions) > 0 {
+		return partitions, nil
+	}
+	return a.Partitions(ctx)
+}
+
+// listOffsets запрашивает границы партиций и, при необходимости, смещение по времени
+func (a *Admin) listOffsets(ctx context.Context, partitions []int, spec OffsetSpec) (map[int]kafka.PartitionOffsets, error) {
+	requests := make([]kafka.OffsetRequest, 0, len(partitions)*3)
+	for _, p := range partitions {
+		requests = append(requests, kafka.FirstOffsetOf(p), kafka.LastOffsetOf(p))
+		if !spec.Time.IsZero() {
+			requests = append(requests, kafka.TimeOffsetOf(p, spec.Time))
+		}
+	}
+
+	resp, err := a.client.ListOffsets(ctx, &kafka.ListOffsetsRequest{
+		Topics: map[string][]kafka.OffsetRequest{a.topic: requests},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := make(map[int]kafka.PartitionOffsets, len(partitions))
+	for _, p := range resp.Topics[a.topic] {
+		if p.Error != nil {
+			return nil, fmt.Errorf("list offsets for partition %d: %w", p.Partition, p.Error)
+		}
+		bounds[p.Partition] = p
+	}
+	return bounds, nil
+}
@@ -0,0 +1,70 @@
+package kafka
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOffsetSpec(t *testing.T) {
+	at := time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		input   string
+		want    OffsetSpec
+		wantErr bool
+	}{
+		{input: "earliest", want: OffsetSpec{Earliest: true}},
+		{input: "LATEST", want: OffsetSpec{Latest: true}},
+		{input: "42", want: OffsetSpec{Offset: 42}},
+		{input: "2025-09-01T10:00:00Z", want: OffsetSpec{Time: at}},
+		{input: "0:15, 2:3", want: OffsetSpec{Offsets: map[int]int64{0: 15, 2: 3}}},
+		{input: "", wantErr: true},
+		{input: "0:15,0:16", wantErr: true},
+		{input: "0:-1", wantErr: true},
+		{input: "0:x", wantErr: true},
+		{input: "-1", wantErr: true},
+		{input: "yesterday", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseOffsetSpec(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tt.want.Time.Equal(got.Time))
+			got.Time, tt.want.Time = time.Time{}, time.Time{}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestOffsetSpec_Check(t *testing.T) {
+	assert.NoError(t, OffsetSpec{Earliest: true}.check([]int{0, 1}))
+	assert.NoError(t, OffsetSpec{Offset: 42}.check([]int{1}))
+	assert.Error(t, OffsetSpec{Offset: 42}.check([]int{0, 1}), "одно смещение на несколько партиций")
+
+	spec := OffsetSpec{Offsets: map[int]int64{0: 15, 2: 3}}
+	assert.Equal(t, []int{0, 2}, spec.Partitions())
+	assert.Equal(t, "0:15,2:3", spec.String())
+	assert.NoError(t, spec.check([]int{0, 2}))
+	assert.Error(t, spec.check([]int{0, 1}))
+}
+
+func TestParsePartitions(t *testing.T) {
+	partitions, err := ParsePartitions("0, 2,5")
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 2, 5}, partitions)
+
+	partitions, err = ParsePartitions("")
+	require.NoError(t, err)
+	assert.Nil(t, partitions)
+
+	_, err = ParsePartitions("1,x")
+	assert.Error(t, err)
+}
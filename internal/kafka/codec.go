@@ -0,0 +1,201 @@
+package kafka
+
+import (
+	"L0-wb/config"
+	"L0-wb/internal/models"
+	"L0-wb/internal/schemaregistry"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Codec декодирует Kafka-сообщение в заказ текущей версии схемы
+type Codec interface {
+	Decode(ctx context.Context, msg kafka.Message) (*models.Order, error)
+}
+
+// JSONCodec декодирует JSON-заказы с версией из заголовка schema_version,
+// из конверта {"schema_version": N, "payload": {...}} или без версии (legacy)
+type JSONCodec struct {
+	upcasters *UpcasterRegistry
+}
+
+func NewJSONCodec(upcasters *UpcasterRegistry) *JSONCodec {
+	if upcasters == nil {
+		upcasters = DefaultUpcasters()
+	}
+	return &JSONCodec{upcasters: upcasters}
+}
+
+func (c *JSONCodec) Decode(_ context.Context, msg kafka.Message) (*models.Order, error) {
+	version, payload, err := splitVersion(msg)
+	if err != nil {
+		return nil, err
+	}
+	return decodeVersioned(c.upcasters, version, payload)
+}
+
+// ConfluentCodec декодирует сообщения в Confluent wire format
+// (magic byte + ID схемы + JSON). Версию заказа объявляет сама JSON Schema
+// (см. declaredVersion); версия subject в реестре с ней не связана.
+// Сообщения без magic byte передаются в fallback.
+type ConfluentCodec struct {
+	registry  schemaregistry.Client
+	upcasters *UpcasterRegistry
+	fallback  Codec
+	// versions - объявленная версия заказа по ID схемы (0 - не объявлена)
+	versions sync.Map
+}
+
+func NewConfluentCodec(registry schemaregistry.Client, upcasters *UpcasterRegistry, fallback Codec) *ConfluentCodec {
+	if upcasters == nil {
+		upcasters = DefaultUpcasters()
+	}
+	if fallback == nil {
+		fallback = NewJSONCodec(upcasters)
+	}
+	return &ConfluentCodec{
+		registry:  registry,
+		upcasters: upcasters,
+		fallback:  fallback,
+	}
+}
+
+func (c *ConfluentCodec) Decode(ctx context.Context, msg kafka.Message) (*models.Order, error) {
+	id, payload, ok := schemaregistry.ParseWireFormat(msg.Value)
+	if !ok {
+		return c.fallback.Decode(ctx, msg)
+	}
+
+	schema, err := c.registry.GetSchemaByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, schemaregistry.ErrSchemaNotFound) {
+			return nil, fmt.Errorf("%w: %v", ErrUnsupportedSchemaVersion, err)
+		}
+		return nil, fmt.Errorf("%w: resolve schema %d: %w", ErrSchemaUnavailable, id, err)
+	}
+	if schema.SchemaType != schemaregistry.SchemaTypeJSON {
+		return nil, fmt.Errorf("%w: %s (schema %d)", ErrUnsupportedSchemaType, schema.SchemaType, id)
+	}
+
+	version, err := c.payloadVersion(schema)
+	if err != nil {
+		return nil, err
+	}
+	if version == 0 {
+		// схема версию не объявляет - берём её из заголовка или тела, как JSONCodec
+		version, payload, err = splitVersion(kafka.Message{Headers: msg.Headers, Value: payload})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return decodeVersioned(c.upcasters, version, payload)
+}
+
+// payloadVersion возвращает версию заказа, объявленную схемой, с кэшем по ID
+func (c *ConfluentCodec) payloadVersion(schema *schemaregistry.Schema) (int, error) {
+	if v, ok := c.versions.Load(schema.ID); ok {
+		return v.(int), nil
+	}
+	version, err := declaredVersion(schema.Schema)
+	if err != nil {
+		return 0, fmt.Errorf("%w: schema %d: %v", ErrUnsupportedSchemaVersion, schema.ID, err)
+	}
+	c.versions.Store(schema.ID, version)
+	return version, nil
+}
+
+// declaredVersion читает версию заказа из JSON Schema:
+// {"properties": {"schema_version": {"const": N}}}. 0 - схема версию не объявляет.
+func declaredVersion(schema string) (int, error) {
+	var doc struct {
+		Properties struct {
+			SchemaVersion struct {
+				Const *int `json:"const"`
+			} `json:"schema_version"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal([]byte(schema), &doc); err != nil {
+		return 0, fmt.Errorf("parse JSON schema: %w", err)
+	}
+	v := doc.Properties.SchemaVersion.Const
+	if v == nil {
+		return 0, nil
+	}
+	if *v < 1 {
+		return 0, fmt.Errorf("invalid schema_version const %d", *v)
+	}
+	return *v, nil
+}
+
+// NewCodec выбирает кодек по конфигурации: при заданном реестре схем
+// используется Confluent wire format, иначе - JSON с версией в заголовке
+func NewCodec(cfg config.Config) Codec {
+	upcasters := DefaultUpcasters()
+
+	var registry schemaregistry.Client
+	switch {
+	case cfg.Kafka.SchemaRegistryURL != "":
+		// TopicNameStrategy: схемы значений топика лежат в subject <topic>-value
+		registry = schemaregistry.NewHTTPClient(cfg.Kafka.SchemaRegistryURL, cfg.Kafka.Topic+"-value", 5*time.Second)
+	case cfg.Kafka.SchemaRegistryDir != "":
+		registry = schemaregistry.NewLocalClient(cfg.Kafka.SchemaRegistryDir)
+	default:
+		return NewJSONCodec(upcasters)
+	}
+
+	return NewConfluentCodec(schemaregistry.NewCachedClient(registry), upcasters, nil)
+}
+
+// decodeVersioned апкастит payload до текущей версии и разбирает его в models.Order
+func decodeVersioned(upcasters *UpcasterRegistry, version int, payload []byte) (*models.Order, error) {
+	current, err := upcasters.Upcast(version, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var order models.Order
+	if err := json.Unmarshal(current, &order); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedMessage, err)
+	}
+	if order.OrderUID == "" {
+		return nil, fmt.Errorf("%w: missing order_uid", ErrMalformedMessage)
+	}
+	return &order, nil
+}
+
+// splitVersion определяет версию схемы сообщения и отделяет payload заказа
+func splitVersion(msg kafka.Message) (int, []byte, error) {
+	for _, h := range msg.Headers {
+		if h.Key != SchemaVersionHeader {
+			continue
+		}
+		version, err := strconv.Atoi(string(h.Value))
+		if err != nil {
+			return 0, nil, fmt.Errorf("%w: invalid %s header %q", ErrMalformedMessage, SchemaVersionHeader, h.Value)
+		}
+		return version, msg.Value, nil
+	}
+
+	var probe struct {
+		SchemaVersion *int            `json:"schema_version"`
+		Payload       json.RawMessage `json:"payload"`
+	}
+	if err := json.Unmarshal(msg.Value, &probe); err != nil {
+		return 0, nil, fmt.Errorf("%w: %v", ErrMalformedMessage, err)
+	}
+	if probe.SchemaVersion == nil {
+		return LegacySchemaVersion, msg.Value, nil
+	}
+	if len(probe.Payload) == 0 {
+		// версия указана прямо в теле заказа
+		return *probe.SchemaVersion, msg.Value, nil
+	}
+	return *probe.SchemaVersion, probe.Payload, nil
+}
@@ -0,0 +1,239 @@
+package kafka
+
+import (
+	"L0-wb/internal/schemaregistry"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONCodec_Decode(t *testing.T) {
+	codec := NewJSONCodec(nil)
+
+	tests := []struct {
+		name    string
+		msg     kafka.Message
+		wantUID string
+		wantErr error
+	}{
+		{
+			name:    "legacy payload without version",
+			msg:     kafka.Message{Value: []byte(`{"order_uid":"legacy-1"}`)},
+			wantUID: "legacy-1",
+		},
+		{
+			name: "version in header",
+			msg: kafka.Message{
+				Value:   []byte(`{"order_uid":"header-1"}`),
+				Headers: []kafka.Header{{Key: SchemaVersionHeader, Value: []byte("1")}},
+			},
+			wantUID: "header-1",
+		},
+		{
+			name:    "envelope",
+			msg:     kafka.Message{Value: []byte(`{"schema_version":1,"payload":{"order_uid":"env-1"}}`)},
+			wantUID: "env-1",
+		},
+		{
+			name:    "inline version",
+			msg:     kafka.Message{Value: []byte(`{"schema_version":1,"order_uid":"inline-1"}`)},
+			wantUID: "inline-1",
+		},
+		{
+			name: "future version in header",
+			msg: kafka.Message{
+				Value:   []byte(`{"order_uid":"future-1"}`),
+				Headers: []kafka.Header{{Key: SchemaVersionHeader, Value: []byte("99")}},
+			},
+			wantErr: ErrUnsupportedSchemaVersion,
+		},
+		{
+			name:    "future version in envelope",
+			msg:     kafka.Message{Value: []byte(`{"schema_version":2,"payload":{"order_uid":"future-2"}}`)},
+			wantErr: ErrUnsupportedSchemaVersion,
+		},
+		{
+			name: "invalid header",
+			msg: kafka.Message{
+				Value:   []byte(`{"order_uid":"bad-header"}`),
+				Headers: []kafka.Header{{Key: SchemaVersionHeader, Value: []byte("v1")}},
+			},
+			wantErr: ErrMalformedMessage,
+		},
+		{
+			name:    "malformed json",
+			msg:     kafka.Message{Value: []byte(`{"order_uid":`)},
+			wantErr: ErrMalformedMessage,
+		},
+		{
+			name:    "missing order_uid",
+			msg:     kafka.Message{Value: []byte(`{"track_number":"WBIL1"}`)},
+			wantErr: ErrMalformedMessage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, err := codec.Decode(context.Background(), tt.msg)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, order)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantUID, order.OrderUID)
+		})
+	}
+}
+
+func TestUpcasterRegistry_Upcast(t *testing.T) {
+	// v1: delivery.first_name + delivery.last_name, v2: delivery.name
+	registry := NewUpcasterRegistry(2)
+	registry.Register(1, func(payload map[string]interface{}) error {
+		delivery, ok := payload["delivery"].(map[string]interface{})
+		if !ok {
+			return errors.New("delivery is missing")
+		}
+		delivery["name"] = delivery["first_name"].(string) + " " + delivery["last_name"].(string)
+		delete(delivery, "first_name")
+		delete(delivery, "last_name")
+		return nil
+	})
+	codec := NewJSONCodec(registry)
+
+	t.Run("old version is upcasted", func(t *testing.T) {
+		msg := kafka.Message{
+			Value:   []byte(`{"order_uid":"up-1","delivery":{"first_name":"Ivan","last_name":"Ivanov"}}`),
+			Headers: []kafka.Header{{Key: SchemaVersionHeader, Value: []byte("1")}},
+		}
+		order, err := codec.Decode(context.Background(), msg)
+		require.NoError(t, err)
+		assert.Equal(t, "Ivan Ivanov", order.Delivery.Name)
+	})
+
+	t.Run("current version is passed through", func(t *testing.T) {
+		msg := kafka.Message{Value: []byte(`{"schema_version":2,"payload":{"order_uid":"up-2","delivery":{"name":"Petr"}}}`)}
+		order, err := codec.Decode(context.Background(), msg)
+		require.NoError(t, err)
+		assert.Equal(t, "Petr", order.Delivery.Name)
+	})
+
+	t.Run("upcaster error", func(t *testing.T) {
+		msg := kafka.Message{Value: []byte(`{"order_uid":"up-3"}`)}
+		_, err := codec.Decode(context.Background(), msg)
+		assert.Error(t, err)
+	})
+
+	t.Run("missing upcaster", func(t *testing.T) {
+		gap := NewUpcasterRegistry(3)
+		gap.Register(1, func(map[string]interface{}) error { return nil })
+		_, err := gap.Upcast(1, []byte(`{}`))
+		assert.ErrorIs(t, err, ErrUnsupportedSchemaVersion)
+	})
+
+	t.Run("zero version", func(t *testing.T) {
+		_, err := registry.Upcast(0, []byte(`{}`))
+		assert.ErrorIs(t, err, ErrUnsupportedSchemaVersion)
+	})
+
+	assert.Equal(t, []int{1}, registry.Versions())
+	assert.Equal(t, 2, registry.Current())
+}
+
+func TestFailureHeaders(t *testing.T) {
+	msg := kafka.Message{
+		Topic:     "wb-orders",
+		Partition: 2,
+		Offset:    42,
+		Headers:   []kafka.Header{{Key: SchemaVersionHeader, Value: []byte("7")}},
+	}
+
+	headers := failureHeaders(msg, ErrUnsupportedSchemaVersion)
+
+	values := make(map[string]string, len(headers))
+	for _, h := range headers {
+		values[h.Key] = string(h.Value)
+	}
+	assert.Equal(t, "7", values[SchemaVersionHeader])
+	assert.Equal(t, ErrUnsupportedSchemaVersion.Error(), values[HeaderError])
+	assert.Equal(t, "wb-orders", values[HeaderOriginalTopic])
+	assert.Equal(t, "2", values[HeaderOriginalPartition])
+	assert.Equal(t, "42", values[HeaderOriginalOffset])
+	assert.NotEmpty(t, values[HeaderFailedAt])
+}
+
+func TestConfluentCodec_Decode(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "1.json"),
+		[]byte(`{"subject":"wb-orders-value","version":1,"schemaType":"JSON","schema":"{}"}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "2.json"),
+		[]byte(`{"subject":"wb-orders-value","version":1,"schemaType":"JSON","schema":"{\"properties\":{\"schema_version\":{\"const\":5}}}"}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "3.json"),
+		[]byte(`{"subject":"wb-orders-value","version":1,"schemaType":"AVRO","schema":"{}"}`), 0644))
+	// версия subject 5 не влияет на версию заказа, объявленную схемой
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "4.json"),
+		[]byte(`{"subject":"wb-orders-value","version":5,"schemaType":"JSON","schema":"{\"properties\":{\"schema_version\":{\"const\":1}}}"}`), 0644))
+
+	codec := NewConfluentCodec(schemaregistry.NewLocalClient(dir), nil, nil)
+	ctx := context.Background()
+
+	order, err := codec.Decode(ctx, kafka.Message{Value: schemaregistry.EncodeWireFormat(1, []byte(`{"order_uid":"wire-1"}`))})
+	require.NoError(t, err)
+	assert.Equal(t, "wire-1", order.OrderUID)
+
+	_, err = codec.Decode(ctx, kafka.Message{Value: schemaregistry.EncodeWireFormat(2, []byte(`{"order_uid":"wire-2"}`))})
+	assert.ErrorIs(t, err, ErrUnsupportedSchemaVersion)
+
+	_, err = codec.Decode(ctx, kafka.Message{Value: schemaregistry.EncodeWireFormat(3, []byte(`{"order_uid":"wire-3"}`))})
+	assert.ErrorIs(t, err, ErrUnsupportedSchemaType)
+
+	_, err = codec.Decode(ctx, kafka.Message{Value: schemaregistry.EncodeWireFormat(404, []byte(`{"order_uid":"wire-4"}`))})
+	assert.ErrorIs(t, err, ErrUnsupportedSchemaVersion)
+
+	order, err = codec.Decode(ctx, kafka.Message{Value: schemaregistry.EncodeWireFormat(4, []byte(`{"order_uid":"wire-5"}`))})
+	require.NoError(t, err)
+	assert.Equal(t, "wire-5", order.OrderUID)
+
+	// схема без объявленной версии - версия из тела, как у JSONCodec
+	_, err = codec.Decode(ctx, kafka.Message{Value: schemaregistry.EncodeWireFormat(1, []byte(`{"schema_version":7,"payload":{"order_uid":"wire-6"}}`))})
+	assert.ErrorIs(t, err, ErrUnsupportedSchemaVersion)
+
+	// обычный JSON без magic byte
+	order, err = codec.Decode(ctx, kafka.Message{Value: []byte(`{"order_uid":"plain-1"}`)})
+	require.NoError(t, err)
+	assert.Equal(t, "plain-1", order.OrderUID)
+}
+
+// failingRegistry отвечает ошибкой, как реестр при таймауте или 5xx
+type failingRegistry struct{}
+
+func (failingRegistry) GetSchemaByID(_ context.Context, _ int) (*schemaregistry.Schema, error) {
+	return nil, errors.New("schema registry request /schemas/ids/1: unexpected status 503")
+}
+
+func TestConfluentCodec_RegistryUnavailable(t *testing.T) {
+	codec := NewConfluentCodec(failingRegistry{}, nil, nil)
+
+	_, err := codec.Decode(context.Background(), kafka.Message{Value: schemaregistry.EncodeWireFormat(1, []byte(`{"order_uid":"wire-1"}`))})
+	assert.ErrorIs(t, err, ErrSchemaUnavailable)
+	assert.NotErrorIs(t, err, ErrUnsupportedSchemaVersion)
+}
+
+func TestConsumer_DecodeTooLarge(t *testing.T) {
+	c := &Consumer{codec: NewJSONCodec(nil), maxBytes: 32}
+
+	_, err := c.decode(context.Background(), kafka.Message{
+		Value: []byte(`{"order_uid":"big-1","internal_signature":"xxxxxxxxxxxxxxxxxxxxxxxx"}`),
+	})
+	assert.ErrorIs(t, err, ErrMessageTooLarge)
+
+	order, err := c.decode(context.Background(), kafka.Message{Value: []byte(`{"order_uid":"small-1"}`)})
+	require.NoError(t, err)
+	assert.Equal(t, "small-1", order.OrderUID)
+}
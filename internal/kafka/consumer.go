@@ -3,8 +3,9 @@ package kafka
 import (
 	"L0-wb/config"
 	"L0-wb/internal/models"
+	"L0-wb/internal/repo"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -18,11 +19,32 @@ type Service interface {
 	SaveOrder(ctx context.Context, order *models.Order) error
 }
 
+// Задержки между повторами сохранения и декодирования при временных ошибках
+const (
+	retryBaseDelay = 100 * time.Millisecond
+	retryMaxDelay  = 10 * time.Second
+)
+
+// errUnprocessed - consumer остановлен раньше, чем заказ удалось сохранить;
+// смещение такого сообщения коммитить нельзя
+var errUnprocessed = errors.New("message left unprocessed")
+
 type Consumer struct {
-	reader  *kafka.Reader
-	topic   string
-	timeout time.Duration
-	service Service
+	reader      *kafka.Reader
+	topic       string
+	group       string
+	timeout     time.Duration
+	retryBase   time.Duration
+	retryMax    time.Duration
+	workers     int
+	maxInFlight int
+	batchSize   int
+	batchWait   time.Duration
+	maxBytes    int
+	service     Service
+	batcher     BatchProcessor
+	codec       Codec
+	sink        FailureSink
 }
 
 // NewConsumer создаёт Kafka consumer
@@ -31,22 +53,53 @@ func NewConsumer(cfg config.Config, service MessageProcessor) (ConsumerInterface
 	reader := kafka.NewReader(kafka.ReaderConfig{
 		Brokers:        []string{brokerAddr},
 		Topic:          cfg.Kafka.Topic,
+		GroupID:        cfg.Kafka.Group,
 		MinBytes:       10e3,        // 10KB
 		MaxBytes:       10e6,        // 10MB
-		CommitInterval: time.Second, // Авто-коммит каждую секунду
+		CommitInterval: time.Second, // Коммиты отправляются пачкой раз в секунду
 	})
 	logrus.WithFields(logrus.Fields{
 		"brokers": []string{brokerAddr},
 		"topic":   cfg.Kafka.Topic,
+		"group":   cfg.Kafka.Group,
 	}).Info("Kafka consumer initialized")
 
 	to := 5 * time.Second
 
+	workers := cfg.Kafka.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	maxInFlight := cfg.Kafka.MaxInFlight
+	if maxInFlight < workers {
+		maxInFlight = workers
+	}
+
+	batchSize := cfg.Kafka.BatchSize
+	if batchSize < 1 {
+		batchSize = 1
+	}
+	var batcher BatchProcessor
+	if batchSize > 1 {
+		batcher, _ = service.(BatchProcessor)
+	}
+
 	return &Consumer{
-		reader:  reader,
-		topic:   cfg.Kafka.Topic,
-		timeout: to,
-		service: service,
+		reader:      reader,
+		topic:       cfg.Kafka.Topic,
+		group:       cfg.Kafka.Group,
+		timeout:     to,
+		retryBase:   retryBaseDelay,
+		retryMax:    retryMaxDelay,
+		workers:     workers,
+		maxInFlight: maxInFlight,
+		batchSize:   batchSize,
+		batchWait:   cfg.Kafka.BatchInterval,
+		maxBytes:    cfg.Kafka.MaxMessageBytes,
+		service:     service,
+		batcher:     batcher,
+		codec:       NewCodec(cfg),
+		sink:        NewFailureSink(cfg),
 	}, nil
 }
 
@@ -61,50 +114,270 @@ func (c *Consumer) Close() error {
 		}
 		logrus.Infof("consumer для топика %s успешно закрыт", c.topic)
 	}
+	if c.sink != nil {
+		err := c.sink.Close()
+		c.sink = nil
+		if err != nil {
+			logrus.Errorf("ошибка при закрытии failure sink: %v", err)
+			return err
+		}
+	}
 	return nil
 }
 
-// Меняем сигнатуру метода чтобы возвращать ошибку
+// ConsumeMessages читает сообщения и раздаёт их пулу воркеров.
+// Порядок обработки сохраняется в пределах ключа (order_uid), количество
+// сообщений в обработке ограничено maxInFlight, смещения коммитятся по порядку.
+// После отмены ctx чтение прекращается, а уже принятые сообщения дорабатываются;
+// сообщение, которое так и не удалось сохранить, не коммитится.
 func (c *Consumer) ConsumeMessages(ctx context.Context) error {
-	logrus.Infof("Старт чтения сообщений из Kafka (topic: %s)", c.topic)
+	logrus.WithFields(logrus.Fields{
+		"topic":         c.topic,
+		"workers":       c.workers,
+		"max_in_flight": c.maxInFlight,
+		"batch_size":    c.batchSize,
+	}).Info("Старт чтения сообщений из Kafka")
+
+	tracker := newOffsetTracker()
+	slots := make(chan struct{}, c.maxInFlight)
+	// коммит не прерывается отменой ctx, чтобы дренаж зафиксировал начатое
+	procCtx := context.WithoutCancel(ctx)
+
+	pool := newWorkerPool(c.workers, c.maxInFlight, c.batchSize, c.batchWait, func(batch []kafka.Message) {
+		errs := c.handleBatch(ctx, batch)
+		for i, m := range batch {
+			// необработанное сообщение держит смещение партиции до перезапуска
+			if !errors.Is(errs[i], errUnprocessed) {
+				if commit, ok := tracker.Done(m); ok {
+					c.commit(procCtx, commit)
+				}
+			}
+			<-slots
+		}
+	})
+	defer func() {
+		pool.Drain()
+		logrus.WithField("in_flight", tracker.InFlight()).Info("consumer drained")
+	}()
+
 	for {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
-		default:
-			m, err := c.reader.ReadMessage(ctx)
-			if err != nil {
-				if ctx.Err() != nil {
-					return ctx.Err()
-				}
-				logrus.WithError(err).Error("read message error")
-				time.Sleep(time.Second)
-				continue
-			}
+		case slots <- struct{}{}:
+		}
 
-			var order models.Order
-			if err := json.Unmarshal(m.Value, &order); err != nil {
-				logrus.WithError(err).Errorf("unmarshal order error, raw message: %s", string(m.Value))
-				continue
+		m, err := c.reader.FetchMessage(ctx)
+		if err != nil {
+			<-slots
+			if ctx.Err() != nil {
+				return ctx.Err()
 			}
+			logrus.WithError(err).Error("read message error")
+			time.Sleep(time.Second)
+			continue
+		}
 
-			if order.OrderUID == "" {
-				logrus.Errorf("invalid order: missing order_uid. raw message: %s", string(m.Value))
-				continue
-			}
+		tracker.Track(m)
+		if err := pool.Dispatch(ctx, m); err != nil {
+			<-slots
+			return err
+		}
+	}
+}
 
-			// Отправляем заказ в сервисный слой
-			if err := c.service.SaveOrder(ctx, &order); err != nil {
-				logrus.WithError(err).Errorf("failed to save order %s", order.OrderUID)
-			} else {
-				logrus.Infof("order %s saved successfully", order.OrderUID)
+// handleBatch сохраняет микро-пачку одним вызовом сервиса.
+// Без поддержки пачек у сервиса сообщения обрабатываются по одному.
+// Возвращает результат handleMessage для каждого сообщения.
+func (c *Consumer) handleBatch(ctx context.Context, batch []kafka.Message) []error {
+	errs := make([]error, len(batch))
+	if c.batcher == nil || len(batch) == 1 {
+		for i, m := range batch {
+			errs[i] = c.handleMessage(ctx, m)
+		}
+		return errs
+	}
+
+	ctxTimeout, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.timeout)
+	defer cancel()
+
+	orders := make([]*models.Order, 0, len(batch))
+	idx := make([]int, 0, len(batch))
+	for i, m := range batch {
+		order, err := c.decodeWithRetry(ctx, m)
+		if err != nil {
+			if !errors.Is(err, errUnprocessed) {
+				c.rejectWithTimeout(ctx, m, err)
 			}
+			errs[i] = err
+			continue
+		}
+		orders = append(orders, order)
+		idx = append(idx, i)
+	}
+	if len(orders) == 0 {
+		return errs
+	}
 
-			logrus.WithFields(logrus.Fields{
+	saved := 0
+	for j, err := range c.batcher.SaveOrders(ctxTimeout, orders) {
+		i := idx[j]
+		if errors.Is(err, models.ErrBatchFailed) {
+			// пачка откатилась - сохраняем заказы по одному, каждый со своим таймаутом
+			err = c.save(ctx, orders[j])
+		}
+		errs[i] = c.settle(ctx, batch[i], orders[j], err)
+		if errs[i] == nil {
+			saved++
+		}
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"partition": batch[0].Partition,
+		"messages":  len(batch),
+		"saved":     saved,
+	}).Info("batch processed")
+	return errs
+}
+
+// handleMessage декодирует сообщение и сохраняет заказ через сервисный слой.
+// nil - заказ сохранён; ошибка декодирования или валидации - сообщение ушло
+// в failure sink; errUnprocessed - consumer остановлен раньше, чем заказ сохранён.
+func (c *Consumer) handleMessage(ctx context.Context, m kafka.Message) error {
+	order, err := c.decodeWithRetry(ctx, m)
+	if err != nil {
+		if !errors.Is(err, errUnprocessed) {
+			c.rejectWithTimeout(ctx, m, err)
+		}
+		return err
+	}
+
+	err = c.settle(ctx, m, order, c.save(ctx, order))
+	logrus.WithFields(logrus.Fields{
+		"partition": m.Partition,
+		"offset":    m.Offset,
+		"order_uid": order.OrderUID,
+	}).Info("message processed")
+	return err
+}
+
+// settle доводит сохранение заказа до конца по результату первой попытки err.
+// Невалидный заказ уходит в failure sink, повторная доставка уже сохранённого
+// считается обработанной, временные ошибки повторяются с экспоненциальной
+// задержкой до успеха или отмены ctx. Результат - как у handleMessage.
+func (c *Consumer) settle(ctx context.Context, m kafka.Message, order *models.Order, err error) error {
+	delay := c.retryBase
+	for attempt := 1; ; attempt++ {
+		switch {
+		case err == nil:
+			logrus.Infof("order %s saved successfully", order.OrderUID)
+			return nil
+		case errors.Is(err, models.ErrInvalidOrder), repo.IsDataError(err):
+			logrus.WithError(err).Errorf("order %s rejected", order.OrderUID)
+			c.rejectWithTimeout(ctx, m, err)
+			return err
+		case repo.IsDuplicate(err):
+			logrus.WithError(err).Warnf("order %s already saved", order.OrderUID)
+			return nil
+		}
+
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"order_uid": order.OrderUID,
+			"attempt":   attempt,
+			"retry_in":  delay,
+		}).Warn("failed to save order, retrying")
+		select {
+		case <-ctx.Done():
+			logrus.WithError(err).WithFields(logrus.Fields{
 				"partition": m.Partition,
 				"offset":    m.Offset,
 				"order_uid": order.OrderUID,
-			}).Info("message processed")
+			}).Error("consumer stopped before order was saved, offset is not committed")
+			return fmt.Errorf("%w: %w", errUnprocessed, err)
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, c.retryMax)
+		err = c.save(ctx, order)
+	}
+}
+
+// save - одна попытка сохранения со своим таймаутом; отмена ctx начатую попытку не прерывает
+func (c *Consumer) save(ctx context.Context, order *models.Order) error {
+	ctxTimeout, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.timeout)
+	defer cancel()
+	return c.service.SaveOrder(ctxTimeout, order)
+}
+
+// decode проверяет размер сообщения и декодирует заказ
+// decodeWithRetry декодирует сообщение, повторяя попытки, пока реестр схем
+// недоступен: такое сообщение не битое, и в failure sink ему рано. Каждая
+// попытка со своим таймаутом; при отмене ctx - errUnprocessed.
+func (c *Consumer) decodeWithRetry(ctx context.Context, m kafka.Message) (*models.Order, error) {
+	delay := c.retryBase
+	for attempt := 1; ; attempt++ {
+		ctxTimeout, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.timeout)
+		order, err := c.decode(ctxTimeout, m)
+		cancel()
+		if !errors.Is(err, ErrSchemaUnavailable) {
+			return order, err
+		}
+
+		fields := logrus.Fields{"partition": m.Partition, "offset": m.Offset, "attempt": attempt, "retry_in": delay}
+		logrus.WithError(err).WithFields(fields).Warn("schema registry unavailable, retrying")
+		select {
+		case <-ctx.Done():
+			logrus.WithError(err).WithFields(fields).Error("consumer stopped before message was decoded, offset is not committed")
+			return nil, fmt.Errorf("%w: %w", errUnprocessed, err)
+		case <-time.After(delay):
 		}
+		delay = min(delay*2, c.retryMax)
+	}
+}
+
+func (c *Consumer) decode(ctx context.Context, m kafka.Message) (*models.Order, error) {
+	if c.maxBytes > 0 && len(m.Value) > c.maxBytes {
+		err := fmt.Errorf("%w: %d bytes, limit %d", ErrMessageTooLarge, len(m.Value), c.maxBytes)
+		logrus.WithError(err).WithField("offset", m.Offset).Error("decode order error")
+		return nil, err
+	}
+	order, err := c.codec.Decode(ctx, m)
+	if err != nil {
+		logrus.WithError(err).Errorf("decode order error, raw message: %s", string(m.Value))
+		return nil, err
+	}
+	return order, nil
+}
+
+// commit фиксирует смещение, до которого все сообщения партиции обработаны
+func (c *Consumer) commit(ctx context.Context, m kafka.Message) {
+	if c.group == "" {
+		return
+	}
+	if err := c.reader.CommitMessages(ctx, m); err != nil {
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"partition": m.Partition,
+			"offset":    m.Offset,
+		}).Error("commit offset error")
+	}
+}
+
+// rejectWithTimeout - reject с собственным таймаутом, не зависящим от отмены ctx
+func (c *Consumer) rejectWithTimeout(ctx context.Context, m kafka.Message, reason error) {
+	ctxTimeout, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.timeout)
+	defer cancel()
+	c.reject(ctxTimeout, m, reason)
+}
+
+// reject отправляет сообщение, которое не удалось декодировать или провалило
+// валидацию, в failure sink
+func (c *Consumer) reject(ctx context.Context, m kafka.Message, reason error) {
+	if c.sink == nil {
+		return
+	}
+	if err := c.sink.Send(ctx, m, reason); err != nil {
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"partition": m.Partition,
+			"offset":    m.Offset,
+		}).Error("failed to send message to failure sink")
 	}
 }
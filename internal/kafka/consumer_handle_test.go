@@ -0,0 +1,206 @@
+package kafka
+
+import (
+	"L0-wb/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedService возвращает ошибки из очереди, затем nil
+type scriptedService struct {
+	mu       sync.Mutex
+	errs     []error
+	attempts int
+	batchErr []error
+}
+
+func (s *scriptedService) SaveOrder(_ context.Context, _ *models.Order) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attempts++
+	if len(s.errs) == 0 {
+		return nil
+	}
+	err := s.errs[0]
+	s.errs = s.errs[1:]
+	return err
+}
+
+func (s *scriptedService) SaveOrders(_ context.Context, orders []*models.Order) []error {
+	errs := make([]error, len(orders))
+	copy(errs, s.batchErr)
+	return errs
+}
+
+// uidCodec декодирует заказ с order_uid из значения сообщения
+type uidCodec struct{}
+
+func (uidCodec) Decode(_ context.Context, m kafka.Message) (*models.Order, error) {
+	if len(m.Value) == 0 {
+		return nil, errors.New("empty message")
+	}
+	return &models.Order{OrderUID: string(m.Value)}, nil
+}
+
+// recordingSink запоминает отправленные в DLQ сообщения
+type recordingSink struct {
+	offsets []int64
+}
+
+func (s *recordingSink) Send(_ context.Context, m kafka.Message, _ error) error {
+	s.offsets = append(s.offsets, m.Offset)
+	return nil
+}
+
+func (s *recordingSink) Close() error { return nil }
+
+func newTestConsumer(svc *scriptedService, sink *recordingSink) *Consumer {
+	return &Consumer{
+		timeout:   time.Second,
+		retryBase: time.Millisecond,
+		retryMax:  4 * time.Millisecond,
+		service:   svc,
+		batcher:   svc,
+		codec:     uidCodec{},
+		sink:      sink,
+	}
+}
+
+func TestConsumer_HandleMessage(t *testing.T) {
+	transient := errors.New("connection refused")
+	invalid := fmt.Errorf("%w: phone", models.ErrInvalidOrder)
+	duplicate := fmt.Errorf("failed to create order: %w", &pq.Error{Code: "23505"})
+	tooLong := fmt.Errorf("failed to create delivery: %w", &pq.Error{Code: "22001"})
+
+	tests := []struct {
+		name     string
+		value    string
+		errs     []error
+		cancel   bool
+		wantErr  error
+		attempts int
+		dlq      bool
+	}{
+		{name: "saved", value: "uid-1", attempts: 1},
+		{name: "transient error retried", value: "uid-1", errs: []error{transient, transient}, attempts: 3},
+		{name: "invalid order to dlq", value: "uid-1", errs: []error{invalid}, wantErr: models.ErrInvalidOrder, attempts: 1, dlq: true},
+		{name: "rejected by db to dlq", value: "uid-1", errs: []error{tooLong}, wantErr: tooLong, attempts: 1, dlq: true},
+		{name: "redelivered duplicate", value: "uid-1", errs: []error{duplicate}, attempts: 1},
+		{name: "decode error to dlq", value: "", wantErr: errors.New("empty message"), dlq: true},
+		{name: "stopped while retrying", value: "uid-1", errs: []error{transient}, cancel: true, wantErr: errUnprocessed, attempts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &scriptedService{errs: tt.errs}
+			sink := &recordingSink{}
+			c := newTestConsumer(svc, sink)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancel {
+				cancel()
+			}
+
+			err := c.handleMessage(ctx, kafka.Message{Offset: 7, Value: []byte(tt.value)})
+			switch {
+			case tt.wantErr == nil:
+				assert.NoError(t, err)
+			case errors.Is(tt.wantErr, models.ErrInvalidOrder) || errors.Is(tt.wantErr, errUnprocessed) || tt.wantErr == tooLong:
+				assert.ErrorIs(t, err, tt.wantErr)
+			default:
+				assert.EqualError(t, err, tt.wantErr.Error())
+			}
+			assert.Equal(t, tt.attempts, svc.attempts)
+			if tt.dlq {
+				assert.Equal(t, []int64{7}, sink.offsets)
+			} else {
+				assert.Empty(t, sink.offsets)
+			}
+		})
+	}
+}
+
+// TestConsumer_HandleBatch проверяет, что заказы откатившейся пачки
+// сохраняются по одному, а невалидные уходят в DLQ
+func TestConsumer_HandleBatch(t *testing.T) {
+	svc := &scriptedService{
+		batchErr: []error{nil, fmt.Errorf("%w: deadlock detected", models.ErrBatchFailed), fmt.Errorf("%w: phone", models.ErrInvalidOrder)},
+		errs:     []error{errors.New("connection refused")},
+	}
+	sink := &recordingSink{}
+	c := newTestConsumer(svc, sink)
+
+	batch := []kafka.Message{
+		{Offset: 1, Value: []byte("uid-1")},
+		{Offset: 2, Value: []byte("uid-2")},
+		{Offset: 3, Value: []byte("uid-3")},
+		{Offset: 4},
+	}
+	errs := c.handleBatch(context.Background(), batch)
+
+	require.Len(t, errs, len(batch))
+	assert.NoError(t, errs[0])
+	assert.NoError(t, errs[1])
+	assert.ErrorIs(t, errs[2], models.ErrInvalidOrder)
+	assert.Error(t, errs[3])
+	assert.Equal(t, 2, svc.attempts, "uid-2 сохраняется по одному: ошибка, затем успешный повтор")
+	assert.ElementsMatch(t, []int64{3, 4}, sink.offsets)
+}
+
+// flakyCodec отвечает ErrSchemaUnavailable первые fails раз
+type flakyCodec struct {
+	fails int
+	calls int
+}
+
+func (c *flakyCodec) Decode(ctx context.Context, m kafka.Message) (*models.Order, error) {
+	c.calls++
+	if c.calls <= c.fails {
+		return nil, fmt.Errorf("%w: resolve schema 1: status 503", ErrSchemaUnavailable)
+	}
+	return uidCodec{}.Decode(ctx, m)
+}
+
+// TestConsumer_SchemaRegistryUnavailable проверяет, что недоступность реестра
+// схем не отправляет сообщение в DLQ: декодирование повторяется, а при
+// остановке смещение не коммитится
+func TestConsumer_SchemaRegistryUnavailable(t *testing.T) {
+	t.Run("retried until registry is back", func(t *testing.T) {
+		svc := &scriptedService{}
+		sink := &recordingSink{}
+		c := newTestConsumer(svc, sink)
+		codec := &flakyCodec{fails: 2}
+		c.codec = codec
+
+		require.NoError(t, c.handleMessage(context.Background(), kafka.Message{Offset: 7, Value: []byte("uid-1")}))
+		assert.Equal(t, 3, codec.calls)
+		assert.Equal(t, 1, svc.attempts)
+		assert.Empty(t, sink.offsets)
+	})
+
+	t.Run("stopped while retrying", func(t *testing.T) {
+		svc := &scriptedService{}
+		sink := &recordingSink{}
+		c := newTestConsumer(svc, sink)
+		c.codec = &flakyCodec{fails: 100}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		errs := c.handleBatch(ctx, []kafka.Message{{Offset: 1, Value: []byte("uid-1")}, {Offset: 2, Value: []byte("uid-2")}})
+		for _, err := range errs {
+			assert.ErrorIs(t, err, errUnprocessed)
+		}
+		assert.Zero(t, svc.attempts)
+		assert.Empty(t, sink.offsets)
+	})
+}
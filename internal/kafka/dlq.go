@@ -0,0 +1,179 @@
+package kafka
+
+import (
+	"L0-wb/config"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// DeadLetter - сообщение из DLQ с разобранными заголовками причины
+type DeadLetter struct {
+	Partition         int
+	Offset            int64
+	Key               string
+	Value             []byte
+	Headers           []kafka.Header
+	Error             string
+	OriginalTopic     string
+	OriginalPartition int
+	OriginalOffset    int64
+	FailedAt          time.Time
+}
+
+func parseDeadLetter(m kafka.Message) DeadLetter {
+	dl := DeadLetter{
+		Partition:         m.Partition,
+		Offset:            m.Offset,
+		Key:               string(m.Key),
+		Value:             m.Value,
+		Headers:           m.Headers,
+		OriginalPartition: -1,
+		OriginalOffset:    -1,
+	}
+	for _, h := range m.Headers {
+		v := string(h.Value)
+		switch h.Key {
+		case HeaderError:
+			dl.Error = v
+		case HeaderOriginalTopic:
+			dl.OriginalTopic = v
+		case HeaderOriginalPartition:
+			if p, err := strconv.Atoi(v); err == nil {
+				dl.OriginalPartition = p
+			}
+		case HeaderOriginalOffset:
+			if o, err := strconv.ParseInt(v, 10, 64); err == nil {
+				dl.OriginalOffset = o
+			}
+		case HeaderFailedAt:
+			dl.FailedAt, _ = time.Parse(time.RFC3339, v)
+		}
+	}
+	return dl
+}
+
+// redriveMessage восстанавливает исходное сообщение: без заголовков DLQ,
+// в исходный топик или в fallbackTopic, если исходный неизвестен
+func (d DeadLetter) redriveMessage(fallbackTopic string) kafka.Message {
+	topic := d.OriginalTopic
+	if topic == "" {
+		topic = fallbackTopic
+	}
+	headers := make([]kafka.Header, 0, len(d.Headers))
+	for _, h := range d.Headers {
+		switch h.Key {
+		case HeaderError, HeaderOriginalTopic, HeaderOriginalPartition, HeaderOriginalOffset, HeaderFailedAt:
+			continue
+		}
+		headers = append(headers, h)
+	}
+	return kafka.Message{
+		Topic:   topic,
+		Key:     []byte(d.Key),
+		Value:   d.Value,
+		Headers: headers,
+	}
+}
+
+// DLQ читает DLQ-топик и возвращает сообщения из него в исходный топик
+type DLQ struct {
+	admin   *Admin
+	brokers []string
+	topic   string
+	target  string
+	writer  *kafka.Writer
+}
+
+func NewDLQ(cfg config.Config) *DLQ {
+	brokerAddr := fmt.Sprintf("%s:%d", cfg.Kafka.Host, cfg.Kafka.Port)
+	dlqCfg := cfg
+	dlqCfg.Kafka.Topic = cfg.Kafka.DLQTopic
+
+	return &DLQ{
+		admin:   NewAdmin(dlqCfg),
+		brokers: []string{brokerAddr},
+		topic:   cfg.Kafka.DLQTopic,
+		target:  cfg.Kafka.Topic,
+		// топик задаётся в каждом сообщении
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(brokerAddr),
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+		},
+	}
+}
+
+// List возвращает до limit сообщений DLQ начиная с from (0 - без ограничения),
+// упорядоченных по партиции и смещению
+func (d *DLQ) List(ctx context.Context, from OffsetSpec, limit int) ([]DeadLetter, error) {
+	starts, err := d.admin.ResolveOffsets(ctx, nil, from)
+	if err != nil {
+		return nil, fmt.Errorf("resolve start offsets: %w", err)
+	}
+	ends, err := d.admin.ResolveOffsets(ctx, nil, OffsetSpec{Latest: true})
+	if err != nil {
+		return nil, fmt.Errorf("resolve end offsets: %w", err)
+	}
+
+	partitions := make([]int, 0, len(starts))
+	for p := range starts {
+		partitions = append(partitions, p)
+	}
+	sort.Ints(partitions)
+
+	var letters []DeadLetter
+	for _, p := range partitions {
+		err := readRange(ctx, d.brokers, d.topic, p, starts[p], ends[p], func(m kafka.Message) error {
+			if limit > 0 && len(letters) >= limit {
+				return errLimitReached
+			}
+			letters = append(letters, parseDeadLetter(m))
+			return nil
+		})
+		if errors.Is(err, errLimitReached) {
+			break
+		}
+		if err != nil {
+			return letters, fmt.Errorf("read %s partition %d: %w", d.topic, p, err)
+		}
+	}
+	return letters, nil
+}
+
+var errLimitReached = errors.New("limit reached")
+
+// Redrive отправляет сообщения обратно в исходный топик. Сами сообщения
+// из DLQ не удаляются: повторный redrive отправит их ещё раз.
+func (d *DLQ) Redrive(ctx context.Context, letters []DeadLetter) (int, error) {
+	sent := 0
+	for _, dl := range letters {
+		if err := d.writer.WriteMessages(ctx, dl.redriveMessage(d.target)); err != nil {
+			return sent, fmt.Errorf("redrive %s/%d@%d: %w", d.topic, dl.Partition, dl.Offset, err)
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+func (d *DLQ) Close() error {
+	return d.writer.Close()
+}
+
+// MatchDeadLetter проверяет, подходит ли сообщение под фильтр redrive:
+// подстрока в тексте ошибки и/или список позиций "partition:offset"
+func MatchDeadLetter(dl DeadLetter, errorSubstr string, positions map[string]bool) bool {
+	if errorSubstr != "" && !strings.Contains(dl.Error, errorSubstr) {
+		return false
+	}
+	if len(positions) > 0 && !positions[fmt.Sprintf("%d:%d", dl.Partition, dl.Offset)] {
+		return false
+	}
+	return true
+}
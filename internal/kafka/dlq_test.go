@@ -0,0 +1,54 @@
+package kafka
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeadLetter_Roundtrip(t *testing.T) {
+	original := kafka.Message{
+		Topic:     "wb-orders",
+		Partition: 2,
+		Offset:    41,
+		Key:       []byte("uid-1"),
+		Value:     []byte(`{"order_uid":`),
+		Headers:   []kafka.Header{{Key: SchemaVersionHeader, Value: []byte("1")}},
+	}
+	dlqMsg := kafka.Message{
+		Partition: 0,
+		Offset:    7,
+		Key:       original.Key,
+		Value:     original.Value,
+		Headers:   failureHeaders(original, errors.New("malformed message")),
+	}
+
+	dl := parseDeadLetter(dlqMsg)
+	assert.Equal(t, 7, int(dl.Offset))
+	assert.Equal(t, "malformed message", dl.Error)
+	assert.Equal(t, "wb-orders", dl.OriginalTopic)
+	assert.Equal(t, 2, dl.OriginalPartition)
+	assert.Equal(t, int64(41), dl.OriginalOffset)
+	assert.WithinDuration(t, time.Now(), dl.FailedAt, time.Minute)
+
+	msg := dl.redriveMessage("fallback")
+	assert.Equal(t, "wb-orders", msg.Topic)
+	assert.Equal(t, original.Key, msg.Key)
+	assert.Equal(t, original.Value, msg.Value)
+	assert.Equal(t, original.Headers, msg.Headers, "заголовки DLQ снимаются")
+
+	dl.OriginalTopic = ""
+	assert.Equal(t, "fallback", dl.redriveMessage("fallback").Topic)
+}
+
+func TestMatchDeadLetter(t *testing.T) {
+	dl := DeadLetter{Partition: 1, Offset: 10, Error: "invalid order: phone"}
+
+	assert.True(t, MatchDeadLetter(dl, "", nil))
+	assert.True(t, MatchDeadLetter(dl, "phone", map[string]bool{"1:10": true}))
+	assert.False(t, MatchDeadLetter(dl, "email", nil))
+	assert.False(t, MatchDeadLetter(dl, "", map[string]bool{"1:11": true}))
+}
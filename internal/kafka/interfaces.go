@@ -3,10 +3,13 @@ package kafka
 import (
 	"L0-wb/internal/models"
 	"context"
+
+	"github.com/segmentio/kafka-go"
 )
 
 type ProducerInterface interface {
 	SendOrders(ctx context.Context, order *models.Order) error
+	SendMessage(ctx context.Context, msg kafka.Message) error
 	RunProducer(ctx context.Context) error
 	Close() error
 	GenerateTestOrder() *models.Order
@@ -20,3 +23,10 @@ type ConsumerInterface interface {
 type MessageProcessor interface {
 	SaveOrder(ctx context.Context, order *models.Order) error
 }
+
+// BatchProcessor - опциональное расширение MessageProcessor для сохранения пачками.
+// Заказы откатившейся пачки получают models.ErrBatchFailed, и консьюмер
+// сохраняет их по одному через SaveOrder.
+type BatchProcessor interface {
+	SaveOrders(ctx context.Context, orders []*models.Order) []error
+}
@@ -0,0 +1,180 @@
+package kafka
+
+import (
+	"context"
+	"hash/fnv"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// offsetTracker следит за сообщениями в обработке и отдаёт смещение для коммита
+// только когда обработаны все предыдущие сообщения партиции
+type offsetTracker struct {
+	mu         sync.Mutex
+	partitions map[int]*partitionOffsets
+}
+
+type partitionOffsets struct {
+	pending []kafka.Message // в порядке чтения из партиции
+	done    map[int64]bool
+}
+
+func newOffsetTracker() *offsetTracker {
+	return &offsetTracker{partitions: make(map[int]*partitionOffsets)}
+}
+
+// Track регистрирует прочитанное сообщение. Вызывается в порядке чтения.
+func (t *offsetTracker) Track(msg kafka.Message) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p, ok := t.partitions[msg.Partition]
+	if !ok {
+		p = &partitionOffsets{done: make(map[int64]bool)}
+		t.partitions[msg.Partition] = p
+	}
+	p.pending = append(p.pending, msg)
+}
+
+// Done отмечает сообщение обработанным. Возвращает последнее сообщение
+// непрерывного обработанного префикса партиции, если он сдвинулся.
+func (t *offsetTracker) Done(msg kafka.Message) (kafka.Message, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p, ok := t.partitions[msg.Partition]
+	if !ok {
+		return kafka.Message{}, false
+	}
+	p.done[msg.Offset] = true
+
+	var commit kafka.Message
+	advanced := false
+	for len(p.pending) > 0 && p.done[p.pending[0].Offset] {
+		commit = p.pending[0]
+		delete(p.done, commit.Offset)
+		p.pending = p.pending[1:]
+		advanced = true
+	}
+	return commit, advanced
+}
+
+// InFlight возвращает количество отслеживаемых, но ещё не закоммиченных сообщений
+func (t *offsetTracker) InFlight() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	n := 0
+	for _, p := range t.partitions {
+		n += len(p.pending)
+	}
+	return n
+}
+
+// workerPool раскладывает сообщения по воркерам по хэшу ключа:
+// сообщения одного order_uid обрабатываются строго по порядку,
+// разные ключи - параллельно. Каждый воркер копит микро-пачку
+// до batchSize сообщений или до истечения batchInterval.
+type workerPool struct {
+	queues        []chan kafka.Message
+	batchSize     int
+	batchInterval time.Duration
+	wg            sync.WaitGroup
+}
+
+func newWorkerPool(workers, queueSize, batchSize int, batchInterval time.Duration, handle func([]kafka.Message)) *workerPool {
+	if workers < 1 {
+		workers = 1
+	}
+	if batchSize < 1 {
+		batchSize = 1
+	}
+	p := &workerPool{
+		queues:        make([]chan kafka.Message, workers),
+		batchSize:     batchSize,
+		batchInterval: batchInterval,
+	}
+	for i := range p.queues {
+		queue := make(chan kafka.Message, queueSize)
+		p.queues[i] = queue
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			p.run(queue, handle)
+		}()
+	}
+	return p
+}
+
+func (p *workerPool) run(queue <-chan kafka.Message, handle func([]kafka.Message)) {
+	batch := make([]kafka.Message, 0, p.batchSize)
+	var timer *time.Timer
+	var timeout <-chan time.Time
+
+	flush := func() {
+		if timer != nil {
+			timer.Stop()
+			timer, timeout = nil, nil
+		}
+		if len(batch) == 0 {
+			return
+		}
+		handle(batch)
+		batch = make([]kafka.Message, 0, p.batchSize)
+	}
+
+	for {
+		select {
+		case msg, ok := <-queue:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, msg)
+			if len(batch) >= p.batchSize {
+				flush()
+				continue
+			}
+			if timer == nil {
+				timer = time.NewTimer(p.batchInterval)
+				timeout = timer.C
+			}
+		case <-timeout:
+			timer, timeout = nil, nil
+			flush()
+		}
+	}
+}
+
+// Dispatch ставит сообщение в очередь воркера, отвечающего за его ключ
+func (p *workerPool) Dispatch(ctx context.Context, msg kafka.Message) error {
+	queue := p.queues[p.shard(msg)]
+	select {
+	case queue <- msg:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Drain закрывает очереди и ждёт, пока воркеры обработают уже принятые сообщения
+func (p *workerPool) Drain() {
+	for _, queue := range p.queues {
+		close(queue)
+	}
+	p.wg.Wait()
+}
+
+func (p *workerPool) shard(msg kafka.Message) int {
+	key := msg.Key
+	if len(key) == 0 {
+		// без ключа сохраняем хотя бы порядок внутри партиции
+		key = []byte("partition-" + strconv.Itoa(msg.Partition))
+	}
+	h := fnv.New32a()
+	_, _ = h.Write(key)
+	return int(h.Sum32() % uint32(len(p.queues)))
+}
@@ -0,0 +1,112 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOffsetTracker_CommitsInOrder(t *testing.T) {
+	tracker := newOffsetTracker()
+	for offset := int64(0); offset < 3; offset++ {
+		tracker.Track(kafka.Message{Partition: 0, Offset: offset})
+	}
+	tracker.Track(kafka.Message{Partition: 1, Offset: 10})
+	assert.Equal(t, 4, tracker.InFlight())
+
+	// смещение 2 готово раньше 0 и 1 - коммитить нельзя
+	_, ok := tracker.Done(kafka.Message{Partition: 0, Offset: 2})
+	assert.False(t, ok)
+
+	commit, ok := tracker.Done(kafka.Message{Partition: 0, Offset: 0})
+	require.True(t, ok)
+	assert.Equal(t, int64(0), commit.Offset)
+
+	// после 1 префикс сдвигается сразу до 2
+	commit, ok = tracker.Done(kafka.Message{Partition: 0, Offset: 1})
+	require.True(t, ok)
+	assert.Equal(t, int64(2), commit.Offset)
+
+	// партиции независимы
+	commit, ok = tracker.Done(kafka.Message{Partition: 1, Offset: 10})
+	require.True(t, ok)
+	assert.Equal(t, 1, commit.Partition)
+	assert.Equal(t, int64(10), commit.Offset)
+
+	assert.Equal(t, 0, tracker.InFlight())
+}
+
+func TestWorkerPool_PreservesOrderPerKey(t *testing.T) {
+	var mu sync.Mutex
+	seen := make(map[string][]int64)
+
+	pool := newWorkerPool(4, 8, 1, time.Millisecond, func(batch []kafka.Message) {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, m := range batch {
+			seen[string(m.Key)] = append(seen[string(m.Key)], m.Offset)
+		}
+	})
+
+	ctx := context.Background()
+	for offset := int64(0); offset < 100; offset++ {
+		key := fmt.Sprintf("order-%d", offset%5)
+		require.NoError(t, pool.Dispatch(ctx, kafka.Message{Key: []byte(key), Offset: offset}))
+	}
+	pool.Drain()
+
+	require.Len(t, seen, 5)
+	for key, offsets := range seen {
+		assert.Len(t, offsets, 20, key)
+		for i := 1; i < len(offsets); i++ {
+			assert.Less(t, offsets[i-1], offsets[i], key)
+		}
+	}
+}
+
+func TestWorkerPool_DispatchCanceled(t *testing.T) {
+	block := make(chan struct{})
+	pool := newWorkerPool(1, 0, 1, time.Millisecond, func([]kafka.Message) { <-block })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, pool.Dispatch(ctx, kafka.Message{Offset: 1}))
+
+	cancel()
+	err := pool.Dispatch(ctx, kafka.Message{Offset: 2})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	close(block)
+	pool.Drain()
+}
+
+func TestWorkerPool_Batches(t *testing.T) {
+	var mu sync.Mutex
+	var sizes []int
+
+	pool := newWorkerPool(1, 16, 4, 20*time.Millisecond, func(batch []kafka.Message) {
+		mu.Lock()
+		defer mu.Unlock()
+		sizes = append(sizes, len(batch))
+	})
+
+	ctx := context.Background()
+	for offset := int64(0); offset < 9; offset++ {
+		require.NoError(t, pool.Dispatch(ctx, kafka.Message{Key: []byte("k"), Offset: offset}))
+	}
+
+	// неполная пачка уходит по таймеру
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(sizes) == 3
+	}, time.Second, 5*time.Millisecond)
+
+	pool.Drain()
+	assert.Equal(t, []int{4, 4, 1}, sizes)
+}
@@ -29,6 +29,8 @@ func NewProducer(cfg config.Config) ProducerInterface {
 		Topic:    cfg.Kafka.Topic,
 		Balancer: &kafka.Hash{},
 		Async:    false,
+		// синхронная запись не ждёт наполнения пачки дольше 10ms
+		BatchTimeout: 10 * time.Millisecond,
 		ErrorLogger: kafka.LoggerFunc(func(msg string, args ...interface{}) {
 			logrus.Errorf(msg, args...)
 		}),
@@ -68,9 +70,30 @@ func (p *Producer) SendOrders(ctx context.Context, order *models.Order) error {
 	}
 
 	msg := kafka.Message{
-		Key:   []byte(order.OrderUID),
-		Value: value,
-		Time:  time.Now(),
+		Key:     []byte(order.OrderUID),
+		Value:   value,
+		Headers: []kafka.Header{CurrentVersionHeader()},
+	}
+	if err := p.SendMessage(ctx, msg); err != nil {
+		return err
+	}
+
+	logrus.WithField("order_uid", order.OrderUID).Info("message sent to kafka")
+	return nil
+}
+
+// SendMessage отправляет уже сериализованное сообщение как есть: заголовки
+// не дополняются, версию схемы при необходимости задаёт вызывающий
+func (p *Producer) SendMessage(ctx context.Context, msg kafka.Message) error {
+	if p.writer == nil {
+		return fmt.Errorf("writer is nil")
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if msg.Time.IsZero() {
+		msg.Time = time.Now()
 	}
 
 	ctxTimeout, cancel := context.WithTimeout(ctx, p.timeout)
@@ -80,10 +103,9 @@ func (p *Producer) SendOrders(ctx context.Context, order *models.Order) error {
 		logrus.WithError(err).Error("send message error")
 		return err
 	}
-
-	logrus.WithField("order_uid", order.OrderUID).Info("message sent to kafka")
 	return nil
 }
+
 func (p *Producer) RunProducer(ctx context.Context) error {
 	for {
 		select {
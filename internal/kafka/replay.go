@@ -0,0 +1,161 @@
+package kafka
+
+import (
+	"L0-wb/config"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+)
+
+// ReplayStats - итог повторной обработки диапазона
+type ReplayStats struct {
+	Messages int
+	Saved    int
+	Failed   int
+}
+
+// Replayer перечитывает диапазон топика без consumer group, не трогая
+// смещения основного сервиса, и прогоняет сообщения через тот же путь
+// декодирования и сохранения, что и Consumer
+type Replayer struct {
+	admin    *Admin
+	brokers  []string
+	topic    string
+	consumer *Consumer
+}
+
+func NewReplayer(cfg config.Config, service MessageProcessor) *Replayer {
+	brokerAddr := fmt.Sprintf("%s:%d", cfg.Kafka.Host, cfg.Kafka.Port)
+	return &Replayer{
+		admin:   NewAdmin(cfg),
+		brokers: []string{brokerAddr},
+		topic:   cfg.Kafka.Topic,
+		consumer: &Consumer{
+			topic:     cfg.Kafka.Topic,
+			timeout:   5 * time.Second,
+			retryBase: retryBaseDelay,
+			retryMax:  retryMaxDelay,
+			maxBytes:  cfg.Kafka.MaxMessageBytes,
+			service:   service,
+			codec:     NewCodec(cfg),
+			sink:      NewFailureSink(cfg),
+		},
+	}
+}
+
+// Replay обрабатывает сообщения в диапазоне [from, to) каждой партиции.
+// Без списка партиций берутся партиции из смещений по партициям, иначе все.
+func (r *Replayer) Replay(ctx context.Context, partitions []int, from, to OffsetSpec) (ReplayStats, error) {
+	var stats ReplayStats
+
+	if len(partitions) == 0 {
+		if from.Offsets != nil {
+			partitions = from.Partitions()
+		} else if to.Offsets != nil {
+			partitions = to.Partitions()
+		}
+	}
+
+	starts, err := r.admin.ResolveOffsets(ctx, partitions, from)
+	if err != nil {
+		return stats, fmt.Errorf("resolve start offsets: %w", err)
+	}
+	ends, err := r.admin.ResolveOffsets(ctx, partitions, to)
+	if err != nil {
+		return stats, fmt.Errorf("resolve end offsets: %w", err)
+	}
+
+	for p, start := range starts {
+		end := ends[p]
+		if start >= end {
+			continue
+		}
+		logrus.WithFields(logrus.Fields{
+			"partition": p,
+			"from":      start,
+			"to":        end,
+		}).Info("replaying partition")
+
+		if err := r.replayPartition(ctx, p, start, end, &stats); err != nil {
+			return stats, fmt.Errorf("replay partition %d: %w", p, err)
+		}
+	}
+	return stats, nil
+}
+
+func (r *Replayer) replayPartition(ctx context.Context, partition int, start, end int64, stats *ReplayStats) error {
+	return readRange(ctx, r.brokers, r.topic, partition, start, end, func(m kafka.Message) error {
+		stats.Messages++
+		if err := r.consumer.handleMessage(ctx, m); err != nil {
+			stats.Failed++
+		} else {
+			stats.Saved++
+		}
+		return nil
+	})
+}
+
+// readIdleTimeout - сколько ждать следующего сообщения диапазона. Все сообщения
+// до end уже записаны в момент вызова, поэтому тишина означает, что остаток
+// диапазона занят служебными записями (маркеры транзакций, компакция).
+const readIdleTimeout = 10 * time.Second
+
+// readRange читает сообщения партиции в диапазоне [start, end) без consumer group.
+// Чтение заканчивается на end, на первом сообщении за ним или после
+// readIdleTimeout без сообщений; оставшийся лаг партиции пишется в лог.
+func readRange(ctx context.Context, brokers []string, topic string, partition int, start, end int64, fn func(kafka.Message) error) error {
+	if start >= end {
+		return nil
+	}
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:   brokers,
+		Topic:     topic,
+		Partition: partition,
+		MinBytes:  1,
+		MaxBytes:  10e6,
+	})
+	defer reader.Close()
+
+	if err := reader.SetOffset(start); err != nil {
+		return err
+	}
+
+	for {
+		readCtx, cancel := context.WithTimeout(ctx, readIdleTimeout)
+		m, err := reader.ReadMessage(readCtx)
+		cancel()
+		if err != nil {
+			if ctx.Err() != nil || !errors.Is(err, context.DeadlineExceeded) {
+				return err
+			}
+			lag, lagErr := reader.ReadLag(ctx)
+			if lagErr != nil {
+				return fmt.Errorf("no messages for %s, lag unknown: %w", readIdleTimeout, lagErr)
+			}
+			logrus.WithFields(logrus.Fields{
+				"partition": partition,
+				"offset":    reader.Offset(),
+				"end":       end,
+				"lag":       lag,
+			}).Warn("no more messages before range end")
+			return nil
+		}
+		if m.Offset >= end {
+			return nil
+		}
+		if err := fn(m); err != nil {
+			return err
+		}
+		if m.Offset+1 >= end {
+			return nil
+		}
+	}
+}
+
+func (r *Replayer) Close() error {
+	return r.consumer.Close()
+}
@@ -0,0 +1,111 @@
+package kafka
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	// SchemaVersionHeader - заголовок Kafka-сообщения с версией схемы заказа
+	SchemaVersionHeader = "schema_version"
+	// LegacySchemaVersion - версия сообщений без заголовка и без конверта
+	LegacySchemaVersion = 1
+	// CurrentSchemaVersion - версия, которой соответствует models.Order
+	CurrentSchemaVersion = 1
+)
+
+var (
+	ErrUnsupportedSchemaVersion = errors.New("unsupported schema version")
+	ErrMalformedMessage         = errors.New("malformed message")
+	ErrUnsupportedSchemaType    = errors.New("unsupported schema type")
+	ErrMessageTooLarge          = errors.New("message too large")
+	// ErrSchemaUnavailable - реестр схем не ответил (таймаут, 5xx, сеть): ошибка
+	// временная, сообщение не битое и декодируется повторно
+	ErrSchemaUnavailable = errors.New("schema registry unavailable")
+)
+
+// CurrentVersionHeader - заголовок версии схемы для заказов, сериализованных из models.Order
+func CurrentVersionHeader() kafka.Header {
+	return kafka.Header{Key: SchemaVersionHeader, Value: []byte(strconv.Itoa(CurrentSchemaVersion))}
+}
+
+// Envelope - конверт, в который продюсер может завернуть заказ вместо заголовка
+type Envelope struct {
+	SchemaVersion int             `json:"schema_version"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// Upcaster переводит payload версии N в версию N+1.
+// Payload передаётся в виде распарсенного JSON-объекта и изменяется на месте.
+type Upcaster func(payload map[string]interface{}) error
+
+// UpcasterRegistry хранит цепочку апкастеров от старых версий схемы к текущей
+type UpcasterRegistry struct {
+	current   int
+	upcasters map[int]Upcaster
+}
+
+func NewUpcasterRegistry(current int) *UpcasterRegistry {
+	return &UpcasterRegistry{
+		current:   current,
+		upcasters: make(map[int]Upcaster),
+	}
+}
+
+// DefaultUpcasters возвращает реестр для текущей версии models.Order.
+// Новые апкастеры регистрируются здесь при каждом изменении схемы.
+func DefaultUpcasters() *UpcasterRegistry {
+	return NewUpcasterRegistry(CurrentSchemaVersion)
+}
+
+// Register добавляет апкастер из версии from в версию from+1
+func (r *UpcasterRegistry) Register(from int, up Upcaster) {
+	r.upcasters[from] = up
+}
+
+func (r *UpcasterRegistry) Current() int {
+	return r.current
+}
+
+// Versions возвращает версии, из которых зарегистрирован апкаст
+func (r *UpcasterRegistry) Versions() []int {
+	versions := make([]int, 0, len(r.upcasters))
+	for v := range r.upcasters {
+		versions = append(versions, v)
+	}
+	sort.Ints(versions)
+	return versions
+}
+
+// Upcast приводит payload версии version к текущей версии схемы.
+// Версии новее текущей и версии без цепочки апкастеров отклоняются.
+func (r *UpcasterRegistry) Upcast(version int, payload []byte) ([]byte, error) {
+	if version < 1 || version > r.current {
+		return nil, fmt.Errorf("%w: %d (current %d)", ErrUnsupportedSchemaVersion, version, r.current)
+	}
+	if version == r.current {
+		return payload, nil
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(payload, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedMessage, err)
+	}
+
+	for v := version; v < r.current; v++ {
+		up, ok := r.upcasters[v]
+		if !ok {
+			return nil, fmt.Errorf("%w: no upcaster from version %d", ErrUnsupportedSchemaVersion, v)
+		}
+		if err := up(doc); err != nil {
+			return nil, fmt.Errorf("upcast from version %d: %w", v, err)
+		}
+	}
+
+	return json.Marshal(doc)
+}
@@ -0,0 +1,97 @@
+package kafka
+
+import (
+	"L0-wb/config"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+)
+
+// Заголовки, которыми помечаются сообщения в DLQ
+const (
+	HeaderError             = "x-error"
+	HeaderOriginalTopic     = "x-original-topic"
+	HeaderOriginalPartition = "x-original-partition"
+	HeaderOriginalOffset    = "x-original-offset"
+	HeaderFailedAt          = "x-failed-at"
+)
+
+// FailureSink принимает сообщения, которые не удалось обработать
+type FailureSink interface {
+	Send(ctx context.Context, msg kafka.Message, reason error) error
+	Close() error
+}
+
+// KafkaFailureSink перекладывает сообщения в DLQ-топик без изменений
+// и добавляет заголовки с причиной и исходной позицией
+type KafkaFailureSink struct {
+	writer  *kafka.Writer
+	topic   string
+	timeout time.Duration
+}
+
+func NewFailureSink(cfg config.Config) FailureSink {
+	brokerAddr := fmt.Sprintf("%s:%d", cfg.Kafka.Host, cfg.Kafka.Port)
+	writer := &kafka.Writer{
+		Addr:                   kafka.TCP(brokerAddr),
+		Topic:                  cfg.Kafka.DLQTopic,
+		Balancer:               &kafka.Hash{},
+		RequiredAcks:           kafka.RequireAll,
+		AllowAutoTopicCreation: true,
+	}
+	logrus.WithField("topic", cfg.Kafka.DLQTopic).Info("Kafka failure sink initialized")
+
+	return &KafkaFailureSink{
+		writer:  writer,
+		topic:   cfg.Kafka.DLQTopic,
+		timeout: 5 * time.Second,
+	}
+}
+
+func (s *KafkaFailureSink) Send(ctx context.Context, msg kafka.Message, reason error) error {
+	if s.writer == nil {
+		return fmt.Errorf("writer is nil")
+	}
+
+	dlqMsg := kafka.Message{
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: failureHeaders(msg, reason),
+		Time:    time.Now(),
+	}
+
+	ctxTimeout, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	if err := s.writer.WriteMessages(ctxTimeout, dlqMsg); err != nil {
+		return fmt.Errorf("write to %s: %w", s.topic, err)
+	}
+	return nil
+}
+
+func (s *KafkaFailureSink) Close() error {
+	if s.writer != nil {
+		err := s.writer.Close()
+		s.writer = nil
+		return err
+	}
+	return nil
+}
+
+// failureHeaders сохраняет исходные заголовки и дописывает к ним причину ошибки
+func failureHeaders(msg kafka.Message, reason error) []kafka.Header {
+	headers := make([]kafka.Header, 0, len(msg.Headers)+5)
+	headers = append(headers, msg.Headers...)
+	headers = append(headers,
+		kafka.Header{Key: HeaderError, Value: []byte(reason.Error())},
+		kafka.Header{Key: HeaderOriginalTopic, Value: []byte(msg.Topic)},
+		kafka.Header{Key: HeaderOriginalPartition, Value: []byte(strconv.Itoa(msg.Partition))},
+		kafka.Header{Key: HeaderOriginalOffset, Value: []byte(strconv.FormatInt(msg.Offset, 10))},
+		kafka.Header{Key: HeaderFailedAt, Value: []byte(time.Now().UTC().Format(time.RFC3339))},
+	)
+	return headers
+}
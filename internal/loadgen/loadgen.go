@@ -0,0 +1,180 @@
+package loadgen
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Config - параметры нагрузки
+type Config struct {
+	Rate     float64       // сообщений в секунду на всех writers, 0 - без ограничения
+	Count    int           // всего сообщений, 0 - без ограничения
+	Duration time.Duration // длительность, 0 - без ограничения
+	Writers  int           // количество параллельных отправителей
+}
+
+// SendFunc отправляет одно сообщение: заказ или уже сериализованные данные
+type SendFunc[T any] func(ctx context.Context, item T) error
+
+// SourceFunc создаёт источник сообщений для writer-а с номером writer.
+// Источник возвращает false, когда сообщения закончились.
+type SourceFunc[T any] func(writer int) func() (T, bool)
+
+// Summary - итог прогона
+type Summary struct {
+	Sent    int
+	Failed  int
+	Elapsed time.Duration
+	P50     time.Duration
+	P99     time.Duration
+	Max     time.Duration
+}
+
+func (s Summary) Throughput() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Sent) / s.Elapsed.Seconds()
+}
+
+func (s Summary) String() string {
+	return fmt.Sprintf("sent=%d failed=%d elapsed=%s rate=%.1f msg/s latency p50=%s p99=%s max=%s",
+		s.Sent, s.Failed, s.Elapsed.Round(time.Millisecond), s.Throughput(),
+		s.P50.Round(time.Microsecond), s.P99.Round(time.Microsecond), s.Max.Round(time.Microsecond))
+}
+
+// Run отправляет сообщения до исчерпания Count, Duration, источников или отмены ctx
+func Run[T any](ctx context.Context, cfg Config, source SourceFunc[T], send SendFunc[T]) Summary {
+	if cfg.Writers < 1 {
+		cfg.Writers = 1
+	}
+	if cfg.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Duration)
+		defer cancel()
+	}
+
+	limiter := newLimiter(ctx, cfg.Rate)
+	var issued int64
+	var sent, failed int64
+	var mu sync.Mutex
+	latencies := make([]time.Duration, 0, 1024)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for w := 0; w < cfg.Writers; w++ {
+		next := source(w)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				if cfg.Count > 0 && atomic.AddInt64(&issued, 1) > int64(cfg.Count) {
+					return
+				}
+				if !limiter.Wait(ctx) {
+					return
+				}
+				item, ok := next()
+				if !ok {
+					return
+				}
+
+				began := time.Now()
+				err := send(ctx, item)
+				latency := time.Since(began)
+				if err != nil {
+					if ctx.Err() != nil {
+						return
+					}
+					atomic.AddInt64(&failed, 1)
+					continue
+				}
+				atomic.AddInt64(&sent, 1)
+				mu.Lock()
+				latencies = append(latencies, latency)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	summary := Summary{
+		Sent:    int(sent),
+		Failed:  int(failed),
+		Elapsed: time.Since(start),
+	}
+	summary.P50, summary.P99, summary.Max = percentiles(latencies)
+	return summary
+}
+
+func percentiles(latencies []time.Duration) (p50, p99, max time.Duration) {
+	if len(latencies) == 0 {
+		return 0, 0, 0
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	at := func(q float64) time.Duration {
+		idx := int(q*float64(len(latencies))+0.5) - 1
+		if idx < 0 {
+			idx = 0
+		}
+		if idx >= len(latencies) {
+			idx = len(latencies) - 1
+		}
+		return latencies[idx]
+	}
+	return at(0.50), at(0.99), latencies[len(latencies)-1]
+}
+
+// limiter выдаёт разрешения с заданной частотой на всех writers
+type limiter struct {
+	tokens <-chan time.Time
+}
+
+func newLimiter(ctx context.Context, rate float64) *limiter {
+	if rate <= 0 {
+		return &limiter{}
+	}
+	interval := time.Duration(float64(time.Second) / rate)
+	if interval <= 0 {
+		interval = time.Nanosecond
+	}
+	tokens := make(chan time.Time)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		// первое сообщение уходит сразу
+		t := time.Now()
+		for {
+			select {
+			case tokens <- t:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case t = <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return &limiter{tokens: tokens}
+}
+
+func (l *limiter) Wait(ctx context.Context) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if l.tokens == nil {
+		return true
+	}
+	select {
+	case <-l.tokens:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,84 @@
+package loadgen
+
+import (
+	"L0-wb/internal/models"
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func constSource(int) func() (*models.Order, bool) {
+	return func() (*models.Order, bool) {
+		return &models.Order{OrderUID: "uid"}, true
+	}
+}
+
+func TestRun_Count(t *testing.T) {
+	var calls int64
+	summary := Run(context.Background(), Config{Count: 25, Writers: 4}, constSource,
+		func(context.Context, *models.Order) error {
+			atomic.AddInt64(&calls, 1)
+			return nil
+		})
+
+	assert.Equal(t, int64(25), calls)
+	assert.Equal(t, 25, summary.Sent)
+	assert.Equal(t, 0, summary.Failed)
+}
+
+func TestRun_CountsFailures(t *testing.T) {
+	var calls int64
+	summary := Run(context.Background(), Config{Count: 10, Writers: 2}, constSource,
+		func(context.Context, *models.Order) error {
+			if atomic.AddInt64(&calls, 1)%2 == 0 {
+				return errors.New("send failed")
+			}
+			return nil
+		})
+
+	assert.Equal(t, 5, summary.Sent)
+	assert.Equal(t, 5, summary.Failed)
+}
+
+func TestRun_RateAndDuration(t *testing.T) {
+	summary := Run(context.Background(), Config{Rate: 100, Duration: 200 * time.Millisecond, Writers: 4}, constSource,
+		func(context.Context, *models.Order) error { return nil })
+
+	// 100 msg/s за 200ms - около 20 сообщений
+	assert.InDelta(t, 20, summary.Sent, 8)
+}
+
+func TestRun_SourceExhausted(t *testing.T) {
+	source := func(int) func() (*models.Order, bool) {
+		left := 3
+		return func() (*models.Order, bool) {
+			if left == 0 {
+				return nil, false
+			}
+			left--
+			return &models.Order{}, true
+		}
+	}
+	summary := Run(context.Background(), Config{Writers: 2}, source,
+		func(context.Context, *models.Order) error { return nil })
+
+	assert.Equal(t, 6, summary.Sent)
+}
+
+func TestPercentiles(t *testing.T) {
+	latencies := make([]time.Duration, 0, 100)
+	for i := 100; i >= 1; i-- {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+	p50, p99, max := percentiles(latencies)
+	assert.Equal(t, 50*time.Millisecond, p50)
+	assert.Equal(t, 99*time.Millisecond, p99)
+	assert.Equal(t, 100*time.Millisecond, max)
+
+	p50, p99, max = percentiles(nil)
+	assert.Zero(t, p50+p99+max)
+}
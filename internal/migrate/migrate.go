@@ -0,0 +1,295 @@
+// Package migrate применяет SQL-миграции из каталога migrations/.
+//
+// Формат файлов и таблица версий совместимы с golang-migrate:
+// <version>_<name>.up.sql / <version>_<name>.down.sql и
+// schema_migrations(version bigint, dirty boolean) с единственной строкой.
+// Миграция помечается dirty до выполнения скрипта и очищается после успеха,
+// поэтому упавшая на середине миграция видна и блокирует следующие запуски
+// до ручного исправления и Force.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// NoVersion - версия схемы, к которой не применена ни одна миграция
+const NoVersion uint64 = 0
+
+// Migration - пара up/down скриптов одной версии
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Load читает миграции из корня fsys и сортирует их по версии
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+
+	byVersion := make(map[uint64]*Migration)
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".sql") {
+			continue
+		}
+		version, name, direction, err := parseFilename(e.Name())
+		if err != nil {
+			return nil, err
+		}
+		body, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", e.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		}
+		if direction == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+func parseFilename(filename string) (uint64, string, string, error) {
+	base := strings.TrimSuffix(filename, ".sql")
+	direction := base[strings.LastIndexByte(base, '.')+1:]
+	if direction != "up" && direction != "down" {
+		return 0, "", "", fmt.Errorf("migration %s: want .up.sql or .down.sql", filename)
+	}
+	base = strings.TrimSuffix(base, "."+direction)
+
+	versionStr, name, _ := strings.Cut(base, "_")
+	version, err := strconv.ParseUint(versionStr, 10, 64)
+	if err != nil || version == NoVersion {
+		return 0, "", "", fmt.Errorf("migration %s: invalid version %q", filename, versionStr)
+	}
+	return version, name, direction, nil
+}
+
+// LockID - ключ pg_advisory_lock, под которым выполняются миграции:
+// несколько экземпляров сервиса не применяют их одновременно
+const LockID int64 = 0x4c30_7762 // "L0wb"
+
+// DirtyError - предыдущая миграция упала на середине
+type DirtyError struct {
+	Version uint64
+}
+
+func (e *DirtyError) Error() string {
+	return fmt.Sprintf("schema version %d is dirty: fix the database manually and force the version", e.Version)
+}
+
+// execQuerier - общее у *sql.DB и *sql.Conn
+type execQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Migrator применяет миграции к базе
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+func New(db *sql.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+// Version возвращает текущую версию схемы и признак незавершённой миграции
+func (m *Migrator) Version(ctx context.Context) (uint64, bool, error) {
+	if err := ensureVersionTable(ctx, m.db); err != nil {
+		return NoVersion, false, err
+	}
+	return version(ctx, m.db)
+}
+
+// Up применяет все миграции новее текущей версии
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		current, err := cleanVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			if mig.Version <= current {
+				continue
+			}
+			if err := apply(ctx, conn, mig.Up, mig.Version, mig.Version); err != nil {
+				return fmt.Errorf("migration %d_%s up: %w", mig.Version, mig.Name, err)
+			}
+			applied = append(applied, mig)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down откатывает steps последних применённых миграций (steps <= 0 - все)
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var reverted []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		current, err := cleanVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			if steps > 0 && len(reverted) >= steps {
+				break
+			}
+			mig := m.migrations[i]
+			if mig.Version > current {
+				continue
+			}
+			if mig.Down == "" {
+				return fmt.Errorf("migration %d_%s has no down script", mig.Version, mig.Name)
+			}
+			previous := NoVersion
+			if i > 0 {
+				previous = m.migrations[i-1].Version
+			}
+			if err := apply(ctx, conn, mig.Down, mig.Version, previous); err != nil {
+				return fmt.Errorf("migration %d_%s down: %w", mig.Version, mig.Name, err)
+			}
+			reverted = append(reverted, mig)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Force записывает версию без выполнения миграций и снимает признак dirty.
+// Используется после ручного исправления базы.
+func (m *Migrator) Force(ctx context.Context, v uint64) error {
+	if v != NoVersion && !m.known(v) {
+		return fmt.Errorf("unknown migration version %d", v)
+	}
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		return setVersion(ctx, conn, v, false)
+	})
+}
+
+func (m *Migrator) known(v uint64) bool {
+	for _, mig := range m.migrations {
+		if mig.Version == v {
+			return true
+		}
+	}
+	return false
+}
+
+// withLock выполняет fn на одном соединении под advisory lock
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, LockID); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer func() {
+		// блокировка сессионная: снимаем даже после отмены ctx
+		_, _ = conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, LockID)
+	}()
+
+	if err := ensureVersionTable(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+// apply помечает версию mark как dirty, выполняет скрипт и записывает версию to
+func apply(ctx context.Context, conn *sql.Conn, script string, mark, to uint64) error {
+	if err := setVersion(ctx, conn, mark, true); err != nil {
+		return err
+	}
+	// скрипт из нескольких выражений Postgres выполняет в одной неявной транзакции
+	if _, err := conn.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	return setVersion(ctx, conn, to, false)
+}
+
+func cleanVersion(ctx context.Context, q execQuerier) (uint64, error) {
+	v, dirty, err := version(ctx, q)
+	if err != nil {
+		return NoVersion, err
+	}
+	if dirty {
+		return v, &DirtyError{Version: v}
+	}
+	return v, nil
+}
+
+func version(ctx context.Context, q execQuerier) (uint64, bool, error) {
+	var v int64
+	var dirty bool
+	err := q.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&v, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return NoVersion, false, nil
+	}
+	if err != nil {
+		return NoVersion, false, fmt.Errorf("read schema version: %w", err)
+	}
+	return uint64(v), dirty, nil
+}
+
+func setVersion(ctx context.Context, conn *sql.Conn, v uint64, dirty bool) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations`); err != nil {
+		return fmt.Errorf("reset schema version: %w", err)
+	}
+	if v != NoVersion {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, dirty) VALUES ($1, $2)`, int64(v), dirty); err != nil {
+			return fmt.Errorf("set schema version: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+func ensureVersionTable(ctx context.Context, q execQuerier) error {
+	_, err := q.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL)`)
+	if err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package migrate
+
+import (
+	"L0-wb/migrations"
+	"context"
+	"regexp"
+	"testing"
+	"testing/fstest"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testFS = fstest.MapFS{
+	"1_first.up.sql":    {Data: []byte("CREATE TABLE a (id int);")},
+	"1_first.down.sql":  {Data: []byte("DROP TABLE a;")},
+	"2_second.up.sql":   {Data: []byte("CREATE TABLE b (id int);")},
+	"2_second.down.sql": {Data: []byte("DROP TABLE b;")},
+	"README.md":         {Data: []byte("not a migration")},
+}
+
+func TestLoad(t *testing.T) {
+	migrations, err := Load(testFS)
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+	assert.Equal(t, Migration{Version: 1, Name: "first", Up: "CREATE TABLE a (id int);", Down: "DROP TABLE a;"}, migrations[0])
+	assert.Equal(t, uint64(2), migrations[1].Version)
+
+	_, err = Load(fstest.MapFS{"x_bad.up.sql": {}})
+	assert.Error(t, err)
+	_, err = Load(fstest.MapFS{"3_only_down.down.sql": {Data: []byte("x")}})
+	assert.Error(t, err)
+}
+
+func TestLoad_Embedded(t *testing.T) {
+	migrations, err := Load(migrations.FS)
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+	for _, m := range migrations {
+		assert.NotEmpty(t, m.Down, "migration %d_%s", m.Version, m.Name)
+	}
+}
+
+func expectLock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_lock($1)")).WithArgs(LockID).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func expectUnlock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_unlock($1)")).WithArgs(LockID).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func expectVersion(mock sqlmock.Sqlmock, version int64, dirty bool) {
+	rows := sqlmock.NewRows([]string{"version", "dirty"})
+	if version > 0 {
+		rows.AddRow(version, dirty)
+	}
+	mock.ExpectQuery("SELECT version, dirty FROM schema_migrations").WillReturnRows(rows)
+}
+
+func expectSetVersion(mock sqlmock.Sqlmock, version int64, dirty bool) {
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM schema_migrations").WillReturnResult(sqlmock.NewResult(0, 1))
+	if version > 0 {
+		mock.ExpectExec("INSERT INTO schema_migrations").WithArgs(version, dirty).WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectCommit()
+}
+
+func TestMigrator_Up(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	m, err := New(db, testFS)
+	require.NoError(t, err)
+
+	expectLock(mock)
+	expectVersion(mock, 1, false)
+	expectSetVersion(mock, 2, true)
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE b (id int);")).WillReturnResult(sqlmock.NewResult(0, 0))
+	expectSetVersion(mock, 2, false)
+	expectUnlock(mock)
+
+	applied, err := m.Up(context.Background())
+	require.NoError(t, err)
+	require.Len(t, applied, 1)
+	assert.Equal(t, uint64(2), applied[0].Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Down(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	m, err := New(db, testFS)
+	require.NoError(t, err)
+
+	expectLock(mock)
+	expectVersion(mock, 2, false)
+	expectSetVersion(mock, 2, true)
+	mock.ExpectExec(regexp.QuoteMeta("DROP TABLE b;")).WillReturnResult(sqlmock.NewResult(0, 0))
+	expectSetVersion(mock, 1, false)
+	expectSetVersion(mock, 1, true)
+	mock.ExpectExec(regexp.QuoteMeta("DROP TABLE a;")).WillReturnResult(sqlmock.NewResult(0, 0))
+	expectSetVersion(mock, 0, false)
+	expectUnlock(mock)
+
+	reverted, err := m.Down(context.Background(), 0)
+	require.NoError(t, err)
+	assert.Len(t, reverted, 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_UpFailureLeavesDirty(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	m, err := New(db, testFS)
+	require.NoError(t, err)
+
+	expectLock(mock)
+	expectVersion(mock, 0, false)
+	expectSetVersion(mock, 1, true)
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE a (id int);")).WillReturnError(assert.AnError)
+	expectUnlock(mock)
+
+	applied, err := m.Up(context.Background())
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Empty(t, applied)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_DirtyBlocksUntilForce(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	m, err := New(db, testFS)
+	require.NoError(t, err)
+
+	expectLock(mock)
+	expectVersion(mock, 1, true)
+	expectUnlock(mock)
+
+	_, err = m.Up(context.Background())
+	var dirty *DirtyError
+	require.ErrorAs(t, err, &dirty)
+	assert.Equal(t, uint64(1), dirty.Version)
+
+	expectLock(mock)
+	expectSetVersion(mock, 1, false)
+	expectUnlock(mock)
+	require.NoError(t, m.Force(context.Background(), 1))
+
+	assert.Error(t, m.Force(context.Background(), 42))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	cache "L0-wb/internal/cache"
 	models "L0-wb/internal/models"
 	reflect "reflect"
 
@@ -72,3 +73,17 @@ func (mr *MockCacheMockRecorder) Set(key, order interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockCache)(nil).Set), key, order)
 }
+
+// Stats mocks base method.
+func (m *MockCache) Stats() cache.Stats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats")
+	ret0, _ := ret[0].(cache.Stats)
+	return ret0
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockCacheMockRecorder) Stats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockCache)(nil).Stats))
+}
@@ -1,6 +1,7 @@
 // Code generated by MockGen. DO NOT EDIT.
 // Source: internal/repo/repository_interface.go
 
+// Package mocks is a generated GoMock package.
 package mocks
 
 import (
@@ -8,6 +9,7 @@ import (
 	context "context"
 	sql "database/sql"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -35,6 +37,21 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 	return m.recorder
 }
 
+// ClaimWebhookDeliveries mocks base method.
+func (m *MockRepository) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookTask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimWebhookDeliveries", ctx, limit, lease)
+	ret0, _ := ret[0].([]models.WebhookTask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimWebhookDeliveries indicates an expected call of ClaimWebhookDeliveries.
+func (mr *MockRepositoryMockRecorder) ClaimWebhookDeliveries(ctx, limit, lease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimWebhookDeliveries", reflect.TypeOf((*MockRepository)(nil).ClaimWebhookDeliveries), ctx, limit, lease)
+}
+
 // Close mocks base method.
 func (m *MockRepository) Close() error {
 	m.ctrl.T.Helper()
@@ -93,6 +110,39 @@ func (mr *MockRepositoryMockRecorder) CreateOrder(ctx, order interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrder", reflect.TypeOf((*MockRepository)(nil).CreateOrder), ctx, order)
 }
 
+// CreateOrders mocks base method.
+func (m *MockRepository) CreateOrders(ctx context.Context, orders []models.Order) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrders", ctx, orders)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOrders indicates an expected call of CreateOrders.
+func (mr *MockRepositoryMockRecorder) CreateOrders(ctx, orders interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrders", reflect.TypeOf((*MockRepository)(nil).CreateOrders), ctx, orders)
+}
+
+// CreateOutboxTx mocks base method.
+func (m *MockRepository) CreateOutboxTx(ctx context.Context, tx *sql.Tx, events ...models.OrderEvent) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, tx}
+	for _, a := range events {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateOutboxTx", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOutboxTx indicates an expected call of CreateOutboxTx.
+func (mr *MockRepositoryMockRecorder) CreateOutboxTx(ctx, tx interface{}, events ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, tx}, events...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOutboxTx", reflect.TypeOf((*MockRepository)(nil).CreateOutboxTx), varargs...)
+}
+
 // CreatePaymentTx mocks base method.
 func (m *MockRepository) CreatePaymentTx(ctx context.Context, tx *sql.Tx, pay models.Payment) (int, error) {
 	m.ctrl.T.Helper()
@@ -108,6 +158,83 @@ func (mr *MockRepositoryMockRecorder) CreatePaymentTx(ctx, tx, pay interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePaymentTx", reflect.TypeOf((*MockRepository)(nil).CreatePaymentTx), ctx, tx, pay)
 }
 
+// CreateWebhook mocks base method.
+func (m *MockRepository) CreateWebhook(ctx context.Context, sub *models.WebhookSubscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, sub)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockRepositoryMockRecorder) CreateWebhook(ctx, sub interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockRepository)(nil).CreateWebhook), ctx, sub)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockRepository) DeleteWebhook(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockRepositoryMockRecorder) DeleteWebhook(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockRepository)(nil).DeleteWebhook), ctx, id)
+}
+
+// EnqueueWebhooksTx mocks base method.
+func (m *MockRepository) EnqueueWebhooksTx(ctx context.Context, tx *sql.Tx, events ...models.OrderEvent) (int, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, tx}
+	for _, a := range events {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "EnqueueWebhooksTx", varargs...)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnqueueWebhooksTx indicates an expected call of EnqueueWebhooksTx.
+func (mr *MockRepositoryMockRecorder) EnqueueWebhooksTx(ctx, tx interface{}, events ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, tx}, events...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueWebhooksTx", reflect.TypeOf((*MockRepository)(nil).EnqueueWebhooksTx), varargs...)
+}
+
+// ExistingOrderUIDs mocks base method.
+func (m *MockRepository) ExistingOrderUIDs(ctx context.Context, uids []string) (map[string]bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExistingOrderUIDs", ctx, uids)
+	ret0, _ := ret[0].(map[string]bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExistingOrderUIDs indicates an expected call of ExistingOrderUIDs.
+func (mr *MockRepositoryMockRecorder) ExistingOrderUIDs(ctx, uids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistingOrderUIDs", reflect.TypeOf((*MockRepository)(nil).ExistingOrderUIDs), ctx, uids)
+}
+
+// FinishWebhookDelivery mocks base method.
+func (m *MockRepository) FinishWebhookDelivery(ctx context.Context, id int64, res models.WebhookResult) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishWebhookDelivery", ctx, id, res)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishWebhookDelivery indicates an expected call of FinishWebhookDelivery.
+func (mr *MockRepositoryMockRecorder) FinishWebhookDelivery(ctx, id, res interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishWebhookDelivery", reflect.TypeOf((*MockRepository)(nil).FinishWebhookDelivery), ctx, id, res)
+}
+
 // GetDelivery mocks base method.
 func (m *MockRepository) GetDelivery(ctx context.Context, deliveryID int) (models.Delivery, error) {
 	m.ctrl.T.Helper()
@@ -168,6 +295,36 @@ func (mr *MockRepositoryMockRecorder) GetOrder(ctx, orderUID interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockRepository)(nil).GetOrder), ctx, orderUID)
 }
 
+// GetOrderParts mocks base method.
+func (m *MockRepository) GetOrderParts(ctx context.Context, uids []string, parts models.OrderParts) (map[string]models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderParts", ctx, uids, parts)
+	ret0, _ := ret[0].(map[string]models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderParts indicates an expected call of GetOrderParts.
+func (mr *MockRepositoryMockRecorder) GetOrderParts(ctx, uids, parts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderParts", reflect.TypeOf((*MockRepository)(nil).GetOrderParts), ctx, uids, parts)
+}
+
+// GetOrders mocks base method.
+func (m *MockRepository) GetOrders(ctx context.Context, uids []string) ([]models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrders", ctx, uids)
+	ret0, _ := ret[0].([]models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrders indicates an expected call of GetOrders.
+func (mr *MockRepositoryMockRecorder) GetOrders(ctx, uids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrders", reflect.TypeOf((*MockRepository)(nil).GetOrders), ctx, uids)
+}
+
 // GetPayment mocks base method.
 func (m *MockRepository) GetPayment(ctx context.Context, paymentID int) (models.Payment, error) {
 	m.ctrl.T.Helper()
@@ -182,3 +339,165 @@ func (mr *MockRepositoryMockRecorder) GetPayment(ctx, paymentID interface{}) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPayment", reflect.TypeOf((*MockRepository)(nil).GetPayment), ctx, paymentID)
 }
+
+// GetWebhook mocks base method.
+func (m *MockRepository) GetWebhook(ctx context.Context, id int64) (models.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", ctx, id)
+	ret0, _ := ret[0].(models.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockRepositoryMockRecorder) GetWebhook(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockRepository)(nil).GetWebhook), ctx, id)
+}
+
+// ListOrderHeaders mocks base method.
+func (m *MockRepository) ListOrderHeaders(ctx context.Context, filter models.OrderFilter) ([]models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrderHeaders", ctx, filter)
+	ret0, _ := ret[0].([]models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrderHeaders indicates an expected call of ListOrderHeaders.
+func (mr *MockRepositoryMockRecorder) ListOrderHeaders(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrderHeaders", reflect.TypeOf((*MockRepository)(nil).ListOrderHeaders), ctx, filter)
+}
+
+// ListOrderWebhookDeliveries mocks base method.
+func (m *MockRepository) ListOrderWebhookDeliveries(ctx context.Context, orderUID string) ([]models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrderWebhookDeliveries", ctx, orderUID)
+	ret0, _ := ret[0].([]models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrderWebhookDeliveries indicates an expected call of ListOrderWebhookDeliveries.
+func (mr *MockRepositoryMockRecorder) ListOrderWebhookDeliveries(ctx, orderUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrderWebhookDeliveries", reflect.TypeOf((*MockRepository)(nil).ListOrderWebhookDeliveries), ctx, orderUID)
+}
+
+// ListOrders mocks base method.
+func (m *MockRepository) ListOrders(ctx context.Context, filter models.OrderFilter) ([]models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrders", ctx, filter)
+	ret0, _ := ret[0].([]models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrders indicates an expected call of ListOrders.
+func (mr *MockRepositoryMockRecorder) ListOrders(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrders", reflect.TypeOf((*MockRepository)(nil).ListOrders), ctx, filter)
+}
+
+// ListWebhookDeliveries mocks base method.
+func (m *MockRepository) ListWebhookDeliveries(ctx context.Context, subscriptionID int64, limit int) ([]models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookDeliveries", ctx, subscriptionID, limit)
+	ret0, _ := ret[0].([]models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookDeliveries indicates an expected call of ListWebhookDeliveries.
+func (mr *MockRepositoryMockRecorder) ListWebhookDeliveries(ctx, subscriptionID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveries", reflect.TypeOf((*MockRepository)(nil).ListWebhookDeliveries), ctx, subscriptionID, limit)
+}
+
+// ListWebhooks mocks base method.
+func (m *MockRepository) ListWebhooks(ctx context.Context) ([]models.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhooks", ctx)
+	ret0, _ := ret[0].([]models.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhooks indicates an expected call of ListWebhooks.
+func (mr *MockRepositoryMockRecorder) ListWebhooks(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhooks", reflect.TypeOf((*MockRepository)(nil).ListWebhooks), ctx)
+}
+
+// OrderStats mocks base method.
+func (m *MockRepository) OrderStats(ctx context.Context, q models.StatsQuery) (models.OrderStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OrderStats", ctx, q)
+	ret0, _ := ret[0].(models.OrderStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OrderStats indicates an expected call of OrderStats.
+func (mr *MockRepositoryMockRecorder) OrderStats(ctx, q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OrderStats", reflect.TypeOf((*MockRepository)(nil).OrderStats), ctx, q)
+}
+
+// ProcessOutbox mocks base method.
+func (m *MockRepository) ProcessOutbox(ctx context.Context, limit int, lease time.Duration, publish func([]models.OutboxMessage) error) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessOutbox", ctx, limit, lease, publish)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProcessOutbox indicates an expected call of ProcessOutbox.
+func (mr *MockRepositoryMockRecorder) ProcessOutbox(ctx, limit, lease, publish interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessOutbox", reflect.TypeOf((*MockRepository)(nil).ProcessOutbox), ctx, limit, lease, publish)
+}
+
+// StreamOrders mocks base method.
+func (m *MockRepository) StreamOrders(ctx context.Context, filter models.OrderFilter, fn func(*models.Order) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamOrders", ctx, filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamOrders indicates an expected call of StreamOrders.
+func (mr *MockRepositoryMockRecorder) StreamOrders(ctx, filter, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamOrders", reflect.TypeOf((*MockRepository)(nil).StreamOrders), ctx, filter, fn)
+}
+
+// UpdateWebhook mocks base method.
+func (m *MockRepository) UpdateWebhook(ctx context.Context, sub *models.WebhookSubscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhook", ctx, sub)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWebhook indicates an expected call of UpdateWebhook.
+func (mr *MockRepositoryMockRecorder) UpdateWebhook(ctx, sub interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhook", reflect.TypeOf((*MockRepository)(nil).UpdateWebhook), ctx, sub)
+}
+
+// UpsertOrders mocks base method.
+func (m *MockRepository) UpsertOrders(ctx context.Context, orders []models.Order) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertOrders", ctx, orders)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertOrders indicates an expected call of UpsertOrders.
+func (mr *MockRepositoryMockRecorder) UpsertOrders(ctx, orders interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertOrders", reflect.TypeOf((*MockRepository)(nil).UpsertOrders), ctx, orders)
+}
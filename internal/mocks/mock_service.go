@@ -5,7 +5,9 @@
 package mocks
 
 import (
+	cache "L0-wb/internal/cache"
 	models "L0-wb/internal/models"
+	feed "L0-wb/internal/service/feed"
 	context "context"
 	reflect "reflect"
 
@@ -35,6 +37,36 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
+// BatchGetOrders mocks base method.
+func (m *MockService) BatchGetOrders(ctx context.Context, uids []string) ([]*models.Order, []string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchGetOrders", ctx, uids)
+	ret0, _ := ret[0].([]*models.Order)
+	ret1, _ := ret[1].([]string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// BatchGetOrders indicates an expected call of BatchGetOrders.
+func (mr *MockServiceMockRecorder) BatchGetOrders(ctx, uids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchGetOrders", reflect.TypeOf((*MockService)(nil).BatchGetOrders), ctx, uids)
+}
+
+// CacheStats mocks base method.
+func (m *MockService) CacheStats() cache.Stats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CacheStats")
+	ret0, _ := ret[0].(cache.Stats)
+	return ret0
+}
+
+// CacheStats indicates an expected call of CacheStats.
+func (mr *MockServiceMockRecorder) CacheStats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CacheStats", reflect.TypeOf((*MockService)(nil).CacheStats))
+}
+
 // Close mocks base method.
 func (m *MockService) Close() error {
 	m.ctrl.T.Helper()
@@ -63,6 +95,34 @@ func (mr *MockServiceMockRecorder) CreateOrder(ctx, order interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrder", reflect.TypeOf((*MockService)(nil).CreateOrder), ctx, order)
 }
 
+// CreateWebhook mocks base method.
+func (m *MockService) CreateWebhook(ctx context.Context, sub *models.WebhookSubscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, sub)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockServiceMockRecorder) CreateWebhook(ctx, sub interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockService)(nil).CreateWebhook), ctx, sub)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockService) DeleteWebhook(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockServiceMockRecorder) DeleteWebhook(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockService)(nil).DeleteWebhook), ctx, id)
+}
+
 // GetOrderByUID mocks base method.
 func (m *MockService) GetOrderByUID(ctx context.Context, orderUID string) (*models.Order, error) {
 	m.ctrl.T.Helper()
@@ -93,6 +153,111 @@ func (mr *MockServiceMockRecorder) GetOrderResponse(ctx, orderUID interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderResponse", reflect.TypeOf((*MockService)(nil).GetOrderResponse), ctx, orderUID)
 }
 
+// GetWebhook mocks base method.
+func (m *MockService) GetWebhook(ctx context.Context, id int64) (models.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", ctx, id)
+	ret0, _ := ret[0].(models.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockServiceMockRecorder) GetWebhook(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockService)(nil).GetWebhook), ctx, id)
+}
+
+// ListOrderHeaders mocks base method.
+func (m *MockService) ListOrderHeaders(ctx context.Context, filter models.OrderFilter) ([]models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrderHeaders", ctx, filter)
+	ret0, _ := ret[0].([]models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrderHeaders indicates an expected call of ListOrderHeaders.
+func (mr *MockServiceMockRecorder) ListOrderHeaders(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrderHeaders", reflect.TypeOf((*MockService)(nil).ListOrderHeaders), ctx, filter)
+}
+
+// ListOrders mocks base method.
+func (m *MockService) ListOrders(ctx context.Context, filter models.OrderFilter) ([]models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrders", ctx, filter)
+	ret0, _ := ret[0].([]models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrders indicates an expected call of ListOrders.
+func (mr *MockServiceMockRecorder) ListOrders(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrders", reflect.TypeOf((*MockService)(nil).ListOrders), ctx, filter)
+}
+
+// ListWebhooks mocks base method.
+func (m *MockService) ListWebhooks(ctx context.Context) ([]models.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhooks", ctx)
+	ret0, _ := ret[0].([]models.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhooks indicates an expected call of ListWebhooks.
+func (mr *MockServiceMockRecorder) ListWebhooks(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhooks", reflect.TypeOf((*MockService)(nil).ListWebhooks), ctx)
+}
+
+// OrderParts mocks base method.
+func (m *MockService) OrderParts(ctx context.Context, uids []string, parts models.OrderParts) (map[string]models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OrderParts", ctx, uids, parts)
+	ret0, _ := ret[0].(map[string]models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OrderParts indicates an expected call of OrderParts.
+func (mr *MockServiceMockRecorder) OrderParts(ctx, uids, parts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OrderParts", reflect.TypeOf((*MockService)(nil).OrderParts), ctx, uids, parts)
+}
+
+// OrderStats mocks base method.
+func (m *MockService) OrderStats(ctx context.Context, q models.StatsQuery) (models.OrderStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OrderStats", ctx, q)
+	ret0, _ := ret[0].(models.OrderStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OrderStats indicates an expected call of OrderStats.
+func (mr *MockServiceMockRecorder) OrderStats(ctx, q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OrderStats", reflect.TypeOf((*MockService)(nil).OrderStats), ctx, q)
+}
+
+// OrderTimeline mocks base method.
+func (m *MockService) OrderTimeline(ctx context.Context, orderUID string) ([]models.TimelineEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OrderTimeline", ctx, orderUID)
+	ret0, _ := ret[0].([]models.TimelineEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OrderTimeline indicates an expected call of OrderTimeline.
+func (mr *MockServiceMockRecorder) OrderTimeline(ctx, orderUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OrderTimeline", reflect.TypeOf((*MockService)(nil).OrderTimeline), ctx, orderUID)
+}
+
 // RestoreCache mocks base method.
 func (m *MockService) RestoreCache(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -120,3 +285,89 @@ func (mr *MockServiceMockRecorder) SaveOrder(ctx, order interface{}) *gomock.Cal
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveOrder", reflect.TypeOf((*MockService)(nil).SaveOrder), ctx, order)
 }
+
+// SaveOrders mocks base method.
+func (m *MockService) SaveOrders(ctx context.Context, orders []*models.Order) []error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveOrders", ctx, orders)
+	ret0, _ := ret[0].([]error)
+	return ret0
+}
+
+// SaveOrders indicates an expected call of SaveOrders.
+func (mr *MockServiceMockRecorder) SaveOrders(ctx, orders interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveOrders", reflect.TypeOf((*MockService)(nil).SaveOrders), ctx, orders)
+}
+
+// StreamOrders mocks base method.
+func (m *MockService) StreamOrders(ctx context.Context, filter models.OrderFilter, fn func(*models.Order) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamOrders", ctx, filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamOrders indicates an expected call of StreamOrders.
+func (mr *MockServiceMockRecorder) StreamOrders(ctx, filter, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamOrders", reflect.TypeOf((*MockService)(nil).StreamOrders), ctx, filter, fn)
+}
+
+// SubscribeOrders mocks base method.
+func (m *MockService) SubscribeOrders(filter feed.Filter) *feed.Subscription {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeOrders", filter)
+	ret0, _ := ret[0].(*feed.Subscription)
+	return ret0
+}
+
+// SubscribeOrders indicates an expected call of SubscribeOrders.
+func (mr *MockServiceMockRecorder) SubscribeOrders(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeOrders", reflect.TypeOf((*MockService)(nil).SubscribeOrders), filter)
+}
+
+// UpdateWebhook mocks base method.
+func (m *MockService) UpdateWebhook(ctx context.Context, sub *models.WebhookSubscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhook", ctx, sub)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWebhook indicates an expected call of UpdateWebhook.
+func (mr *MockServiceMockRecorder) UpdateWebhook(ctx, sub interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhook", reflect.TypeOf((*MockService)(nil).UpdateWebhook), ctx, sub)
+}
+
+// WarmCache mocks base method.
+func (m *MockService) WarmCache(ctx context.Context, limit int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WarmCache", ctx, limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WarmCache indicates an expected call of WarmCache.
+func (mr *MockServiceMockRecorder) WarmCache(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WarmCache", reflect.TypeOf((*MockService)(nil).WarmCache), ctx, limit)
+}
+
+// WebhookDeliveries mocks base method.
+func (m *MockService) WebhookDeliveries(ctx context.Context, id int64, limit int) ([]models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WebhookDeliveries", ctx, id, limit)
+	ret0, _ := ret[0].([]models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WebhookDeliveries indicates an expected call of WebhookDeliveries.
+func (mr *MockServiceMockRecorder) WebhookDeliveries(ctx, id, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WebhookDeliveries", reflect.TypeOf((*MockService)(nil).WebhookDeliveries), ctx, id, limit)
+}
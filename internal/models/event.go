@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// Типы событий, публикуемых через outbox
+const (
+	EventOrderSaved = "order.saved"
+)
+
+// OrderEvent - событие о заказе для внешних сервисов
+type OrderEvent struct {
+	EventType       string    `json:"event_type"`
+	OrderUID        string    `json:"order_uid"`
+	TrackNumber     string    `json:"track_number"`
+	CustomerID      string    `json:"customer_id"`
+	DeliveryService string    `json:"delivery_service"`
+	Amount          int       `json:"amount"`
+	Currency        string    `json:"currency"`
+	ItemsCount      int       `json:"items_count"`
+	DateCreated     time.Time `json:"date_created"`
+	OccurredAt      time.Time `json:"occurred_at"`
+}
+
+// OutboxMessage - запись outbox, ожидающая публикации
+type OutboxMessage struct {
+	ID          int64
+	EventType   string
+	AggregateID string
+	Payload     []byte
+	CreatedAt   time.Time
+}
+
+// NewOrderSavedEvent собирает событие order.saved по сохранённому заказу
+func NewOrderSavedEvent(o *Order, at time.Time) OrderEvent {
+	return OrderEvent{
+		EventType:       EventOrderSaved,
+		OrderUID:        o.OrderUID,
+		TrackNumber:     o.TrackNumber,
+		CustomerID:      o.CustomerID,
+		DeliveryService: o.DeliveryService,
+		Amount:          o.Payment.Amount,
+		Currency:        o.Payment.Currency,
+		ItemsCount:      len(o.Items),
+		DateCreated:     o.DateCreated,
+		OccurredAt:      at,
+	}
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// DefaultListLimit - размер страницы списка заказов по умолчанию
+const DefaultListLimit = 50
+
+// OrderFilter - условия выборки списка заказов; пустые поля не фильтруют
+type OrderFilter struct {
+	CustomerID      string
+	TrackNumber     string
+	Locale          string
+	DeliveryService string
+	CreatedFrom     time.Time // включительно
+	CreatedTo       time.Time // не включительно
+	Limit           int
+	Offset          int
+}
+
+// OrderParts - какие связанные с заказом данные догрузить
+type OrderParts struct {
+	Delivery bool
+	Payment  bool
+	Items    bool
+}
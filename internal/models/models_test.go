@@ -72,6 +72,25 @@ func TestOrder_Validate(t *testing.T) {
 	}
 }
 
+func TestOrder_CheckTotals(t *testing.T) {
+	newOrder := func(goodsTotal, amount int) *Order {
+		return &Order{
+			OrderUID: "test-123",
+			Payment: Payment{
+				Amount:       amount,
+				DeliveryCost: 1500,
+				GoodsTotal:   goodsTotal,
+			},
+			Items: []Item{{Price: 453, TotalPrice: 317}},
+		}
+	}
+
+	assert.NoError(t, newOrder(317, 1817).CheckTotals())
+	assert.NoError(t, newOrder(0, 1000).CheckTotals(), "без goods_total суммы не сверяются")
+	assert.Error(t, newOrder(400, 1900).CheckTotals(), "goods_total не совпадает с товарами")
+	assert.Error(t, newOrder(317, 2000).CheckTotals(), "amount не совпадает с суммой")
+}
+
 func TestDelivery_Validate(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -117,3 +136,61 @@ func TestDelivery_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestWebhookSubscription_Validate(t *testing.T) {
+	tests := map[string]bool{
+		"https://partner.example/hook":             true,
+		"http://93.184.216.34:8080/hook":           true,
+		"https://[2606:4700::1]/hook":              true,
+		"ftp://partner.example":                    false,
+		"http://localhost:8080/hook":               false,
+		"http://api.localhost/hook":                false,
+		"http://metadata.google.internal/":         false,
+		"http://127.0.0.1/hook":                    false,
+		"http://10.0.0.5/hook":                     false,
+		"http://192.168.1.1/hook":                  false,
+		"http://169.254.169.254/latest/meta-data/": false,
+		"http://100.100.100.200/":                  false,
+		"http://0.0.0.0/":                          false,
+		"http://[::1]/hook":                        false,
+		"http://[fd00:ec2::254]/":                  false,
+		"http://[::ffff:127.0.0.1]/":               false,
+	}
+	for u, valid := range tests {
+		err := (&WebhookSubscription{URL: u}).Validate()
+		if valid {
+			assert.NoError(t, err, u)
+		} else {
+			assert.Error(t, err, u)
+		}
+	}
+}
+
+func TestBuildTimeline(t *testing.T) {
+	created := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	paid := created.Add(-time.Minute)
+	delivered := created.Add(2 * time.Second)
+	order := &Order{
+		DateCreated: created,
+		Payment:     Payment{Amount: 1817, Currency: "USD", Provider: "wbpay", PaymentDt: int(paid.Unix())},
+	}
+	deliveries := []WebhookDelivery{
+		{SubscriptionID: 1, EventType: EventOrderSaved, Status: WebhookDelivered, Attempts: 1,
+			LastStatusCode: 200, CreatedAt: created.Add(time.Second), DeliveredAt: &delivered},
+		{SubscriptionID: 2, EventType: EventOrderSaved, Status: WebhookFailed, Attempts: 8,
+			LastStatusCode: 503, LastError: "unavailable", CreatedAt: created.Add(time.Second),
+			NextAttemptAt: created.Add(time.Hour)},
+	}
+
+	events := BuildTimeline(order, deliveries)
+	assert.Equal(t, []TimelineEvent{
+		{Type: TimelinePaid, At: paid, Detail: "1817 USD, wbpay"},
+		{Type: TimelineCreated, At: created},
+		{Type: TimelineWebhook, At: delivered, Status: WebhookDelivered, Detail: EventOrderSaved},
+		{Type: TimelineWebhook, At: created.Add(time.Hour), Status: WebhookFailed, Detail: EventOrderSaved},
+	}, events)
+
+	// без оплаты и вебхуков - только создание
+	assert.Equal(t, []TimelineEvent{{Type: TimelineCreated, At: created}},
+		BuildTimeline(&Order{DateCreated: created}, nil))
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// DefaultStatsTop - сколько брендов и размеров попадает в топ по умолчанию
+const DefaultStatsTop = 10
+
+// StatsQuery - интервал [From, To) по date_created и размер топов
+type StatsQuery struct {
+	From time.Time
+	To   time.Time
+	Top  int
+}
+
+// StatsBucket - число заказов и выручка (сумма payment.amount) по одному значению ключа
+type StatsBucket struct {
+	Key     string `json:"key"`
+	Orders  int    `json:"orders"`
+	Revenue int64  `json:"revenue"`
+}
+
+// ItemBucket - число товаров и сумма total_price по одному значению ключа
+type ItemBucket struct {
+	Key     string `json:"key"`
+	Items   int    `json:"items"`
+	Revenue int64  `json:"revenue"`
+}
+
+// OrderStats - агрегаты по заказам за интервал. Выручка складывается
+// в единицах payment.amount без пересчёта валют.
+type OrderStats struct {
+	From          time.Time     `json:"from"`
+	To            time.Time     `json:"to"`
+	Orders        int           `json:"orders"`
+	Revenue       int64         `json:"revenue"`
+	AvgBasketSize float64       `json:"avg_basket_size"` // товаров в заказе
+	AvgSale       float64       `json:"avg_sale"`        // средняя скидка товара, %
+	ByDay         []StatsBucket `json:"by_day"`
+	ByDelivery    []StatsBucket `json:"by_delivery_service"`
+	ByProvider    []StatsBucket `json:"by_provider"`
+	ByBank        []StatsBucket `json:"by_bank"`
+	TopBrands     []ItemBucket  `json:"top_brands"`
+	TopSizes      []ItemBucket  `json:"top_sizes"`
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Шаги истории заказа
+const (
+	TimelineCreated = "created"
+	TimelinePaid    = "paid"
+	TimelineWebhook = "webhook"
+)
+
+// TimelineEvent - шаг истории заказа. Status есть только у вебхуков:
+// pending, delivered или failed. История публичная, поэтому подписчики,
+// их ответы и ошибки доставки в неё не попадают
+type TimelineEvent struct {
+	Type   string    `json:"type"`
+	At     time.Time `json:"at"`
+	Status string    `json:"status,omitempty"`
+	Detail string    `json:"detail,omitempty"`
+}
+
+// BuildTimeline собирает историю заказа по времени: создание, оплата
+// и доставка событий о заказе подписчикам вебхуков
+func BuildTimeline(o *Order, deliveries []WebhookDelivery) []TimelineEvent {
+	events := []TimelineEvent{{Type: TimelineCreated, At: o.DateCreated}}
+	if o.Payment.PaymentDt > 0 {
+		events = append(events, TimelineEvent{
+			Type:   TimelinePaid,
+			At:     time.Unix(int64(o.Payment.PaymentDt), 0).UTC(),
+			Detail: fmt.Sprintf("%d %s, %s", o.Payment.Amount, o.Payment.Currency, o.Payment.Provider),
+		})
+	}
+
+	for _, d := range deliveries {
+		e := TimelineEvent{Type: TimelineWebhook, At: d.CreatedAt, Status: d.Status, Detail: d.EventType}
+		switch {
+		case d.DeliveredAt != nil:
+			e.At = *d.DeliveredAt
+		case d.Status == WebhookFailed:
+			// после последней попытки next_attempt_at больше не сдвигается
+			e.At = d.NextAttemptAt
+		}
+		events = append(events, e)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].At.Before(events[j].At) })
+	return events
+}
@@ -1,10 +1,17 @@
 package models
 
 import (
+	"errors"
 	"fmt"
 	"regexp"
 )
 
+// ErrInvalidOrder - заказ не прошёл валидацию; повтор сохранения не поможет
+var ErrInvalidOrder = errors.New("invalid order")
+
+// ErrBatchFailed - пачка откатилась целиком, сам заказ мог быть в порядке
+var ErrBatchFailed = errors.New("batch not saved")
+
 var (
 	phoneRegex = regexp.MustCompile(`^\+?[0-9]{10,15}$`)
 	emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
@@ -31,6 +38,28 @@ func (o *Order) Validate() error {
 	return nil
 }
 
+// CheckTotals сверяет суммы оплаты с товарами. В Validate проверка не входит:
+// сервис принимает заказы с несходящимися суммами, а сверяет их `orderctl validate -totals`.
+// goods_total = 0 означает, что отправитель суммы не считал, и проверка пропускается.
+func (o *Order) CheckTotals() error {
+	p := o.Payment
+	if p.GoodsTotal == 0 {
+		return nil
+	}
+
+	itemsTotal := 0
+	for _, item := range o.Items {
+		itemsTotal += item.TotalPrice
+	}
+	if p.GoodsTotal != itemsTotal {
+		return fmt.Errorf("goods_total %d does not match items total %d", p.GoodsTotal, itemsTotal)
+	}
+	if want := p.GoodsTotal + p.DeliveryCost + p.CustomFee; p.Amount != want {
+		return fmt.Errorf("amount %d does not match goods_total + delivery_cost + custom_fee = %d", p.Amount, want)
+	}
+	return nil
+}
+
 func (o *Order) validateBasicFields() error {
 	if o.OrderUID == "" {
 		return fmt.Errorf("order_uid is required")
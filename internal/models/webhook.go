@@ -0,0 +1,123 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Статусы доставки вебхука
+const (
+	WebhookPending   = "pending"
+	WebhookDelivered = "delivered"
+	WebhookFailed    = "failed"
+)
+
+// WebhookEvents - события, на которые можно подписаться
+var WebhookEvents = []string{EventOrderSaved}
+
+// WebhookSubscription - адрес партнёра и условия, при которых ему отправляются события.
+// Пустые Events, CustomerID и DeliveryService не фильтруют.
+type WebhookSubscription struct {
+	ID              int64     `json:"id"`
+	URL             string    `json:"url"`
+	Secret          string    `json:"secret,omitempty"`
+	Events          []string  `json:"events"`
+	CustomerID      string    `json:"customer_id"`
+	DeliveryService string    `json:"delivery_service"`
+	Active          bool      `json:"active"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// nonPublicPrefixes - сети, не покрытые методами netip.Addr: "этот" хост,
+// CGNAT (там же метаданные некоторых облаков), служебные и зарезервированные
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// IsPublicWebhookAddr сообщает, можно ли отправлять вебхуки на адрес: петлевые,
+// частные, link-local (169.254.169.254 - метаданные облака) и прочие
+// внутренние сети запрещены
+func IsPublicWebhookAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, p := range nonPublicPrefixes {
+		if p.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate проверяет адрес и список событий подписки. Имена хостов проверяются
+// только по виду: куда они резолвятся, проверяет диспетчер при подключении.
+func (s *WebhookSubscription) Validate() error {
+	u, err := url.Parse(s.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url must be an absolute http(s) URL")
+	}
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if !IsPublicWebhookAddr(addr) {
+			return fmt.Errorf("url must not point to a private, loopback or link-local address")
+		}
+	} else if host == "localhost" || strings.HasSuffix(host, ".localhost") ||
+		strings.HasSuffix(host, ".internal") || strings.HasSuffix(host, ".local") {
+		return fmt.Errorf("url must not point to an internal host")
+	}
+	for _, e := range s.Events {
+		known := false
+		for _, w := range WebhookEvents {
+			known = known || e == w
+		}
+		if !known {
+			return fmt.Errorf("unknown event %q", e)
+		}
+	}
+	return nil
+}
+
+// WebhookDelivery - запись журнала доставки одного события одной подписке
+type WebhookDelivery struct {
+	ID             int64           `json:"id"`
+	SubscriptionID int64           `json:"subscription_id"`
+	EventType      string          `json:"event_type"`
+	OrderUID       string          `json:"order_uid"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	LastStatusCode int             `json:"last_status_code"`
+	LastError      string          `json:"last_error"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+}
+
+// WebhookTask - доставка, взятая в работу диспетчером; Attempt - номер текущей попытки
+type WebhookTask struct {
+	DeliveryID int64
+	EventType  string
+	OrderUID   string
+	Payload    []byte
+	Attempt    int
+	URL        string
+	Secret     string
+}
+
+// WebhookResult - итог попытки: Status pending означает повтор через RetryIn
+type WebhookResult struct {
+	Status     string
+	StatusCode int
+	Error      string
+	RetryIn    time.Duration
+}
@@ -0,0 +1,127 @@
+package outbox
+
+import (
+	"L0-wb/config"
+	"L0-wb/internal/models"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+)
+
+// HeaderEventType - заголовок с типом события в публикуемом сообщении
+const HeaderEventType = "event_type"
+
+// lease - на сколько реле арендует пачку; пачка, не опубликованная и не удалённая
+// за это время, достанется следующему проходу
+const lease = time.Minute
+
+// Store - часть репозитория, нужная реле
+type Store interface {
+	ProcessOutbox(ctx context.Context, limit int, lease time.Duration, publish func([]models.OutboxMessage) error) (int, error)
+}
+
+// Writer - часть kafka.Writer, нужная реле
+type Writer interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// Relay периодически забирает записи outbox и публикует их в Kafka.
+// Запись удаляется только после подтверждения от брокера, поэтому
+// при сбоях событие может быть доставлено повторно, но не потеряно.
+type Relay struct {
+	store     Store
+	writer    Writer
+	batchSize int
+	interval  time.Duration
+}
+
+func NewRelay(cfg config.Config, store Store) *Relay {
+	brokerAddr := fmt.Sprintf("%s:%d", cfg.Kafka.Host, cfg.Kafka.Port)
+	writer := &kafka.Writer{
+		Addr:                   kafka.TCP(brokerAddr),
+		Topic:                  cfg.Outbox.Topic,
+		Balancer:               &kafka.Hash{},
+		RequiredAcks:           kafka.RequireAll,
+		AllowAutoTopicCreation: true,
+	}
+	logrus.WithField("topic", cfg.Outbox.Topic).Info("outbox relay initialized")
+
+	return newRelay(store, writer, cfg.Outbox.BatchSize, cfg.Outbox.Interval)
+}
+
+func newRelay(store Store, writer Writer, batchSize int, interval time.Duration) *Relay {
+	if batchSize < 1 {
+		batchSize = 100
+	}
+	if interval <= 0 {
+		interval = time.Second
+	}
+	return &Relay{
+		store:     store,
+		writer:    writer,
+		batchSize: batchSize,
+		interval:  interval,
+	}
+}
+
+// Run публикует события до отмены ctx. Полные пачки выбираются сразу одна за другой,
+// после неполной реле ждёт interval.
+func (r *Relay) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := r.PublishBatch(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			logrus.WithError(err).Error("outbox relay error")
+		}
+		if n == r.batchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// PublishBatch публикует одну пачку и возвращает количество отправленных событий
+func (r *Relay) PublishBatch(ctx context.Context) (int, error) {
+	n, err := r.store.ProcessOutbox(ctx, r.batchSize, lease, func(messages []models.OutboxMessage) error {
+		msgs := make([]kafka.Message, len(messages))
+		for i, m := range messages {
+			msgs[i] = kafka.Message{
+				Key:     []byte(m.AggregateID),
+				Value:   m.Payload,
+				Headers: []kafka.Header{{Key: HeaderEventType, Value: []byte(m.EventType)}},
+				Time:    m.CreatedAt,
+			}
+		}
+		return r.writer.WriteMessages(ctx, msgs...)
+	})
+	if err != nil {
+		return 0, err
+	}
+	if n > 0 {
+		logrus.WithField("events", n).Info("outbox events published")
+	}
+	return n, nil
+}
+
+func (r *Relay) Close() error {
+	if r.writer != nil {
+		err := r.writer.Close()
+		r.writer = nil
+		return err
+	}
+	return nil
+}
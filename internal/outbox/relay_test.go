@@ -0,0 +1,99 @@
+package outbox
+
+import (
+	"L0-wb/internal/models"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	pending []models.OutboxMessage
+}
+
+func (s *fakeStore) ProcessOutbox(_ context.Context, limit int, _ time.Duration, publish func([]models.OutboxMessage) error) (int, error) {
+	n := limit
+	if n > len(s.pending) {
+		n = len(s.pending)
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	if err := publish(s.pending[:n]); err != nil {
+		return 0, err
+	}
+	s.pending = s.pending[n:]
+	return n, nil
+}
+
+type fakeWriter struct {
+	fail bool
+	sent []kafka.Message
+}
+
+func (w *fakeWriter) WriteMessages(_ context.Context, msgs ...kafka.Message) error {
+	if w.fail {
+		return errors.New("broker unavailable")
+	}
+	w.sent = append(w.sent, msgs...)
+	return nil
+}
+
+func (w *fakeWriter) Close() error { return nil }
+
+func outboxMessages(n int) []models.OutboxMessage {
+	messages := make([]models.OutboxMessage, n)
+	for i := range messages {
+		messages[i] = models.OutboxMessage{
+			ID:          int64(i + 1),
+			EventType:   models.EventOrderSaved,
+			AggregateID: "order-" + string(rune('a'+i)),
+			Payload:     []byte(`{}`),
+			CreatedAt:   time.Now(),
+		}
+	}
+	return messages
+}
+
+func TestRelay_PublishBatch(t *testing.T) {
+	store := &fakeStore{pending: outboxMessages(3)}
+	writer := &fakeWriter{}
+	relay := newRelay(store, writer, 2, time.Millisecond)
+
+	n, err := relay.PublishBatch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	require.Len(t, writer.sent, 2)
+	assert.Equal(t, "order-a", string(writer.sent[0].Key))
+	assert.Equal(t, HeaderEventType, writer.sent[0].Headers[0].Key)
+	assert.Equal(t, models.EventOrderSaved, string(writer.sent[0].Headers[0].Value))
+	assert.Len(t, store.pending, 1)
+}
+
+func TestRelay_KeepsEventsOnPublishError(t *testing.T) {
+	store := &fakeStore{pending: outboxMessages(2)}
+	relay := newRelay(store, &fakeWriter{fail: true}, 10, time.Millisecond)
+
+	_, err := relay.PublishBatch(context.Background())
+	assert.Error(t, err)
+	assert.Len(t, store.pending, 2)
+}
+
+func TestRelay_RunDrainsOutbox(t *testing.T) {
+	store := &fakeStore{pending: outboxMessages(5)}
+	writer := &fakeWriter{}
+	relay := newRelay(store, writer, 2, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := relay.Run(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Len(t, writer.sent, 5)
+	assert.Empty(t, store.pending)
+}
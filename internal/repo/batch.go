@@ -0,0 +1,232 @@
+package repo
+
+import (
+	"L0-wb/internal/models"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// maxQueryParams - предел параметров одного запроса в протоколе Postgres;
+// многострочные INSERT разбиваются на порции под него
+const maxQueryParams = 65535
+
+// CreateOrders сохраняет пачку заказов одной транзакцией.
+// ID для delivery и payment берутся из последовательностей заранее,
+// после чего каждая таблица заполняется многострочными INSERT.
+// При любой ошибке откатывается вся пачка.
+func (pgs *PostgresRepo) CreateOrders(ctx context.Context, orders []models.Order) error {
+	return pgs.saveOrders(ctx, orders, false)
+}
+
+// UpsertOrders сохраняет пачку заказов как CreateOrders, предварительно удаляя
+// заказы с теми же order_uid вместе с их доставкой, оплатой и товарами
+func (pgs *PostgresRepo) UpsertOrders(ctx context.Context, orders []models.Order) error {
+	return pgs.saveOrders(ctx, orders, true)
+}
+
+func (pgs *PostgresRepo) saveOrders(ctx context.Context, orders []models.Order, replace bool) error {
+	if len(orders) == 0 {
+		return nil
+	}
+	for i, order := range orders {
+		if err := validateForInsert(order); err != nil {
+			return fmt.Errorf("order %d (%s): %w", i+1, order.OrderUID, err)
+		}
+	}
+
+	tx, err := pgs.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if replace {
+		if err = deleteOrders(ctx, tx, orders); err != nil {
+			return fmt.Errorf("replaced orders deletion error: %w", err)
+		}
+	}
+
+	deliveryIDs, err := nextIDs(ctx, tx, "delivery_id_seq", len(orders))
+	if err != nil {
+		return fmt.Errorf("delivery ids allocation error: %w", err)
+	}
+	paymentIDs, err := nextIDs(ctx, tx, "payment_id_seq", len(orders))
+	if err != nil {
+		return fmt.Errorf("payment ids allocation error: %w", err)
+	}
+
+	if err = insertDeliveries(ctx, tx, orders, deliveryIDs); err != nil {
+		return fmt.Errorf("delivery batch creation error: %w", err)
+	}
+	if err = insertPayments(ctx, tx, orders, paymentIDs); err != nil {
+		return fmt.Errorf("payment batch creation error: %w", err)
+	}
+	if err = insertOrders(ctx, tx, orders, deliveryIDs, paymentIDs); err != nil {
+		return fmt.Errorf("order batch creation error: %w", err)
+	}
+	if err = insertItems(ctx, tx, orders); err != nil {
+		return fmt.Errorf("item batch creation error: %w", err)
+	}
+	events := orderSavedEvents(orders)
+	if pgs.outbox {
+		if err = pgs.CreateOutboxTx(ctx, tx, events...); err != nil {
+			return fmt.Errorf("outbox batch creation error: %w", err)
+		}
+	}
+	// перезапись (импорт) подписчикам не отправляется
+	if pgs.webhooks && !replace {
+		if _, err = pgs.EnqueueWebhooksTx(ctx, tx, events...); err != nil {
+			return fmt.Errorf("webhook deliveries batch creation error: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// validateForInsert - те же проверки, что у CreateOrder и его CreateXxxTx
+func validateForInsert(order models.Order) error {
+	if order.OrderUID == "" {
+		return fmt.Errorf("order_uid cannot be empty")
+	}
+	if err := checkDelivery(order.Delivery); err != nil {
+		return fmt.Errorf("delivery:%w", err)
+	}
+	if err := checkPayment(order.Payment); err != nil {
+		return fmt.Errorf("payment:%w", err)
+	}
+	for i, item := range order.Items {
+		if err := checkItem(item, order.OrderUID); err != nil {
+			return fmt.Errorf("item %d:%w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func nextIDs(ctx context.Context, tx *sql.Tx, sequence string, n int) ([]int, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT nextval($1::regclass) FROM generate_series(1, $2)`, sequence, n)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]int, 0, n)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(ids) != n {
+		return nil, fmt.Errorf("allocated %d ids from %s, want %d", len(ids), sequence, n)
+	}
+	return ids, nil
+}
+
+func insertDeliveries(ctx context.Context, tx *sql.Tx, orders []models.Order, ids []int) error {
+	return insertRows(ctx, tx, `INSERT INTO delivery (id, name, phone, zip, city, address, region, email) VALUES `,
+		8, len(orders), func(args []interface{}, i int) []interface{} {
+			d := orders[i].Delivery
+			return append(args, ids[i], d.Name, d.Phone, d.Zip, d.City, d.Address, d.Region, d.Email)
+		})
+}
+
+func insertPayments(ctx context.Context, tx *sql.Tx, orders []models.Order, ids []int) error {
+	return insertRows(ctx, tx, `INSERT INTO payment (id, transaction, request_id, currency, provider, amount, payment_dt, bank, delivery_cost, goods_total, custom_fee) VALUES `,
+		11, len(orders), func(args []interface{}, i int) []interface{} {
+			p := orders[i].Payment
+			return append(args, ids[i], p.Transaction, p.RequestID, p.Currency, p.Provider, p.Amount,
+				p.PaymentDt, p.Bank, p.DeliveryCost, p.GoodsTotal, p.CustomFee)
+		})
+}
+
+func insertOrders(ctx context.Context, tx *sql.Tx, orders []models.Order, deliveryIDs, paymentIDs []int) error {
+	query := `INSERT INTO orders (
+		order_uid, track_number, entry, delivery_id, payment_id, locale, internal_signature,
+		customer_id, delivery_service, shardkey, sm_id, date_created, oof_shard
+	) VALUES `
+	return insertRows(ctx, tx, query, 13, len(orders), func(args []interface{}, i int) []interface{} {
+		o := orders[i]
+		return append(args, o.OrderUID, o.TrackNumber, o.Entry, deliveryIDs[i], paymentIDs[i],
+			o.Locale, o.InternalSignature, o.CustomerID, o.DeliveryService,
+			o.Shardkey, o.SmID, o.DateCreated, o.OofShard)
+	})
+}
+
+func insertItems(ctx context.Context, tx *sql.Tx, orders []models.Order) error {
+	type itemRow struct {
+		orderUID string
+		item     *models.Item
+	}
+	var items []itemRow
+	for i := range orders {
+		for j := range orders[i].Items {
+			items = append(items, itemRow{orderUID: orders[i].OrderUID, item: &orders[i].Items[j]})
+		}
+	}
+	return insertRows(ctx, tx, `INSERT INTO item (chrt_id, track_number, price, rid, name, sale, size, total_price, nm_id, brand, status, order_uid) VALUES `,
+		12, len(items), func(args []interface{}, i int) []interface{} {
+			it := items[i].item
+			return append(args, it.ChrtID, it.TrackNumber, it.Price, it.Rid, it.Name,
+				it.Sale, it.Size, it.TotalPrice, it.NmID, it.Brand, it.Status, items[i].orderUID)
+		})
+}
+
+// insertRows вставляет rows строк по cols колонок запросами prefix + VALUES (...),
+// порциями не больше maxQueryParams параметров. appendRow дописывает в args
+// значения строки i.
+func insertRows(ctx context.Context, tx *sql.Tx, prefix string, cols, rows int, appendRow func(args []interface{}, i int) []interface{}) error {
+	chunk := chunkRows(cols)
+	args := make([]interface{}, 0, min(rows, chunk)*cols)
+	for start := 0; start < rows; start += chunk {
+		end := min(start+chunk, rows)
+		args = args[:0]
+		for i := start; i < end; i++ {
+			args = appendRow(args, i)
+		}
+		if _, err := tx.ExecContext(ctx, prefix+valuesPlaceholders(end-start, cols), args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// chunkRows - сколько строк по cols колонок помещается в один запрос
+func chunkRows(cols int) int {
+	return maxQueryParams / cols
+}
+
+// valuesPlaceholders строит "($1,$2),($3,$4)" для rows строк по cols колонок
+func valuesPlaceholders(rows, cols int) string {
+	var b strings.Builder
+	b.Grow(rows * cols * 5)
+	n := 1
+	for r := 0; r < rows; r++ {
+		if r > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteByte('(')
+		for c := 0; c < cols; c++ {
+			if c > 0 {
+				b.WriteByte(',')
+			}
+			fmt.Fprintf(&b, "$%d", n)
+			n++
+		}
+		b.WriteByte(')')
+	}
+	return b.String()
+}
@@ -0,0 +1,71 @@
+//go:build integration
+
+package repo
+
+import (
+	"L0-wb/config"
+	"L0-wb/internal/db"
+	"L0-wb/internal/generator"
+	"L0-wb/internal/models"
+	"context"
+	"testing"
+)
+
+// Бенчмарки пишут в реальную БД из .env:
+// go test -tags integration -run ^$ -bench CreateOrder -benchtime 1x ./internal/repo
+func benchOrders(n int) []models.Order {
+	orders := make([]models.Order, n)
+	for i := range orders {
+		orders[i] = *generator.GenerateOrder()
+	}
+	return orders
+}
+
+func benchRepo(b *testing.B) *PostgresRepo {
+	cfg := config.LoadConfig()
+	return &PostgresRepo{DB: db.NewDB(cfg)}
+}
+
+func benchmarkCreateOrder(b *testing.B, n int) {
+	repo := benchRepo(b)
+	defer repo.Close()
+	ctx := context.Background()
+
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		orders := benchOrders(n)
+		b.StartTimer()
+		for _, order := range orders {
+			if err := repo.CreateOrder(ctx, order); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func benchmarkCreateOrders(b *testing.B, n, batchSize int) {
+	repo := benchRepo(b)
+	defer repo.Close()
+	ctx := context.Background()
+
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		orders := benchOrders(n)
+		b.StartTimer()
+		for start := 0; start < n; start += batchSize {
+			end := start + batchSize
+			if end > n {
+				end = n
+			}
+			if err := repo.CreateOrders(ctx, orders[start:end]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkCreateOrder_1k(b *testing.B)  { benchmarkCreateOrder(b, 1_000) }
+func BenchmarkCreateOrder_10k(b *testing.B) { benchmarkCreateOrder(b, 10_000) }
+
+func BenchmarkCreateOrders_1k(b *testing.B)  { benchmarkCreateOrders(b, 1_000, 500) }
+func BenchmarkCreateOrders_10k(b *testing.B) { benchmarkCreateOrders(b, 10_000, 500) }
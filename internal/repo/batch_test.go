@@ -0,0 +1,195 @@
+package repo
+
+import (
+	"L0-wb/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func batchTestOrder(uid string, items int) models.Order {
+	order := models.Order{
+		OrderUID:    uid,
+		TrackNumber: "track-" + uid,
+		Entry:       "WBIL",
+		Delivery:    models.Delivery{Name: "Test User", Phone: "+79991234567"},
+		Payment:     models.Payment{Transaction: "tx-" + uid, Provider: "stripe", Amount: 100},
+		DateCreated: time.Now(),
+	}
+	for i := 0; i < items; i++ {
+		order.Items = append(order.Items, models.Item{Name: fmt.Sprintf("Item %d", i), Price: 100, TotalPrice: 100})
+	}
+	return order
+}
+
+func idRows(ids ...int) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"nextval"})
+	for _, id := range ids {
+		rows.AddRow(id)
+	}
+	return rows
+}
+
+func TestCreateOrders(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &PostgresRepo{DB: db, outbox: true}
+	ctx := context.Background()
+	orders := []models.Order{batchTestOrder("a", 2), batchTestOrder("b", 1)}
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT nextval").WithArgs("delivery_id_seq", 2).WillReturnRows(idRows(10, 11))
+		mock.ExpectQuery("SELECT nextval").WithArgs("payment_id_seq", 2).WillReturnRows(idRows(20, 21))
+		mock.ExpectExec(`INSERT INTO delivery .* VALUES \(\$1,.*\),\(\$9,`).WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(`INSERT INTO payment .* VALUES \(\$1,.*\),\(\$12,`).WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(`INSERT INTO orders`).WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(`INSERT INTO item .* VALUES \(\$1,.*\),\(\$13,.*\),\(\$25,`).WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectExec(`INSERT INTO outbox .* VALUES \(\$1,\$2,\$3\),\(\$4,\$5,\$6\)`).
+			WithArgs(models.EventOrderSaved, "a", sqlmock.AnyArg(), models.EventOrderSaved, "b", sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		require.NoError(t, repo.CreateOrders(ctx, orders))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("webhook deliveries in the same transaction", func(t *testing.T) {
+		repo := &PostgresRepo{DB: db, outbox: true, webhooks: true}
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT nextval").WillReturnRows(idRows(14, 15))
+		mock.ExpectQuery("SELECT nextval").WillReturnRows(idRows(24, 25))
+		mock.ExpectExec("INSERT INTO delivery").WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec("INSERT INTO payment").WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec("INSERT INTO orders").WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec("INSERT INTO item").WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectExec("INSERT INTO outbox").WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec("INSERT INTO webhook_deliveries").WillReturnError(errors.New("db down"))
+		mock.ExpectRollback()
+
+		// без очереди вебхуков заказы не сохраняются
+		assert.Error(t, repo.CreateOrders(ctx, orders))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rollback on error", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT nextval").WillReturnRows(idRows(12, 13))
+		mock.ExpectQuery("SELECT nextval").WillReturnRows(idRows(22, 23))
+		mock.ExpectExec("INSERT INTO delivery").WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec("INSERT INTO payment").WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec("INSERT INTO orders").WillReturnError(errors.New("duplicate key"))
+		mock.ExpectRollback()
+
+		err := repo.CreateOrders(ctx, orders)
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		err := repo.CreateOrders(ctx, []models.Order{batchTestOrder("c", 1), {OrderUID: "bad"}})
+		assert.Error(t, err)
+	})
+
+	t.Run("empty batch", func(t *testing.T) {
+		assert.NoError(t, repo.CreateOrders(ctx, nil))
+	})
+}
+
+func TestInsertRows_ChunksByParams(t *testing.T) {
+	var chunks []int
+	matcher := sqlmock.QueryMatcherFunc(func(_, actual string) error {
+		chunks = append(chunks, strings.Count(actual, "),(")+1)
+		return nil
+	})
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(matcher))
+	require.NoError(t, err)
+	defer db.Close()
+
+	// 16384 колонки - в запрос помещаются 3 строки
+	const cols = 16384
+	mock.ExpectBegin()
+	for i := 0; i < 3; i++ {
+		mock.ExpectExec("INSERT").WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	tx, err := db.Begin()
+	require.NoError(t, err)
+
+	err = insertRows(context.Background(), tx, "INSERT INTO t VALUES ", cols, 7, func(args []interface{}, i int) []interface{} {
+		return append(args, make([]interface{}, cols)...)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int{3, 3, 1}, chunks)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestValuesPlaceholders(t *testing.T) {
+	assert.Equal(t, "($1,$2),($3,$4),($5,$6)", valuesPlaceholders(3, 2))
+	assert.Equal(t, "($1)", valuesPlaceholders(1, 1))
+}
+
+func TestProcessOutbox(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &PostgresRepo{DB: db}
+	ctx := context.Background()
+	columns := []string{"id", "event_type", "aggregate_id", "payload", "created_at"}
+
+	t.Run("published rows are deleted", func(t *testing.T) {
+		// публикация идёт между двумя короткими запросами, без открытой транзакции
+		mock.ExpectQuery(`UPDATE outbox SET locked_until (.+) FOR UPDATE SKIP LOCKED`).WithArgs(10, int64(60000)).WillReturnRows(
+			sqlmock.NewRows(columns).
+				AddRow(2, models.EventOrderSaved, "b", []byte(`{}`), time.Now()).
+				AddRow(1, models.EventOrderSaved, "a", []byte(`{}`), time.Now()))
+		mock.ExpectExec(`DELETE FROM outbox WHERE id = ANY\(\$1\)`).WithArgs(pq.Array([]int64{1, 2})).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		var published []models.OutboxMessage
+		n, err := repo.ProcessOutbox(ctx, 10, time.Minute, func(messages []models.OutboxMessage) error {
+			published = messages
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 2, n)
+		require.Len(t, published, 2)
+		assert.Equal(t, int64(1), published[0].ID, "события публикуются по порядку id")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("publish error releases lease", func(t *testing.T) {
+		mock.ExpectQuery(`UPDATE outbox SET locked_until`).WillReturnRows(
+			sqlmock.NewRows(columns).AddRow(3, models.EventOrderSaved, "c", []byte(`{}`), time.Now()))
+		mock.ExpectExec(`UPDATE outbox SET locked_until = NULL WHERE id = ANY\(\$1\)`).WithArgs(pq.Array([]int64{3})).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		_, err := repo.ProcessOutbox(ctx, 10, time.Minute, func([]models.OutboxMessage) error {
+			return errors.New("broker unavailable")
+		})
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("nothing to publish", func(t *testing.T) {
+		mock.ExpectQuery(`UPDATE outbox SET locked_until`).WillReturnRows(sqlmock.NewRows(columns))
+
+		n, err := repo.ProcessOutbox(ctx, 10, time.Minute, func([]models.OutboxMessage) error {
+			t.Fatal("publish без записей")
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Zero(t, n)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,180 @@
+package repo
+
+import (
+	"L0-wb/internal/models"
+	"context"
+	"fmt"
+	"strings"
+)
+
+const orderColumns = `order_uid, track_number, entry, delivery_id, payment_id, locale, internal_signature, customer_id, delivery_service, shardkey, sm_id, date_created, oof_shard`
+
+// streamPageSize - сколько заказов StreamOrders держит в памяти за раз
+var streamPageSize = 500
+
+// ListOrders возвращает заказы по фильтру, новые первыми
+func (pgs *PostgresRepo) ListOrders(ctx context.Context, filter models.OrderFilter) ([]models.Order, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = models.DefaultListLimit
+	}
+	return pgs.listOrders(ctx, filter, nil)
+}
+
+// StreamOrders передаёт в fn все заказы по фильтру (Limit <= 0 - без ограничения)
+// в порядке ListOrders. Заказы читаются страницами по ключу (date_created, order_uid),
+// поэтому память не растёт с размером выборки, а вставки во время обхода не сдвигают страницы.
+func (pgs *PostgresRepo) StreamOrders(ctx context.Context, filter models.OrderFilter, fn func(*models.Order) error) error {
+	remaining := filter.Limit
+	page := filter
+	var after *models.Order
+	for {
+		page.Limit = streamPageSize
+		if remaining > 0 && remaining < page.Limit {
+			page.Limit = remaining
+		}
+		orders, err := pgs.listOrders(ctx, page, after)
+		if err != nil {
+			return err
+		}
+		for i := range orders {
+			if err := fn(&orders[i]); err != nil {
+				return err
+			}
+		}
+		if remaining > 0 {
+			if remaining -= len(orders); remaining <= 0 {
+				return nil
+			}
+		}
+		if len(orders) < page.Limit {
+			return nil
+		}
+		last := orders[len(orders)-1]
+		after = &models.Order{OrderUID: last.OrderUID, DateCreated: last.DateCreated}
+		page.Offset = 0
+	}
+}
+
+// ListOrderHeaders возвращает заказы по фильтру без доставки, оплаты и товаров:
+// их догружает GetOrderParts одним запросом на всю страницу
+func (pgs *PostgresRepo) ListOrderHeaders(ctx context.Context, filter models.OrderFilter) ([]models.Order, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = models.DefaultListLimit
+	}
+	refs, err := pgs.listOrderRefs(ctx, filter, nil)
+	if err != nil {
+		return nil, err
+	}
+	orders := make([]models.Order, len(refs))
+	for i, ref := range refs {
+		orders[i] = ref.order
+	}
+	return orders, nil
+}
+
+type orderRef struct {
+	order                 models.Order
+	deliveryID, paymentID int
+}
+
+// listOrders читает страницу заказов; after - последний заказ предыдущей страницы.
+// Доставка, оплата и товары всей страницы догружаются GetOrderParts - по запросу на таблицу.
+func (pgs *PostgresRepo) listOrders(ctx context.Context, filter models.OrderFilter, after *models.Order) ([]models.Order, error) {
+	refs, err := pgs.listOrderRefs(ctx, filter, after)
+	if err != nil || len(refs) == 0 {
+		return nil, err
+	}
+
+	uids := make([]string, len(refs))
+	for i, ref := range refs {
+		uids[i] = ref.order.OrderUID
+	}
+	parts, err := pgs.GetOrderParts(ctx, uids, models.OrderParts{Delivery: true, Payment: true, Items: true})
+	if err != nil {
+		return nil, err
+	}
+
+	orders := make([]models.Order, len(refs))
+	for i, ref := range refs {
+		orders[i] = ref.order
+		p := parts[ref.order.OrderUID]
+		orders[i].Delivery, orders[i].Payment, orders[i].Items = p.Delivery, p.Payment, p.Items
+	}
+	return orders, nil
+}
+
+// listOrderRefs читает строки orders страницы вместе со ссылками на доставку и оплату
+func (pgs *PostgresRepo) listOrderRefs(ctx context.Context, filter models.OrderFilter, after *models.Order) ([]orderRef, error) {
+	where, args := filterConditions(filter)
+	if after != nil {
+		args = append(args, after.DateCreated, after.OrderUID)
+		cond := fmt.Sprintf("(date_created < $%d OR (date_created = $%d AND order_uid > $%d))", len(args)-1, len(args)-1, len(args))
+		if where == "" {
+			where = " WHERE " + cond
+		} else {
+			where += " AND " + cond
+		}
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query := fmt.Sprintf(`SELECT %s FROM orders%s ORDER BY date_created DESC, order_uid LIMIT $%d OFFSET $%d`,
+		orderColumns, where, len(args)-1, len(args))
+
+	rows, err := pgs.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("order query error: %w", err)
+	}
+	// связанные таблицы читаются после закрытия курсора, чтобы не держать два соединения
+	defer rows.Close()
+
+	var refs []orderRef
+	for rows.Next() {
+		var ref orderRef
+		o := &ref.order
+		err := rows.Scan(&o.OrderUID, &o.TrackNumber, &o.Entry, &ref.deliveryID, &ref.paymentID,
+			&o.Locale, &o.InternalSignature, &o.CustomerID, &o.DeliveryService,
+			&o.Shardkey, &o.SmID, &o.DateCreated, &o.OofShard)
+		if err != nil {
+			return nil, fmt.Errorf("order scanning error: %w", err)
+		}
+		refs = append(refs, ref)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("order iteration error: %w", err)
+	}
+	return refs, nil
+}
+
+// filterConditions строит WHERE с позиционными параметрами начиная с $1
+func filterConditions(f models.OrderFilter) (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if f.CustomerID != "" {
+		add("customer_id = $%d", f.CustomerID)
+	}
+	if f.TrackNumber != "" {
+		add("track_number = $%d", f.TrackNumber)
+	}
+	if f.Locale != "" {
+		add("locale = $%d", f.Locale)
+	}
+	if f.DeliveryService != "" {
+		add("delivery_service = $%d", f.DeliveryService)
+	}
+	if !f.CreatedFrom.IsZero() {
+		add("date_created >= $%d", f.CreatedFrom)
+	}
+	if !f.CreatedTo.IsZero() {
+		add("date_created < $%d", f.CreatedTo)
+	}
+
+	if len(conds) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
@@ -0,0 +1,141 @@
+package repo
+
+import (
+	"L0-wb/internal/models"
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListOrders(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := &PostgresRepo{DB: db}
+
+	from := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	created := from.Add(time.Hour)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT `+orderColumns+` FROM orders WHERE customer_id = $1 AND date_created >= $2 ORDER BY date_created DESC, order_uid LIMIT $3 OFFSET $4`)).
+		WithArgs("test", from, 10, 20).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"order_uid", "track_number", "entry", "delivery_id", "payment_id", "locale", "internal_signature",
+			"customer_id", "delivery_service", "shardkey", "sm_id", "date_created", "oof_shard",
+		}).AddRow("uid-1", "WBIL1", "WBIL", 1, 2, "ru", "", "test", "meest", "9", 99, created, "1"))
+	// связанные таблицы - по запросу на страницу, а не на заказ
+	mock.ExpectQuery("FROM orders o JOIN delivery d").WithArgs(pq.Array([]string{"uid-1"})).
+		WillReturnRows(sqlmock.NewRows([]string{"order_uid", "name", "phone", "zip", "city", "address", "region", "email"}).
+			AddRow("uid-1", "Test", "+79991234567", "123", "Moscow", "Street", "Moscow", "t@example.com"))
+	mock.ExpectQuery("FROM orders o JOIN payment p").WithArgs(pq.Array([]string{"uid-1"})).
+		WillReturnRows(sqlmock.NewRows([]string{"order_uid", "transaction", "request_id", "currency", "provider", "amount", "payment_dt", "bank", "delivery_cost", "goods_total", "custom_fee"}).
+			AddRow("uid-1", "tx-1", "", "RUB", "wbpay", 100, 1, "Sber", 0, 100, 0))
+	mock.ExpectQuery("FROM item WHERE order_uid = ANY").WithArgs(pq.Array([]string{"uid-1"})).
+		WillReturnRows(sqlmock.NewRows([]string{"order_uid", "chrt_id", "track_number", "price", "rid", "name", "sale", "size", "total_price", "nm_id", "brand", "status"}).
+			AddRow("uid-1", 1, "WBIL1", 100, "rid", "Item", 0, "0", 100, 1, "Brand", 200))
+
+	orders, err := repo.ListOrders(context.Background(), models.OrderFilter{
+		CustomerID:  "test",
+		CreatedFrom: from,
+		Limit:       10,
+		Offset:      20,
+	})
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	assert.Equal(t, "uid-1", orders[0].OrderUID)
+	assert.Equal(t, "Moscow", orders[0].Delivery.City)
+	assert.Equal(t, "tx-1", orders[0].Payment.Transaction)
+	assert.Len(t, orders[0].Items, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFilterConditions(t *testing.T) {
+	where, args := filterConditions(models.OrderFilter{})
+	assert.Empty(t, where)
+	assert.Empty(t, args)
+
+	to := time.Date(2025, 9, 2, 0, 0, 0, 0, time.UTC)
+	where, args = filterConditions(models.OrderFilter{TrackNumber: "WBIL1", Locale: "en", DeliveryService: "UPS", CreatedTo: to})
+	assert.Equal(t, " WHERE track_number = $1 AND locale = $2 AND delivery_service = $3 AND date_created < $4", where)
+	assert.Equal(t, []interface{}{"WBIL1", "en", "UPS", to}, args)
+}
+
+func expectOrderDetails(mock sqlmock.Sqlmock, uids ...string) {
+	mock.ExpectQuery("FROM orders o JOIN delivery d").WithArgs(pq.Array(uids)).
+		WillReturnRows(sqlmock.NewRows([]string{"order_uid", "name", "phone", "zip", "city", "address", "region", "email"}))
+	mock.ExpectQuery("FROM orders o JOIN payment p").WithArgs(pq.Array(uids)).
+		WillReturnRows(sqlmock.NewRows([]string{"order_uid", "transaction", "request_id", "currency", "provider", "amount", "payment_dt", "bank", "delivery_cost", "goods_total", "custom_fee"}))
+	mock.ExpectQuery("FROM item WHERE order_uid = ANY").WithArgs(pq.Array(uids)).
+		WillReturnRows(sqlmock.NewRows([]string{"order_uid", "chrt_id", "track_number", "price", "rid", "name", "sale", "size", "total_price", "nm_id", "brand", "status"}))
+}
+
+func orderRows(uid string, created time.Time) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{
+		"order_uid", "track_number", "entry", "delivery_id", "payment_id", "locale", "internal_signature",
+		"customer_id", "delivery_service", "shardkey", "sm_id", "date_created", "oof_shard",
+	}).AddRow(uid, "WBIL1", "WBIL", 1, 2, "ru", "", "test", "meest", "9", 99, created, "1")
+}
+
+func TestStreamOrders(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := &PostgresRepo{DB: db}
+
+	defer func(n int) { streamPageSize = n }(streamPageSize)
+	streamPageSize = 1
+
+	first := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	second := first.Add(-time.Hour)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM orders WHERE locale = $1 ORDER BY date_created DESC, order_uid LIMIT $2 OFFSET $3`)).
+		WithArgs("ru", 1, 0).WillReturnRows(orderRows("uid-1", first))
+	expectOrderDetails(mock, "uid-1")
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM orders WHERE locale = $1 AND (date_created < $2 OR (date_created = $2 AND order_uid > $3)) ORDER BY date_created DESC, order_uid LIMIT $4 OFFSET $5`)).
+		WithArgs("ru", first, "uid-1", 1, 0).WillReturnRows(orderRows("uid-2", second))
+	expectOrderDetails(mock, "uid-2")
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM orders WHERE locale = $1 AND (date_created < $2`)).
+		WithArgs("ru", second, "uid-2", 1, 0).WillReturnRows(sqlmock.NewRows([]string{"order_uid"}))
+
+	var uids []string
+	err = repo.StreamOrders(context.Background(), models.OrderFilter{Locale: "ru"}, func(o *models.Order) error {
+		uids = append(uids, o.OrderUID)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"uid-1", "uid-2"}, uids)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStreamOrders_LimitAndCallbackError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := &PostgresRepo{DB: db}
+
+	created := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM orders ORDER BY date_created DESC, order_uid LIMIT $1 OFFSET $2`)).
+		WithArgs(1, 0).WillReturnRows(orderRows("uid-1", created))
+	expectOrderDetails(mock, "uid-1")
+
+	calls := 0
+	err = repo.StreamOrders(context.Background(), models.OrderFilter{Limit: 1}, func(o *models.Order) error {
+		calls++
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+
+	mock.ExpectQuery("FROM orders").WillReturnRows(orderRows("uid-1", created))
+	expectOrderDetails(mock, "uid-1")
+	err = repo.StreamOrders(context.Background(), models.OrderFilter{}, func(o *models.Order) error {
+		return assert.AnError
+	})
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
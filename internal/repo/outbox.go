@@ -0,0 +1,105 @@
+package repo
+
+import (
+	"L0-wb/internal/models"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// CreateOutboxTx записывает события в outbox в рамках транзакции заказа
+func (pgs *PostgresRepo) CreateOutboxTx(ctx context.Context, tx *sql.Tx, events ...models.OrderEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	payloads := make([][]byte, len(events))
+	for i, e := range events {
+		payload, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("marshal %s event for %s: %w", e.EventType, e.OrderUID, err)
+		}
+		payloads[i] = payload
+	}
+
+	return insertRows(ctx, tx, `INSERT INTO outbox (event_type, aggregate_id, payload) VALUES `,
+		3, len(events), func(args []interface{}, i int) []interface{} {
+			return append(args, events[i].EventType, events[i].OrderUID, payloads[i])
+		})
+}
+
+// ProcessOutbox забирает до limit самых старых записей outbox, откладывая их на lease,
+// и передаёт в publish вне транзакции, чтобы строки не были заблокированы на время
+// записи в брокер. Опубликованные записи удаляются. Если publish вернул ошибку,
+// аренда снимается и записи будут отправлены повторно (at-least-once); если реле
+// упало между публикацией и удалением, записи повторятся после lease.
+// SKIP LOCKED и аренда позволяют запускать несколько реле параллельно.
+func (pgs *PostgresRepo) ProcessOutbox(ctx context.Context, limit int, lease time.Duration, publish func([]models.OutboxMessage) error) (int, error) {
+	messages, err := pgs.claimOutbox(ctx, limit, lease)
+	if err != nil {
+		return 0, fmt.Errorf("outbox claim error: %w", err)
+	}
+	if len(messages) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]int64, len(messages))
+	for i, m := range messages {
+		ids[i] = m.ID
+	}
+	if err := publish(messages); err != nil {
+		// не ждём конца аренды; если снять её не удалось, записи повторятся после lease
+		_, _ = pgs.DB.ExecContext(ctx, `UPDATE outbox SET locked_until = NULL WHERE id = ANY($1)`, pq.Array(ids))
+		return 0, fmt.Errorf("outbox publish error: %w", err)
+	}
+
+	if _, err := pgs.DB.ExecContext(ctx, `DELETE FROM outbox WHERE id = ANY($1)`, pq.Array(ids)); err != nil {
+		return 0, fmt.Errorf("outbox cleanup error: %w", err)
+	}
+	return len(messages), nil
+}
+
+// claimOutbox одним запросом (короткой транзакцией) арендует записи, которые
+// никто не публикует или чья аренда истекла
+func (pgs *PostgresRepo) claimOutbox(ctx context.Context, limit int, lease time.Duration) ([]models.OutboxMessage, error) {
+	query := `UPDATE outbox SET locked_until = NOW() + $2 * INTERVAL '1 millisecond'
+		WHERE id IN (
+			SELECT id FROM outbox WHERE locked_until IS NULL OR locked_until <= NOW()
+			ORDER BY id LIMIT $1 FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, event_type, aggregate_id, payload, created_at`
+	rows, err := pgs.DB.QueryContext(ctx, query, limit, lease.Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []models.OutboxMessage
+	for rows.Next() {
+		var m models.OutboxMessage
+		if err := rows.Scan(&m.ID, &m.EventType, &m.AggregateID, &m.Payload, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		messages = append(messages, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// RETURNING не сохраняет порядок подзапроса
+	sort.Slice(messages, func(i, j int) bool { return messages[i].ID < messages[j].ID })
+	return messages, nil
+}
+
+func orderSavedEvents(orders []models.Order) []models.OrderEvent {
+	now := time.Now().UTC()
+	events := make([]models.OrderEvent, len(orders))
+	for i := range orders {
+		events[i] = models.NewOrderSavedEvent(&orders[i], now)
+	}
+	return events
+}
@@ -0,0 +1,150 @@
+package repo
+
+import (
+	"L0-wb/internal/models"
+	"context"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// GetOrderParts догружает выбранные части заказов: не больше одного запроса
+// на каждую часть независимо от числа заказов. Заказы без данных в таблице
+// части получают пустое значение.
+func (pgs *PostgresRepo) GetOrderParts(ctx context.Context, uids []string, parts models.OrderParts) (map[string]models.Order, error) {
+	orders := make(map[string]models.Order, len(uids))
+	if len(uids) == 0 {
+		return orders, nil
+	}
+	for _, uid := range uids {
+		orders[uid] = models.Order{OrderUID: uid}
+	}
+
+	if parts.Delivery {
+		query := `SELECT o.order_uid, d.name, d.phone, d.zip, d.city, d.address, d.region, d.email
+			FROM orders o JOIN delivery d ON d.id = o.delivery_id WHERE o.order_uid = ANY($1)`
+		err := pgs.scanParts(ctx, query, uids, func(scan func(...interface{}) error) error {
+			var uid string
+			var d models.Delivery
+			if err := scan(&uid, &d.Name, &d.Phone, &d.Zip, &d.City, &d.Address, &d.Region, &d.Email); err != nil {
+				return err
+			}
+			o := orders[uid]
+			o.Delivery = d
+			orders[uid] = o
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("delivery data retrieval error: %w", err)
+		}
+	}
+
+	if parts.Payment {
+		query := `SELECT o.order_uid, p.transaction, p.request_id, p.currency, p.provider, p.amount,
+			p.payment_dt, p.bank, p.delivery_cost, p.goods_total, p.custom_fee
+			FROM orders o JOIN payment p ON p.id = o.payment_id WHERE o.order_uid = ANY($1)`
+		err := pgs.scanParts(ctx, query, uids, func(scan func(...interface{}) error) error {
+			var uid string
+			var p models.Payment
+			if err := scan(&uid, &p.Transaction, &p.RequestID, &p.Currency, &p.Provider, &p.Amount,
+				&p.PaymentDt, &p.Bank, &p.DeliveryCost, &p.GoodsTotal, &p.CustomFee); err != nil {
+				return err
+			}
+			o := orders[uid]
+			o.Payment = p
+			orders[uid] = o
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("payment data retrieval error: %w", err)
+		}
+	}
+
+	if parts.Items {
+		query := `SELECT order_uid, chrt_id, track_number, price, rid, name, sale, size, total_price, nm_id, brand, status
+			FROM item WHERE order_uid = ANY($1) ORDER BY order_uid, id`
+		err := pgs.scanParts(ctx, query, uids, func(scan func(...interface{}) error) error {
+			var uid string
+			var it models.Item
+			if err := scan(&uid, &it.ChrtID, &it.TrackNumber, &it.Price, &it.Rid, &it.Name,
+				&it.Sale, &it.Size, &it.TotalPrice, &it.NmID, &it.Brand, &it.Status); err != nil {
+				return err
+			}
+			o := orders[uid]
+			o.Items = append(o.Items, it)
+			orders[uid] = o
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("items retrieval error: %w", err)
+		}
+	}
+	return orders, nil
+}
+
+func (pgs *PostgresRepo) scanParts(ctx context.Context, query string, uids []string, fn func(scan func(...interface{}) error) error) error {
+	rows, err := pgs.DB.QueryContext(ctx, query, pq.Array(uids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := fn(rows.Scan); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// GetOrders возвращает полные заказы по списку order_uid: заказы с доставкой
+// и оплатой одним запросом, товары - вторым. Ненайденные order_uid пропускаются,
+// порядок результата не определён.
+func (pgs *PostgresRepo) GetOrders(ctx context.Context, uids []string) ([]models.Order, error) {
+	if len(uids) == 0 {
+		return nil, nil
+	}
+
+	query := `SELECT o.order_uid, o.track_number, o.entry, o.locale, o.internal_signature, o.customer_id,
+			o.delivery_service, o.shardkey, o.sm_id, o.date_created, o.oof_shard,
+			d.name, d.phone, d.zip, d.city, d.address, d.region, d.email,
+			p.transaction, p.request_id, p.currency, p.provider, p.amount,
+			p.payment_dt, p.bank, p.delivery_cost, p.goods_total, p.custom_fee
+		FROM orders o
+		JOIN delivery d ON d.id = o.delivery_id
+		JOIN payment p ON p.id = o.payment_id
+		WHERE o.order_uid = ANY($1)`
+	var orders []models.Order
+	err := pgs.scanParts(ctx, query, uids, func(scan func(...interface{}) error) error {
+		var o models.Order
+		d, p := &o.Delivery, &o.Payment
+		if err := scan(&o.OrderUID, &o.TrackNumber, &o.Entry, &o.Locale, &o.InternalSignature, &o.CustomerID,
+			&o.DeliveryService, &o.Shardkey, &o.SmID, &o.DateCreated, &o.OofShard,
+			&d.Name, &d.Phone, &d.Zip, &d.City, &d.Address, &d.Region, &d.Email,
+			&p.Transaction, &p.RequestID, &p.Currency, &p.Provider, &p.Amount,
+			&p.PaymentDt, &p.Bank, &p.DeliveryCost, &p.GoodsTotal, &p.CustomFee); err != nil {
+			return err
+		}
+		orders = append(orders, o)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("order retrieval error: %w", err)
+	}
+	if len(orders) == 0 {
+		return nil, nil
+	}
+
+	found := make([]string, len(orders))
+	for i, o := range orders {
+		found[i] = o.OrderUID
+	}
+	items, err := pgs.GetOrderParts(ctx, found, models.OrderParts{Items: true})
+	if err != nil {
+		return nil, err
+	}
+	for i := range orders {
+		orders[i].Items = items[orders[i].OrderUID].Items
+	}
+	return orders, nil
+}
@@ -0,0 +1,128 @@
+package repo
+
+import (
+	"L0-wb/internal/models"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetOrderParts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := &PostgresRepo{DB: db}
+
+	uids := []string{"a", "b"}
+	mock.ExpectQuery("FROM orders o JOIN delivery d ON d.id = o.delivery_id WHERE o.order_uid = ANY\\(\\$1\\)").
+		WithArgs(pq.Array(uids)).
+		WillReturnRows(sqlmock.NewRows([]string{"order_uid", "name", "phone", "zip", "city", "address", "region", "email"}).
+			AddRow("a", "Test", "+7", "1", "Moscow", "Lenina 1", "MSK", "a@b.c").
+			AddRow("b", "Other", "+7", "2", "Kazan", "Baumana 2", "TAT", "b@b.c"))
+	mock.ExpectQuery("FROM item WHERE order_uid = ANY\\(\\$1\\) ORDER BY order_uid, id").
+		WithArgs(pq.Array(uids)).
+		WillReturnRows(sqlmock.NewRows([]string{"order_uid", "chrt_id", "track_number", "price", "rid", "name", "sale", "size", "total_price", "nm_id", "brand", "status"}).
+			AddRow("a", 1, "T", 100, "r1", "Shoes", 0, "42", 100, 10, "NIKE", 202).
+			AddRow("a", 2, "T", 50, "r2", "Socks", 0, "0", 50, 11, "NIKE", 202))
+
+	orders, err := repo.GetOrderParts(context.Background(), uids, models.OrderParts{Delivery: true, Items: true})
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	assert.Equal(t, "Moscow", orders["a"].Delivery.City)
+	assert.Equal(t, "Kazan", orders["b"].Delivery.City)
+	require.Len(t, orders["a"].Items, 2)
+	assert.Equal(t, "Socks", orders["a"].Items[1].Name)
+	assert.Empty(t, orders["b"].Items)
+	assert.Equal(t, models.Payment{}, orders["a"].Payment)
+}
+
+func TestGetOrderParts_Empty(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	orders, err := (&PostgresRepo{DB: db}).GetOrderParts(context.Background(), nil, models.OrderParts{Delivery: true})
+	require.NoError(t, err)
+	assert.Empty(t, orders)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListOrderHeaders(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := &PostgresRepo{DB: db}
+
+	created := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("SELECT .* FROM orders WHERE delivery_service = \\$1 ORDER BY date_created DESC, order_uid LIMIT \\$2 OFFSET \\$3").
+		WithArgs("meest", models.DefaultListLimit, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"order_uid", "track_number", "entry", "delivery_id", "payment_id", "locale",
+			"internal_signature", "customer_id", "delivery_service", "shardkey", "sm_id", "date_created", "oof_shard"}).
+			AddRow("a", "T", "WBIL", 1, 1, "en", "", "c1", "meest", "9", 99, created, "1"))
+
+	// связанные таблицы не читаются
+	orders, err := repo.ListOrderHeaders(context.Background(), models.OrderFilter{DeliveryService: "meest"})
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Len(t, orders, 1)
+	assert.Equal(t, "a", orders[0].OrderUID)
+	assert.Equal(t, created, orders[0].DateCreated)
+}
+
+func TestGetOrders(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := &PostgresRepo{DB: db}
+
+	created := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	uids := []string{"a", "missing"}
+	mock.ExpectQuery("FROM orders o\\s+JOIN delivery d ON d.id = o.delivery_id\\s+JOIN payment p ON p.id = o.payment_id\\s+WHERE o.order_uid = ANY\\(\\$1\\)").
+		WithArgs(pq.Array(uids)).
+		WillReturnRows(sqlmock.NewRows([]string{"order_uid", "track_number", "entry", "locale", "internal_signature",
+			"customer_id", "delivery_service", "shardkey", "sm_id", "date_created", "oof_shard",
+			"name", "phone", "zip", "city", "address", "region", "email",
+			"transaction", "request_id", "currency", "provider", "amount",
+			"payment_dt", "bank", "delivery_cost", "goods_total", "custom_fee"}).
+			AddRow("a", "T", "WBIL", "en", "", "c1", "meest", "9", 99, created, "1",
+				"Test", "+7", "1", "Moscow", "Lenina 1", "MSK", "a@b.c",
+				"a", "", "USD", "wbpay", 1817, 1637907727, "alpha", 1500, 317, 0))
+	// товары читаются только для найденных заказов
+	mock.ExpectQuery("FROM item WHERE order_uid = ANY\\(\\$1\\) ORDER BY order_uid, id").
+		WithArgs(pq.Array([]string{"a"})).
+		WillReturnRows(sqlmock.NewRows([]string{"order_uid", "chrt_id", "track_number", "price", "rid", "name", "sale", "size", "total_price", "nm_id", "brand", "status"}).
+			AddRow("a", 1, "T", 100, "r1", "Shoes", 0, "42", 100, 10, "NIKE", 202))
+
+	orders, err := repo.GetOrders(context.Background(), uids)
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	require.Len(t, orders, 1)
+	assert.Equal(t, "a", orders[0].OrderUID)
+	assert.Equal(t, created, orders[0].DateCreated)
+	assert.Equal(t, "Moscow", orders[0].Delivery.City)
+	assert.Equal(t, 1817, orders[0].Payment.Amount)
+	require.Len(t, orders[0].Items, 1)
+	assert.Equal(t, "Shoes", orders[0].Items[0].Name)
+}
+
+func TestGetOrders_NoneFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("WHERE o.order_uid = ANY\\(\\$1\\)").
+		WithArgs(pq.Array([]string{"x"})).
+		WillReturnRows(sqlmock.NewRows([]string{"order_uid"}))
+
+	orders, err := (&PostgresRepo{DB: db}).GetOrders(context.Background(), []string{"x"})
+	require.NoError(t, err)
+	assert.Empty(t, orders)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -57,12 +57,26 @@ func (pgs *PostgresRepo) CreateOrder(ctx context.Context, order models.Order) er
 
 	// Вставляем все items
 	for i, item := range order.Items {
-		_, err := pgs.CreateItemTx(ctx, tx, item, order.OrderUID)
+		_, err = pgs.CreateItemTx(ctx, tx, item, order.OrderUID)
 		if err != nil {
 			return fmt.Errorf("item %d creation error: %w", i+1, err)
 		}
 	}
 
+	// Событие order.saved публикуется реле и уходит подписчикам вебхуков
+	// только после коммита заказа
+	events := orderSavedEvents([]models.Order{order})
+	if pgs.outbox {
+		if err = pgs.CreateOutboxTx(ctx, tx, events...); err != nil {
+			return fmt.Errorf("outbox creation error: %w", err)
+		}
+	}
+	if pgs.webhooks {
+		if _, err = pgs.EnqueueWebhooksTx(ctx, tx, events...); err != nil {
+			return fmt.Errorf("webhook deliveries creation error: %w", err)
+		}
+	}
+
 	// Коммитим транзакцию
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
@@ -71,13 +85,47 @@ func (pgs *PostgresRepo) CreateOrder(ctx context.Context, order models.Order) er
 	return nil
 }
 
-// Create запросы для транзакции CreateOrder
-func (pgs *PostgresRepo) CreateDeliveryTx(ctx context.Context, tx *sql.Tx, del models.Delivery) (int, error) {
+// Проверки перед вставкой; общие для CreateOrder и пачечной вставки
+func checkDelivery(del models.Delivery) error {
 	if del.Name == "" {
-		return 0, fmt.Errorf(" recipient name is required")
+		return fmt.Errorf(" recipient name is required")
 	}
 	if del.Phone == "" {
-		return 0, fmt.Errorf(" recipient phone is required")
+		return fmt.Errorf(" recipient phone is required")
+	}
+	return nil
+}
+
+func checkPayment(pay models.Payment) error {
+	if pay.Transaction == "" {
+		return fmt.Errorf(" transaction number is required")
+	}
+	if pay.Provider == "" {
+		return fmt.Errorf(" payment provider is required")
+	}
+	if pay.Amount <= 0 {
+		return fmt.Errorf(" payment amount must be greater than zero")
+	}
+	return nil
+}
+
+func checkItem(item models.Item, orderUID string) error {
+	if item.Name == "" {
+		return fmt.Errorf(" item name is required")
+	}
+	if item.Price <= 0 {
+		return fmt.Errorf(" item price must be greater than zero")
+	}
+	if orderUID == "" {
+		return fmt.Errorf(" item order_uid is required")
+	}
+	return nil
+}
+
+// Create запросы для транзакции CreateOrder
+func (pgs *PostgresRepo) CreateDeliveryTx(ctx context.Context, tx *sql.Tx, del models.Delivery) (int, error) {
+	if err := checkDelivery(del); err != nil {
+		return 0, err
 	}
 
 	query := `INSERT INTO delivery (name, phone, zip, city, address, region, email) 
@@ -93,14 +141,8 @@ func (pgs *PostgresRepo) CreateDeliveryTx(ctx context.Context, tx *sql.Tx, del m
 }
 
 func (pgs *PostgresRepo) CreatePaymentTx(ctx context.Context, tx *sql.Tx, pay models.Payment) (int, error) {
-	if pay.Transaction == "" {
-		return 0, fmt.Errorf(" transaction number is required")
-	}
-	if pay.Provider == "" {
-		return 0, fmt.Errorf(" payment provider is required")
-	}
-	if pay.Amount <= 0 {
-		return 0, fmt.Errorf(" payment amount must be greater than zero")
+	if err := checkPayment(pay); err != nil {
+		return 0, err
 	}
 	query := `INSERT INTO payment (transaction, request_id, currency, provider, amount, payment_dt, bank, delivery_cost, goods_total, custom_fee) 
 	          VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10) RETURNING id`
@@ -115,14 +157,8 @@ func (pgs *PostgresRepo) CreatePaymentTx(ctx context.Context, tx *sql.Tx, pay mo
 }
 
 func (pgs *PostgresRepo) CreateItemTx(ctx context.Context, tx *sql.Tx, item models.Item, orderUID string) (int, error) {
-	if item.Name == "" {
-		return 0, fmt.Errorf(" item name is required")
-	}
-	if item.Price <= 0 {
-		return 0, fmt.Errorf(" item price must be greater than zero")
-	}
-	if orderUID == "" {
-		return 0, fmt.Errorf(" item order_uid is required")
+	if err := checkItem(item, orderUID); err != nil {
+		return 0, err
 	}
 
 	query := `INSERT INTO item (chrt_id, track_number, price, rid, name, sale, size, total_price, nm_id, brand, status, order_uid) 
@@ -225,43 +261,5 @@ func (pgs *PostgresRepo) GetItemsByOrderUID(ctx context.Context, orderUID string
 
 // Получить последние заказы для кэширования(количество задается в .env)
 func (pgs *PostgresRepo) GetLastOrders(ctx context.Context, lim int) ([]models.Order, error) {
-	query := `SELECT order_uid, track_number, entry, delivery_id, payment_id, locale, internal_signature, customer_id, delivery_service, shardkey, sm_id, date_created, oof_shard FROM orders`
-	rows, err := pgs.DB.QueryContext(ctx, query)
-	if err != nil {
-		return nil, fmt.Errorf("order query error: %w", err)
-	}
-	defer rows.Close()
-
-	var orders []models.Order
-	for rows.Next() {
-		var order models.Order
-		var deliveryID, paymentID int
-		err := rows.Scan(&order.OrderUID, &order.TrackNumber, &order.Entry, &deliveryID, &paymentID, &order.Locale, &order.InternalSignature, &order.CustomerID, &order.DeliveryService, &order.Shardkey, &order.SmID, &order.DateCreated, &order.OofShard)
-		if err != nil {
-			return nil, fmt.Errorf("order scanning error: %w", err)
-		}
-
-		order.Delivery, err = pgs.GetDelivery(ctx, deliveryID)
-		if err != nil {
-			return nil, fmt.Errorf("delivery data retrieval error for order %s: %w", order.OrderUID, err)
-		}
-
-		order.Payment, err = pgs.GetPayment(ctx, paymentID)
-		if err != nil {
-			return nil, fmt.Errorf("payment data retrieval error for order %s: %w", order.OrderUID, err)
-		}
-
-		order.Items, err = pgs.GetItemsByOrderUID(ctx, order.OrderUID)
-		if err != nil {
-			return nil, fmt.Errorf("items retrieval error for order %s %w: ", order.OrderUID, err)
-		}
-
-		orders = append(orders, order)
-	}
-
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("order iteration error")
-	}
-
-	return orders, nil
+	return pgs.ListOrders(ctx, models.OrderFilter{Limit: lim})
 }
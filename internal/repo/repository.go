@@ -1,17 +1,24 @@
 package repo
 
 import (
+	"L0-wb/config"
 	"database/sql"
 )
 
 // PostgresRepo содержит *sql.DB и методы для работы с таблицами
 type PostgresRepo struct {
 	DB *sql.DB
+	// outbox - писать ли события сохраняемых заказов в outbox (OUTBOX_ENABLED):
+	// без реле таблица только бы росла
+	outbox bool
+	// webhooks - ставить ли сохраняемые заказы в очередь вебхуков
+	// в той же транзакции (WEBHOOK_ENABLED)
+	webhooks bool
 }
 
 // Конструктор PostgresRepo
 func NewRepo(db *sql.DB) Repository {
-	return &PostgresRepo{DB: db}
+	return &PostgresRepo{DB: db, outbox: config.GetOutboxEnabled(), webhooks: config.GetWebhookEnabled()}
 }
 
 // Закрытие соединения
@@ -4,15 +4,37 @@ import (
 	"L0-wb/internal/models"
 	"context"
 	"database/sql"
+	"time"
 )
 
 type Repository interface {
 	CreateOrder(ctx context.Context, order models.Order) error
+	CreateOrders(ctx context.Context, orders []models.Order) error
+	UpsertOrders(ctx context.Context, orders []models.Order) error
+	ExistingOrderUIDs(ctx context.Context, uids []string) (map[string]bool, error)
 	GetOrder(ctx context.Context, orderUID string) (models.Order, error)
 	GetLastOrders(ctx context.Context, lim int) ([]models.Order, error)
+	ListOrders(ctx context.Context, filter models.OrderFilter) ([]models.Order, error)
+	StreamOrders(ctx context.Context, filter models.OrderFilter, fn func(*models.Order) error) error
+	ListOrderHeaders(ctx context.Context, filter models.OrderFilter) ([]models.Order, error)
+	GetOrderParts(ctx context.Context, uids []string, parts models.OrderParts) (map[string]models.Order, error)
+	GetOrders(ctx context.Context, uids []string) ([]models.Order, error)
+	OrderStats(ctx context.Context, q models.StatsQuery) (models.OrderStats, error)
 	CreateDeliveryTx(ctx context.Context, tx *sql.Tx, del models.Delivery) (int, error)
 	CreatePaymentTx(ctx context.Context, tx *sql.Tx, pay models.Payment) (int, error)
 	CreateItemTx(ctx context.Context, tx *sql.Tx, item models.Item, orderUID string) (int, error)
+	CreateOutboxTx(ctx context.Context, tx *sql.Tx, events ...models.OrderEvent) error
+	ProcessOutbox(ctx context.Context, limit int, lease time.Duration, publish func([]models.OutboxMessage) error) (int, error)
+	CreateWebhook(ctx context.Context, sub *models.WebhookSubscription) error
+	UpdateWebhook(ctx context.Context, sub *models.WebhookSubscription) error
+	DeleteWebhook(ctx context.Context, id int64) error
+	GetWebhook(ctx context.Context, id int64) (models.WebhookSubscription, error)
+	ListWebhooks(ctx context.Context) ([]models.WebhookSubscription, error)
+	ListWebhookDeliveries(ctx context.Context, subscriptionID int64, limit int) ([]models.WebhookDelivery, error)
+	ListOrderWebhookDeliveries(ctx context.Context, orderUID string) ([]models.WebhookDelivery, error)
+	EnqueueWebhooksTx(ctx context.Context, tx *sql.Tx, events ...models.OrderEvent) (int, error)
+	ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookTask, error)
+	FinishWebhookDelivery(ctx context.Context, id int64, res models.WebhookResult) error
 	GetDelivery(ctx context.Context, deliveryID int) (models.Delivery, error)
 	GetPayment(ctx context.Context, paymentID int) (models.Payment, error)
 	GetItemsByOrderUID(ctx context.Context, orderUID string) (models.Items, error)
@@ -30,34 +30,37 @@ func TestGetLastOrders(t *testing.T) {
 		)
 
 		deliveryRows := sqlmock.NewRows([]string{
-			"name", "phone", "zip", "city", "address", "region", "email",
-		}).AddRow("Test User", "+7999999999", "123456", "City", "Address", "Region", "test@test.com")
+			"order_uid", "name", "phone", "zip", "city", "address", "region", "email",
+		}).AddRow("test-123", "Test User", "+7999999999", "123456", "City", "Address", "Region", "test@test.com")
 
 		paymentRows := sqlmock.NewRows([]string{
-			"transaction", "request_id", "currency", "provider", "amount",
+			"order_uid", "transaction", "request_id", "currency", "provider", "amount",
 			"payment_dt", "bank", "delivery_cost", "goods_total", "custom_fee",
 		}).AddRow(
-			"tx-1", "req-1", "USD", "stripe", 100,
+			"test-123", "tx-1", "req-1", "USD", "stripe", 100,
 			time.Now().Unix(), "bank1", 10, 90, 0,
 		)
 
 		itemRows := sqlmock.NewRows([]string{
-			"chrt_id", "track_number", "price", "rid", "name",
+			"order_uid", "chrt_id", "track_number", "price", "rid", "name",
 			"sale", "size", "total_price", "nm_id", "brand", "status",
 		}).AddRow(
-			1, "track1", 100, "rid1", "Item 1",
+			"test-123", 1, "track1", 100, "rid1", "Item 1",
 			0, "M", 100, 1, "Brand", 200,
 		)
 
 		mock.ExpectQuery("SELECT (.+) FROM orders").WillReturnRows(orderRows)
-		mock.ExpectQuery("SELECT (.+) FROM delivery").WillReturnRows(deliveryRows)
-		mock.ExpectQuery("SELECT (.+) FROM payment").WillReturnRows(paymentRows)
+		mock.ExpectQuery("SELECT (.+) FROM orders o JOIN delivery").WillReturnRows(deliveryRows)
+		mock.ExpectQuery("SELECT (.+) FROM orders o JOIN payment").WillReturnRows(paymentRows)
 		mock.ExpectQuery("SELECT (.+) FROM item").WillReturnRows(itemRows)
 
 		orders, err := repo.GetLastOrders(ctx, 10)
 		assert.NoError(t, err)
 		assert.Len(t, orders, 1)
 		assert.Equal(t, "test-123", orders[0].OrderUID)
+		assert.Equal(t, "Test User", orders[0].Delivery.Name)
+		assert.Equal(t, "tx-1", orders[0].Payment.Transaction)
+		assert.Len(t, orders[0].Items, 1)
 	})
 
 	t.Run("db error", func(t *testing.T) {
@@ -74,7 +77,7 @@ func TestCreateOrder(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	repo := &PostgresRepo{DB: db}
+	repo := &PostgresRepo{DB: db, outbox: true}
 	ctx := context.Background()
 
 	order := models.Order{
@@ -109,6 +112,9 @@ func TestCreateOrder(t *testing.T) {
 			WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectQuery("INSERT INTO item").
 			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectExec("INSERT INTO outbox").
+			WithArgs(models.EventOrderSaved, "test-123", sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
 
 		err := repo.CreateOrder(ctx, order)
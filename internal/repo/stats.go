@@ -0,0 +1,112 @@
+package repo
+
+import (
+	"L0-wb/internal/models"
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+const statsRange = `o.date_created >= $1 AND o.date_created < $2`
+
+// OrderStats считает агрегаты по заказам за [q.From, q.To). Все запросы идут
+// в одной read-only транзакции REPEATABLE READ, чтобы разделы отчёта
+// сходились между собой при параллельной записи заказов.
+func (pgs *PostgresRepo) OrderStats(ctx context.Context, q models.StatsQuery) (models.OrderStats, error) {
+	stats := models.OrderStats{From: q.From, To: q.To}
+	if q.Top <= 0 {
+		q.Top = models.DefaultStatsTop
+	}
+
+	tx, err := pgs.DB.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return stats, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	err = tx.QueryRowContext(ctx, `SELECT COUNT(*), COALESCE(SUM(p.amount), 0), COALESCE(AVG(b.items), 0)
+		FROM orders o
+		JOIN payment p ON p.id = o.payment_id
+		CROSS JOIN LATERAL (SELECT COUNT(*) AS items FROM item i WHERE i.order_uid = o.order_uid) b
+		WHERE `+statsRange, q.From, q.To).Scan(&stats.Orders, &stats.Revenue, &stats.AvgBasketSize)
+	if err != nil {
+		return stats, fmt.Errorf("totals query error: %w", err)
+	}
+	err = tx.QueryRowContext(ctx, `SELECT COALESCE(AVG(i.sale), 0)
+		FROM item i
+		JOIN orders o ON o.order_uid = i.order_uid
+		WHERE `+statsRange, q.From, q.To).Scan(&stats.AvgSale)
+	if err != nil {
+		return stats, fmt.Errorf("sale query error: %w", err)
+	}
+
+	if stats.ByDay, err = orderBuckets(ctx, tx, q, `to_char(o.date_created, 'YYYY-MM-DD')`, "1"); err != nil {
+		return stats, fmt.Errorf("daily stats query error: %w", err)
+	}
+	if stats.ByDelivery, err = orderBuckets(ctx, tx, q, "o.delivery_service", "2 DESC, 1"); err != nil {
+		return stats, fmt.Errorf("delivery stats query error: %w", err)
+	}
+	if stats.ByProvider, err = orderBuckets(ctx, tx, q, "p.provider", "2 DESC, 1"); err != nil {
+		return stats, fmt.Errorf("provider stats query error: %w", err)
+	}
+	if stats.ByBank, err = orderBuckets(ctx, tx, q, "p.bank", "2 DESC, 1"); err != nil {
+		return stats, fmt.Errorf("bank stats query error: %w", err)
+	}
+	if stats.TopBrands, err = itemBuckets(ctx, tx, q, "i.brand"); err != nil {
+		return stats, fmt.Errorf("brand stats query error: %w", err)
+	}
+	if stats.TopSizes, err = itemBuckets(ctx, tx, q, "i.size"); err != nil {
+		return stats, fmt.Errorf("size stats query error: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return stats, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return stats, nil
+}
+
+// orderBuckets группирует заказы с оплатой по выражению key
+func orderBuckets(ctx context.Context, tx *sql.Tx, q models.StatsQuery, key, orderBy string) ([]models.StatsBucket, error) {
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(`SELECT COALESCE(%s, ''), COUNT(*), COALESCE(SUM(p.amount), 0)
+		FROM orders o
+		JOIN payment p ON p.id = o.payment_id
+		WHERE %s
+		GROUP BY 1 ORDER BY %s`, key, statsRange, orderBy), q.From, q.To)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	buckets := []models.StatsBucket{}
+	for rows.Next() {
+		var b models.StatsBucket
+		if err := rows.Scan(&b.Key, &b.Orders, &b.Revenue); err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, b)
+	}
+	return buckets, rows.Err()
+}
+
+// itemBuckets возвращает q.Top значений key с наибольшим числом товаров
+func itemBuckets(ctx context.Context, tx *sql.Tx, q models.StatsQuery, key string) ([]models.ItemBucket, error) {
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(`SELECT COALESCE(%s, ''), COUNT(*), COALESCE(SUM(i.total_price), 0)
+		FROM item i
+		JOIN orders o ON o.order_uid = i.order_uid
+		WHERE %s
+		GROUP BY 1 ORDER BY 2 DESC, 1 LIMIT $3`, key, statsRange), q.From, q.To, q.Top)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	buckets := []models.ItemBucket{}
+	for rows.Next() {
+		var b models.ItemBucket
+		if err := rows.Scan(&b.Key, &b.Items, &b.Revenue); err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, b)
+	}
+	return buckets, rows.Err()
+}
@@ -0,0 +1,72 @@
+package repo
+
+import (
+	"L0-wb/internal/models"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderStats(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := &PostgresRepo{DB: db}
+
+	from := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+	bucketRows := func() *sqlmock.Rows { return sqlmock.NewRows([]string{"key", "orders", "revenue"}) }
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\), COALESCE\\(SUM\\(p.amount\\), 0\\), COALESCE\\(AVG\\(b.items\\), 0\\)").
+		WithArgs(from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"count", "sum", "avg"}).AddRow(3, 4500, 1.5))
+	mock.ExpectQuery("SELECT COALESCE\\(AVG\\(i.sale\\), 0\\)").WithArgs(from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"avg"}).AddRow(12.5))
+	mock.ExpectQuery("to_char\\(o.date_created, 'YYYY-MM-DD'\\).*GROUP BY 1 ORDER BY 1$").WithArgs(from, to).
+		WillReturnRows(bucketRows().AddRow("2025-09-01", 2, 3000).AddRow("2025-09-03", 1, 1500))
+	mock.ExpectQuery("COALESCE\\(o.delivery_service, ''\\).*ORDER BY 2 DESC, 1$").WithArgs(from, to).
+		WillReturnRows(bucketRows().AddRow("meest", 3, 4500))
+	mock.ExpectQuery("COALESCE\\(p.provider, ''\\)").WithArgs(from, to).
+		WillReturnRows(bucketRows().AddRow("wbpay", 2, 3000).AddRow("sbp", 1, 1500))
+	mock.ExpectQuery("COALESCE\\(p.bank, ''\\)").WithArgs(from, to).
+		WillReturnRows(bucketRows().AddRow("Sber", 3, 4500))
+	mock.ExpectQuery("COALESCE\\(i.brand, ''\\).*LIMIT \\$3$").WithArgs(from, to, models.DefaultStatsTop).
+		WillReturnRows(sqlmock.NewRows([]string{"key", "items", "revenue"}).AddRow("NIKE", 4, 4000))
+	mock.ExpectQuery("COALESCE\\(i.size, ''\\)").WithArgs(from, to, models.DefaultStatsTop).
+		WillReturnRows(sqlmock.NewRows([]string{"key", "items", "revenue"}))
+	mock.ExpectCommit()
+
+	stats, err := repo.OrderStats(context.Background(), models.StatsQuery{From: from, To: to})
+	require.NoError(t, err)
+	assert.Equal(t, 3, stats.Orders)
+	assert.Equal(t, int64(4500), stats.Revenue)
+	assert.Equal(t, 1.5, stats.AvgBasketSize)
+	assert.Equal(t, 12.5, stats.AvgSale)
+	assert.Equal(t, []models.StatsBucket{{Key: "2025-09-01", Orders: 2, Revenue: 3000}, {Key: "2025-09-03", Orders: 1, Revenue: 1500}}, stats.ByDay)
+	assert.Len(t, stats.ByProvider, 2)
+	assert.Equal(t, []models.ItemBucket{{Key: "NIKE", Items: 4, Revenue: 4000}}, stats.TopBrands)
+	assert.NotNil(t, stats.TopSizes)
+	assert.Empty(t, stats.TopSizes)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrderStats_QueryError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := &PostgresRepo{DB: db}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT COUNT").WillReturnError(errors.New("db down"))
+	mock.ExpectRollback()
+
+	_, err = repo.OrderStats(context.Background(), models.StatsQuery{To: time.Now()})
+	assert.ErrorContains(t, err, "totals query error")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -180,6 +180,11 @@ func TestCreateOrder(t *testing.T) {
 		WithArgs(0, "", 20, "", "item2", 0, "", 0, 0, "", 0, "uid").
 		WillReturnRows(rows4)
 
+	// outbox insert
+	mock.ExpectExec("INSERT INTO outbox").
+		WithArgs(models.EventOrderSaved, "uid", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
 	// commit
 	mock.ExpectCommit()
 
@@ -0,0 +1,91 @@
+package repo
+
+import (
+	"L0-wb/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// IsDuplicate сообщает, что сохранение не удалось из-за нарушения уникальности:
+// заказ, его трек-номер или транзакция оплаты уже есть в БД
+func IsDuplicate(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// IsDataError сообщает, что БД отвергла сами данные заказа (классы 22 и 23,
+// кроме нарушения уникальности): повтор того же заказа ничего не изменит
+func IsDataError(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code == "23505" {
+		return false
+	}
+	class := pqErr.Code.Class()
+	return class == "22" || class == "23"
+}
+
+// ExistingOrderUIDs возвращает, какие из uids уже сохранены
+func (pgs *PostgresRepo) ExistingOrderUIDs(ctx context.Context, uids []string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	if len(uids) == 0 {
+		return existing, nil
+	}
+
+	rows, err := pgs.DB.QueryContext(ctx, `SELECT order_uid FROM orders WHERE order_uid = ANY($1)`, pq.Array(uids))
+	if err != nil {
+		return nil, fmt.Errorf("order lookup error: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var uid string
+		if err := rows.Scan(&uid); err != nil {
+			return nil, fmt.Errorf("order lookup scanning error: %w", err)
+		}
+		existing[uid] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("order lookup iteration error: %w", err)
+	}
+	return existing, nil
+}
+
+// deleteOrders удаляет заказы пачки, если они уже есть; товары удаляются каскадно
+func deleteOrders(ctx context.Context, tx *sql.Tx, orders []models.Order) error {
+	uids := make([]string, len(orders))
+	for i, o := range orders {
+		uids[i] = o.OrderUID
+	}
+
+	rows, err := tx.QueryContext(ctx, `DELETE FROM orders WHERE order_uid = ANY($1) RETURNING delivery_id, payment_id`, pq.Array(uids))
+	if err != nil {
+		return err
+	}
+	var deliveryIDs, paymentIDs []int64
+	for rows.Next() {
+		var deliveryID, paymentID int64
+		if err := rows.Scan(&deliveryID, &paymentID); err != nil {
+			rows.Close()
+			return err
+		}
+		deliveryIDs = append(deliveryIDs, deliveryID)
+		paymentIDs = append(paymentIDs, paymentID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(deliveryIDs) == 0 {
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM delivery WHERE id = ANY($1)`, pq.Array(deliveryIDs)); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `DELETE FROM payment WHERE id = ANY($1)`, pq.Array(paymentIDs))
+	return err
+}
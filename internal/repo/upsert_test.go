@@ -0,0 +1,77 @@
+package repo
+
+import (
+	"L0-wb/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExistingOrderUIDs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := &PostgresRepo{DB: db}
+
+	mock.ExpectQuery(`SELECT order_uid FROM orders WHERE order_uid = ANY\(\$1\)`).
+		WithArgs(pq.Array([]string{"a", "b"})).
+		WillReturnRows(sqlmock.NewRows([]string{"order_uid"}).AddRow("b"))
+
+	existing, err := repo.ExistingOrderUIDs(context.Background(), []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"b": true}, existing)
+
+	existing, err = repo.ExistingOrderUIDs(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, existing)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertOrders(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := &PostgresRepo{DB: db, outbox: true}
+
+	orders := []models.Order{batchTestOrder("a", 1), batchTestOrder("b", 1)}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`DELETE FROM orders WHERE order_uid = ANY\(\$1\) RETURNING delivery_id, payment_id`).
+		WithArgs(pq.Array([]string{"a", "b"})).
+		WillReturnRows(sqlmock.NewRows([]string{"delivery_id", "payment_id"}).AddRow(1, 2))
+	mock.ExpectExec(`DELETE FROM delivery WHERE id = ANY\(\$1\)`).WithArgs(pq.Array([]int64{1})).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM payment WHERE id = ANY\(\$1\)`).WithArgs(pq.Array([]int64{2})).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT nextval").WithArgs("delivery_id_seq", 2).WillReturnRows(idRows(10, 11))
+	mock.ExpectQuery("SELECT nextval").WithArgs("payment_id_seq", 2).WillReturnRows(idRows(20, 21))
+	mock.ExpectExec(`INSERT INTO delivery`).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`INSERT INTO payment`).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`INSERT INTO orders`).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`INSERT INTO item`).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`INSERT INTO outbox`).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	require.NoError(t, repo.UpsertOrders(context.Background(), orders))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIsDuplicate(t *testing.T) {
+	dup := &pq.Error{Code: "23505", Message: "duplicate key value violates unique constraint"}
+	assert.True(t, IsDuplicate(dup))
+	assert.True(t, IsDuplicate(fmt.Errorf("order batch creation error: %w", dup)))
+	assert.False(t, IsDuplicate(&pq.Error{Code: "23503"}))
+	assert.False(t, IsDuplicate(errors.New("duplicate")))
+}
+
+func TestIsDataError(t *testing.T) {
+	assert.True(t, IsDataError(&pq.Error{Code: "22001"}))
+	assert.True(t, IsDataError(fmt.Errorf("failed to create order: %w", &pq.Error{Code: "23502"})))
+	assert.False(t, IsDataError(&pq.Error{Code: "23505"}))
+	assert.False(t, IsDataError(&pq.Error{Code: "40P01"}))
+	assert.False(t, IsDataError(errors.New("connection refused")))
+}
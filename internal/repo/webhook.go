@@ -0,0 +1,214 @@
+package repo
+
+import (
+	"L0-wb/internal/models"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const webhookColumns = `id, url, secret, events, customer_id, delivery_service, active, created_at`
+
+// CreateWebhook сохраняет подписку и заполняет ID и CreatedAt
+func (pgs *PostgresRepo) CreateWebhook(ctx context.Context, sub *models.WebhookSubscription) error {
+	query := `INSERT INTO webhook_subscriptions (url, secret, events, customer_id, delivery_service, active)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`
+	return pgs.DB.QueryRowContext(ctx, query, sub.URL, sub.Secret, pq.Array(webhookEvents(sub.Events)),
+		sub.CustomerID, sub.DeliveryService, sub.Active).Scan(&sub.ID, &sub.CreatedAt)
+}
+
+// UpdateWebhook перезаписывает подписку по ID; sql.ErrNoRows - подписки нет
+func (pgs *PostgresRepo) UpdateWebhook(ctx context.Context, sub *models.WebhookSubscription) error {
+	query := `UPDATE webhook_subscriptions SET url = $2, secret = $3, events = $4, customer_id = $5,
+		delivery_service = $6, active = $7 WHERE id = $1 RETURNING created_at`
+	return pgs.DB.QueryRowContext(ctx, query, sub.ID, sub.URL, sub.Secret, pq.Array(webhookEvents(sub.Events)),
+		sub.CustomerID, sub.DeliveryService, sub.Active).Scan(&sub.CreatedAt)
+}
+
+// DeleteWebhook удаляет подписку вместе с журналом доставок
+func (pgs *PostgresRepo) DeleteWebhook(ctx context.Context, id int64) error {
+	res, err := pgs.DB.ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (pgs *PostgresRepo) GetWebhook(ctx context.Context, id int64) (models.WebhookSubscription, error) {
+	row := pgs.DB.QueryRowContext(ctx, `SELECT `+webhookColumns+` FROM webhook_subscriptions WHERE id = $1`, id)
+	return scanWebhook(row)
+}
+
+func (pgs *PostgresRepo) ListWebhooks(ctx context.Context) ([]models.WebhookSubscription, error) {
+	rows, err := pgs.DB.QueryContext(ctx, `SELECT `+webhookColumns+` FROM webhook_subscriptions ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subs := []models.WebhookSubscription{}
+	for rows.Next() {
+		sub, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+	return subs, rows.Err()
+}
+
+const webhookDeliveryColumns = `id, subscription_id, event_type, order_uid, payload, status, attempts, next_attempt_at,
+		last_status_code, last_error, created_at, delivered_at`
+
+// ListWebhookDeliveries возвращает последние limit доставок подписки, новые первыми
+func (pgs *PostgresRepo) ListWebhookDeliveries(ctx context.Context, subscriptionID int64, limit int) ([]models.WebhookDelivery, error) {
+	query := `SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries WHERE subscription_id = $1 ORDER BY id DESC LIMIT $2`
+	return pgs.queryWebhookDeliveries(ctx, query, subscriptionID, limit)
+}
+
+// ListOrderWebhookDeliveries возвращает доставки событий заказа всем подпискам в порядке создания
+func (pgs *PostgresRepo) ListOrderWebhookDeliveries(ctx context.Context, orderUID string) ([]models.WebhookDelivery, error) {
+	query := `SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries WHERE order_uid = $1 ORDER BY id`
+	return pgs.queryWebhookDeliveries(ctx, query, orderUID)
+}
+
+func (pgs *PostgresRepo) queryWebhookDeliveries(ctx context.Context, query string, args ...interface{}) ([]models.WebhookDelivery, error) {
+	rows, err := pgs.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		var d models.WebhookDelivery
+		var payload []byte
+		if err := rows.Scan(&d.ID, &d.SubscriptionID, &d.EventType, &d.OrderUID, &payload, &d.Status, &d.Attempts,
+			&d.NextAttemptAt, &d.LastStatusCode, &d.LastError, &d.CreatedAt, &d.DeliveredAt); err != nil {
+			return nil, err
+		}
+		d.Payload = payload
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// EnqueueWebhooksTx ставит события в очередь доставки всем активным подпискам,
+// чьи фильтры им соответствуют, в рамках транзакции заказа и возвращает число
+// созданных доставок
+func (pgs *PostgresRepo) EnqueueWebhooksTx(ctx context.Context, tx *sql.Tx, events ...models.OrderEvent) (int, error) {
+	const cols = 5
+	chunk := chunkRows(cols)
+	total := 0
+	for start := 0; start < len(events); start += chunk {
+		n, err := enqueueWebhooks(ctx, tx, events[start:min(start+chunk, len(events))])
+		if err != nil {
+			return total, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// enqueueWebhooks - один запрос EnqueueWebhooksTx для порции событий
+func enqueueWebhooks(ctx context.Context, tx *sql.Tx, events []models.OrderEvent) (int, error) {
+	const cols = 5
+	args := make([]interface{}, 0, len(events)*cols)
+	values := make([]string, len(events))
+	for i, e := range events {
+		payload, err := json.Marshal(e)
+		if err != nil {
+			return 0, fmt.Errorf("marshal %s event for %s: %w", e.EventType, e.OrderUID, err)
+		}
+		args = append(args, e.EventType, e.OrderUID, e.CustomerID, e.DeliveryService, payload)
+		n := i*cols + 1
+		values[i] = fmt.Sprintf("($%d::text, $%d::text, $%d::text, $%d::text, $%d::jsonb)", n, n+1, n+2, n+3, n+4)
+	}
+
+	query := `INSERT INTO webhook_deliveries (subscription_id, event_type, order_uid, payload)
+		SELECT s.id, e.event_type, e.order_uid, e.payload
+		FROM webhook_subscriptions s
+		JOIN (VALUES ` + strings.Join(values, ", ") + `) AS e(event_type, order_uid, customer_id, delivery_service, payload)
+		ON s.active
+		AND (cardinality(s.events) = 0 OR e.event_type = ANY(s.events))
+		AND (s.customer_id = '' OR s.customer_id = e.customer_id)
+		AND (s.delivery_service = '' OR s.delivery_service = e.delivery_service)`
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// ClaimWebhookDeliveries забирает до limit доставок активных подписок, время попытки
+// которых наступило, и откладывает их на lease: если диспетчер упадёт, не записав
+// результат, доставка повторится после lease. SKIP LOCKED позволяет запускать
+// несколько диспетчеров. Доставки выключенной подписки ждут её включения.
+func (pgs *PostgresRepo) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookTask, error) {
+	query := `UPDATE webhook_deliveries d
+		SET attempts = d.attempts + 1, next_attempt_at = NOW() + $2 * INTERVAL '1 millisecond'
+		FROM webhook_subscriptions s
+		WHERE s.id = d.subscription_id AND s.active AND d.id IN (
+			SELECT pd.id FROM webhook_deliveries pd
+			JOIN webhook_subscriptions ps ON ps.id = pd.subscription_id
+			WHERE pd.status = 'pending' AND pd.next_attempt_at <= NOW() AND ps.active
+			ORDER BY pd.next_attempt_at LIMIT $1 FOR UPDATE OF pd SKIP LOCKED
+		)
+		RETURNING d.id, d.event_type, d.order_uid, d.payload, d.attempts, s.url, s.secret`
+	rows, err := pgs.DB.QueryContext(ctx, query, limit, lease.Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []models.WebhookTask
+	for rows.Next() {
+		var t models.WebhookTask
+		if err := rows.Scan(&t.DeliveryID, &t.EventType, &t.OrderUID, &t.Payload, &t.Attempt, &t.URL, &t.Secret); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, rows.Err()
+}
+
+// FinishWebhookDelivery записывает результат попытки в журнал доставки
+func (pgs *PostgresRepo) FinishWebhookDelivery(ctx context.Context, id int64, res models.WebhookResult) error {
+	query := `UPDATE webhook_deliveries SET status = $2, last_status_code = $3, last_error = $4,
+		next_attempt_at = NOW() + $5 * INTERVAL '1 millisecond',
+		delivered_at = CASE WHEN $2 = 'delivered' THEN NOW() END
+		WHERE id = $1`
+	_, err := pgs.DB.ExecContext(ctx, query, id, res.Status, res.StatusCode, res.Error, res.RetryIn.Milliseconds())
+	return err
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanWebhook(row rowScanner) (models.WebhookSubscription, error) {
+	var sub models.WebhookSubscription
+	err := row.Scan(&sub.ID, &sub.URL, &sub.Secret, pq.Array(&sub.Events), &sub.CustomerID,
+		&sub.DeliveryService, &sub.Active, &sub.CreatedAt)
+	sub.Events = webhookEvents(sub.Events)
+	return sub, err
+}
+
+// webhookEvents заменяет nil пустым списком: колонка events NOT NULL, а в JSON нужен []
+func webhookEvents(events []string) []string {
+	if events == nil {
+		return []string{}
+	}
+	return events
+}
@@ -0,0 +1,107 @@
+package repo
+
+import (
+	"L0-wb/internal/models"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnqueueWebhooksTx(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := &PostgresRepo{DB: db}
+
+	at := time.Date(2025, 9, 20, 12, 0, 0, 0, time.UTC)
+	events := []models.OrderEvent{
+		models.NewOrderSavedEvent(&models.Order{OrderUID: "a", CustomerID: "c1", DeliveryService: "meest"}, at),
+		models.NewOrderSavedEvent(&models.Order{OrderUID: "b", CustomerID: "c2", DeliveryService: "dhl"}, at),
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO webhook_deliveries .* SELECT .* FROM webhook_subscriptions s\\s+JOIN \\(VALUES "+
+		"\\(\\$1::text, \\$2::text, \\$3::text, \\$4::text, \\$5::jsonb\\), \\(\\$6::text, .*\\$10::jsonb\\)\\)").
+		WithArgs(models.EventOrderSaved, "a", "c1", "meest", sqlmock.AnyArg(),
+			models.EventOrderSaved, "b", "c2", "dhl", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	n, err := repo.EnqueueWebhooksTx(context.Background(), tx, events...)
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	n, err = repo.EnqueueWebhooksTx(context.Background(), tx)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClaimAndFinishWebhookDeliveries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := &PostgresRepo{DB: db}
+
+	mock.ExpectQuery("UPDATE webhook_deliveries d\\s+SET attempts = d.attempts \\+ 1.*AND s.active AND d.id IN.*AND ps.active.*FOR UPDATE OF pd SKIP LOCKED").
+		WithArgs(10, int64(30000)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "event_type", "order_uid", "payload", "attempts", "url", "secret"}).
+			AddRow(7, models.EventOrderSaved, "a", []byte(`{}`), 2, "https://partner.example/hook", "s"))
+	mock.ExpectExec("UPDATE webhook_deliveries SET status = \\$2").
+		WithArgs(int64(7), models.WebhookPending, 503, "unavailable", int64(20000)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	tasks, err := repo.ClaimWebhookDeliveries(context.Background(), 10, 30*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, []models.WebhookTask{{
+		DeliveryID: 7, EventType: models.EventOrderSaved, OrderUID: "a", Payload: []byte(`{}`),
+		Attempt: 2, URL: "https://partner.example/hook", Secret: "s",
+	}}, tasks)
+
+	err = repo.FinishWebhookDelivery(context.Background(), 7, models.WebhookResult{
+		Status: models.WebhookPending, StatusCode: 503, Error: "unavailable", RetryIn: 20 * time.Second,
+	})
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteWebhook_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := &PostgresRepo{DB: db}
+
+	mock.ExpectExec("DELETE FROM webhook_subscriptions").WithArgs(int64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.DeleteWebhook(context.Background(), 5), sql.ErrNoRows)
+}
+
+func TestListOrderWebhookDeliveries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := &PostgresRepo{DB: db}
+
+	created := time.Date(2025, 9, 20, 12, 0, 0, 0, time.UTC)
+	delivered := created.Add(time.Second)
+	mock.ExpectQuery("FROM webhook_deliveries WHERE order_uid = \\$1 ORDER BY id").
+		WithArgs("a").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "subscription_id", "event_type", "order_uid", "payload", "status",
+			"attempts", "next_attempt_at", "last_status_code", "last_error", "created_at", "delivered_at"}).
+			AddRow(7, 1, models.EventOrderSaved, "a", []byte(`{}`), models.WebhookDelivered, 1, created, 200, "", created, delivered).
+			AddRow(8, 2, models.EventOrderSaved, "a", []byte(`{}`), models.WebhookPending, 0, created, 0, "", created, nil))
+
+	deliveries, err := repo.ListOrderWebhookDeliveries(context.Background(), "a")
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Len(t, deliveries, 2)
+	assert.Equal(t, delivered, *deliveries[0].DeliveredAt)
+	assert.Nil(t, deliveries[1].DeliveredAt)
+	assert.Equal(t, int64(2), deliveries[1].SubscriptionID)
+}
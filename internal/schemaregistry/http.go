@@ -0,0 +1,102 @@
+package schemaregistry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// HTTPClient ходит в REST API Confluent Schema Registry.
+// Схема может быть зарегистрирована под несколькими subject; клиент принимает
+// только ту, что есть в subject (пустой subject - схема должна быть в одном subject).
+type HTTPClient struct {
+	baseURL string
+	subject string
+	client  *http.Client
+}
+
+func NewHTTPClient(baseURL, subject string, timeout time.Duration) *HTTPClient {
+	return &HTTPClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		subject: subject,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+func (c *HTTPClient) GetSchemaByID(ctx context.Context, id int) (*Schema, error) {
+	var body struct {
+		SchemaType string `json:"schemaType"`
+		Schema     string `json:"schema"`
+	}
+	if err := c.get(ctx, fmt.Sprintf("/schemas/ids/%d", id), &body); err != nil {
+		return nil, err
+	}
+
+	// Версия и subject не входят в ответ /schemas/ids/{id}, запрашиваем отдельно
+	var versions []struct {
+		Subject string `json:"subject"`
+		Version int    `json:"version"`
+	}
+	if err := c.get(ctx, fmt.Sprintf("/schemas/ids/%d/versions", id), &versions); err != nil {
+		return nil, err
+	}
+	idx := -1
+	for i, v := range versions {
+		if c.subject == "" || v.Subject == c.subject {
+			if idx >= 0 {
+				return nil, fmt.Errorf("schema %d is registered under several subjects, set the subject", id)
+			}
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return nil, fmt.Errorf("schema %d: %w: not registered under subject %q", id, ErrSchemaNotFound, c.subject)
+	}
+	version := versions[idx]
+	if version.Subject == "" || version.Version < 1 {
+		return nil, fmt.Errorf("schema %d: invalid subject version %q/%d", id, version.Subject, version.Version)
+	}
+
+	schemaType := body.SchemaType
+	if schemaType == "" {
+		// реестр не возвращает schemaType для AVRO
+		schemaType = SchemaTypeAvro
+	}
+
+	return &Schema{
+		ID:         id,
+		Subject:    version.Subject,
+		Version:    version.Version,
+		SchemaType: schemaType,
+		Schema:     body.Schema,
+	}, nil
+}
+
+func (c *HTTPClient) get(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.schemaregistry.v1+json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("schema registry request %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s: %w", path, ErrSchemaNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("schema registry request %s: unexpected status %d", path, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("schema registry response %s: %w", path, err)
+	}
+	return nil
+}
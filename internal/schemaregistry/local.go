@@ -0,0 +1,42 @@
+package schemaregistry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// LocalClient - замена реестра для тестов и локального запуска.
+// Каждая схема лежит в файле <dir>/<id>.json в формате Schema.
+type LocalClient struct {
+	dir string
+}
+
+func NewLocalClient(dir string) *LocalClient {
+	return &LocalClient{dir: dir}
+}
+
+func (c *LocalClient) GetSchemaByID(_ context.Context, id int) (*Schema, error) {
+	path := filepath.Join(c.dir, strconv.Itoa(id)+".json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("schema %d: %w", id, ErrSchemaNotFound)
+		}
+		return nil, fmt.Errorf("read schema %d: %w", id, err)
+	}
+
+	var schema Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("parse schema %d: %w", id, err)
+	}
+	schema.ID = id
+	if schema.SchemaType == "" {
+		schema.SchemaType = SchemaTypeJSON
+	}
+	return &schema, nil
+}
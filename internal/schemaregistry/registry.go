@@ -0,0 +1,85 @@
+package schemaregistry
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"sync"
+)
+
+// MagicByte - первый байт сообщения в Confluent wire format
+const MagicByte byte = 0
+
+// Типы схем Confluent Schema Registry
+const (
+	SchemaTypeJSON     = "JSON"
+	SchemaTypeAvro     = "AVRO"
+	SchemaTypeProtobuf = "PROTOBUF"
+)
+
+var ErrSchemaNotFound = errors.New("schema not found")
+
+// Schema - схема, зарегистрированная под глобальным ID
+type Schema struct {
+	ID         int    `json:"id"`
+	Subject    string `json:"subject"`
+	Version    int    `json:"version"`
+	SchemaType string `json:"schemaType"`
+	Schema     string `json:"schema"`
+}
+
+// Client разрешает ID схемы из заголовка сообщения в саму схему
+type Client interface {
+	GetSchemaByID(ctx context.Context, id int) (*Schema, error)
+}
+
+// ParseWireFormat отделяет ID схемы от payload.
+// ok=false, если сообщение не в Confluent wire format.
+func ParseWireFormat(data []byte) (id int, payload []byte, ok bool) {
+	if len(data) < 5 || data[0] != MagicByte {
+		return 0, nil, false
+	}
+	return int(binary.BigEndian.Uint32(data[1:5])), data[5:], true
+}
+
+// EncodeWireFormat добавляет к payload magic byte и ID схемы
+func EncodeWireFormat(id int, payload []byte) []byte {
+	out := make([]byte, 5+len(payload))
+	out[0] = MagicByte
+	binary.BigEndian.PutUint32(out[1:5], uint32(id))
+	copy(out[5:], payload)
+	return out
+}
+
+// CachedClient запоминает разрешённые схемы: ID в реестре неизменяемы
+type CachedClient struct {
+	next    Client
+	mu      sync.RWMutex
+	schemas map[int]*Schema
+}
+
+func NewCachedClient(next Client) *CachedClient {
+	return &CachedClient{
+		next:    next,
+		schemas: make(map[int]*Schema),
+	}
+}
+
+func (c *CachedClient) GetSchemaByID(ctx context.Context, id int) (*Schema, error) {
+	c.mu.RLock()
+	schema, ok := c.schemas[id]
+	c.mu.RUnlock()
+	if ok {
+		return schema, nil
+	}
+
+	schema, err := c.next.GetSchemaByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.schemas[id] = schema
+	c.mu.Unlock()
+	return schema, nil
+}
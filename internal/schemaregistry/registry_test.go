@@ -0,0 +1,110 @@
+package schemaregistry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWireFormat(t *testing.T) {
+	encoded := EncodeWireFormat(258, []byte(`{"order_uid":"1"}`))
+	assert.Equal(t, []byte{0, 0, 0, 1, 2}, encoded[:5])
+
+	id, payload, ok := ParseWireFormat(encoded)
+	require.True(t, ok)
+	assert.Equal(t, 258, id)
+	assert.Equal(t, `{"order_uid":"1"}`, string(payload))
+
+	_, _, ok = ParseWireFormat([]byte(`{"order_uid":"1"}`))
+	assert.False(t, ok)
+	_, _, ok = ParseWireFormat([]byte{0, 1})
+	assert.False(t, ok)
+}
+
+func TestLocalClient(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "7.json"),
+		[]byte(`{"subject":"wb-orders-value","version":1,"schema":"{}"}`), 0644)
+	require.NoError(t, err)
+
+	client := NewLocalClient(dir)
+
+	schema, err := client.GetSchemaByID(context.Background(), 7)
+	require.NoError(t, err)
+	assert.Equal(t, 7, schema.ID)
+	assert.Equal(t, "wb-orders-value", schema.Subject)
+	assert.Equal(t, 1, schema.Version)
+	assert.Equal(t, SchemaTypeJSON, schema.SchemaType)
+
+	_, err = client.GetSchemaByID(context.Background(), 8)
+	assert.ErrorIs(t, err, ErrSchemaNotFound)
+}
+
+func TestHTTPClient(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/schemas/ids/3", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"schemaType":"JSON","schema":"{\"type\":\"object\"}"}`))
+	})
+	mux.HandleFunc("/schemas/ids/3/versions", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"subject":"other-value","version":7},{"subject":"wb-orders-value","version":2}]`))
+	})
+	mux.HandleFunc("/schemas/ids/5", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"schemaType":"JSON","schema":"{}"}`))
+	})
+	mux.HandleFunc("/schemas/ids/5/versions", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"subject":"other-value","version":1}]`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := NewHTTPClient(srv.URL+"/", "wb-orders-value", time.Second)
+
+	schema, err := client.GetSchemaByID(context.Background(), 3)
+	require.NoError(t, err)
+	assert.Equal(t, "wb-orders-value", schema.Subject)
+	assert.Equal(t, 2, schema.Version)
+	assert.Equal(t, SchemaTypeJSON, schema.SchemaType)
+	assert.Equal(t, `{"type":"object"}`, schema.Schema)
+
+	_, err = client.GetSchemaByID(context.Background(), 4)
+	assert.ErrorIs(t, err, ErrSchemaNotFound)
+
+	// схема есть в реестре, но не в нашем subject
+	_, err = client.GetSchemaByID(context.Background(), 5)
+	assert.ErrorIs(t, err, ErrSchemaNotFound)
+
+	// без subject схема из нескольких subject неоднозначна
+	_, err = NewHTTPClient(srv.URL, "", time.Second).GetSchemaByID(context.Background(), 3)
+	assert.ErrorContains(t, err, "several subjects")
+}
+
+type countingClient struct {
+	calls int
+}
+
+func (c *countingClient) GetSchemaByID(_ context.Context, id int) (*Schema, error) {
+	c.calls++
+	return &Schema{ID: id, Version: 1, SchemaType: SchemaTypeJSON}, nil
+}
+
+func TestCachedClient(t *testing.T) {
+	next := &countingClient{}
+	client := NewCachedClient(next)
+
+	for i := 0; i < 3; i++ {
+		schema, err := client.GetSchemaByID(context.Background(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, 1, schema.ID)
+	}
+	_, err := client.GetSchemaByID(context.Background(), 2)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, next.calls)
+}
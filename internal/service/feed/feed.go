@@ -0,0 +1,132 @@
+// Package feed раздаёт только что сохранённые заказы подписчикам (SSE, WebSocket)
+package feed
+
+import (
+	"L0-wb/internal/models"
+	"errors"
+	"sync"
+)
+
+// DefaultBuffer - сколько заказов подписчик может не забрать, прежде чем будет отключён
+const DefaultBuffer = 64
+
+// ErrSlowSubscriber - подписка закрыта, потому что клиент не успевал забирать заказы
+var ErrSlowSubscriber = errors.New("subscriber is too slow")
+
+// Filter - условия подписки на новые заказы; пустые поля не фильтруют
+type Filter struct {
+	DeliveryService string
+	CustomerID      string
+}
+
+func (f Filter) match(o *models.Order) bool {
+	return (f.DeliveryService == "" || f.DeliveryService == o.DeliveryService) &&
+		(f.CustomerID == "" || f.CustomerID == o.CustomerID)
+}
+
+// Subscription - поток сохранённых заказов для одного клиента.
+// C закрывается при Close, остановке Hub или переполнении буфера.
+type Subscription struct {
+	C <-chan *models.OrderResponse
+
+	c      chan *models.OrderResponse
+	filter Filter
+	hub    *Hub
+	err    error
+}
+
+// Err объясняет, почему закрылся C: nil - закрыт клиентом или при остановке хаба
+func (s *Subscription) Err() error {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	return s.err
+}
+
+// Close отписывает клиента; повторный вызов ничего не делает
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.remove(s, nil)
+}
+
+// Hub раздаёт сохранённые заказы подписчикам. Publish никогда не блокируется:
+// подписчик с заполненным буфером отключается с ErrSlowSubscriber, чтобы
+// медленный клиент не задерживал консьюмер и остальных подписчиков.
+type Hub struct {
+	buffer int
+	mu     sync.Mutex
+	subs   map[*Subscription]struct{}
+	closed bool
+}
+
+func New(buffer int) *Hub {
+	if buffer <= 0 {
+		buffer = DefaultBuffer
+	}
+	return &Hub{buffer: buffer, subs: make(map[*Subscription]struct{})}
+}
+
+// Subscribe регистрирует подписчика; после Close хаба канал сразу закрыт
+func (h *Hub) Subscribe(filter Filter) *Subscription {
+	c := make(chan *models.OrderResponse, h.buffer)
+	s := &Subscription{C: c, c: c, filter: filter, hub: h}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		close(c)
+		return s
+	}
+	h.subs[s] = struct{}{}
+	return s
+}
+
+// Publish отправляет заказ подходящим подписчикам
+func (h *Hub) Publish(orders ...*models.Order) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, o := range orders {
+		var resp *models.OrderResponse
+		for s := range h.subs {
+			if !s.filter.match(o) {
+				continue
+			}
+			if resp == nil {
+				resp = o.ConvertToOrderResponse()
+			}
+			select {
+			case s.c <- resp:
+			default:
+				h.remove(s, ErrSlowSubscriber)
+			}
+		}
+	}
+}
+
+// Subscribers - число активных подписок
+func (h *Hub) Subscribers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subs)
+}
+
+// Close закрывает все подписки; новые подписки сразу закрыты
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for s := range h.subs {
+		h.remove(s, nil)
+	}
+}
+
+// remove вызывается под h.mu
+func (h *Hub) remove(s *Subscription, err error) {
+	if _, ok := h.subs[s]; !ok {
+		return
+	}
+	delete(h.subs, s)
+	s.err = err
+	close(s.c)
+}
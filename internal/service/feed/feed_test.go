@@ -0,0 +1,84 @@
+package feed
+
+import (
+	"L0-wb/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHub_Filter(t *testing.T) {
+	h := New(4)
+	all := h.Subscribe(Filter{})
+	meest := h.Subscribe(Filter{DeliveryService: "meest"})
+	ivan := h.Subscribe(Filter{DeliveryService: "meest", CustomerID: "ivan"})
+
+	h.Publish(
+		&models.Order{OrderUID: "a", DeliveryService: "meest", CustomerID: "ivan"},
+		&models.Order{OrderUID: "b", DeliveryService: "cdek", CustomerID: "ivan"},
+		&models.Order{OrderUID: "c", DeliveryService: "meest", CustomerID: "petr"},
+	)
+
+	assert.Equal(t, []string{"a", "b", "c"}, drain(all))
+	assert.Equal(t, []string{"a", "c"}, drain(meest))
+	assert.Equal(t, []string{"a"}, drain(ivan))
+}
+
+func TestHub_SlowSubscriber(t *testing.T) {
+	h := New(2)
+	slow := h.Subscribe(Filter{})
+	fast := h.Subscribe(Filter{})
+
+	for _, uid := range []string{"a", "b", "c"} {
+		h.Publish(&models.Order{OrderUID: uid})
+		<-fast.C
+	}
+
+	// буфер медленного переполнился на третьем заказе: уже принятые заказы дочитываются, затем канал закрыт
+	assert.Equal(t, []string{"a", "b"}, readUntilClosed(slow))
+	assert.ErrorIs(t, slow.Err(), ErrSlowSubscriber)
+	assert.Equal(t, 1, h.Subscribers())
+	assert.NoError(t, fast.Err())
+}
+
+func TestHub_Close(t *testing.T) {
+	h := New(1)
+	s := h.Subscribe(Filter{})
+	s.Close()
+	s.Close()
+	_, ok := <-s.C
+	assert.False(t, ok)
+	assert.NoError(t, s.Err())
+
+	other := h.Subscribe(Filter{})
+	h.Close()
+	_, ok = <-other.C
+	assert.False(t, ok)
+
+	late := h.Subscribe(Filter{})
+	_, ok = <-late.C
+	assert.False(t, ok)
+	require.Zero(t, h.Subscribers())
+	h.Publish(&models.Order{OrderUID: "a"})
+}
+
+func drain(s *Subscription) []string {
+	var uids []string
+	for {
+		select {
+		case o := <-s.C:
+			uids = append(uids, o.OrderUID)
+		default:
+			return uids
+		}
+	}
+}
+
+func readUntilClosed(s *Subscription) []string {
+	var uids []string
+	for o := range s.C {
+		uids = append(uids, o.OrderUID)
+	}
+	return uids
+}
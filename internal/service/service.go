@@ -5,6 +5,7 @@ import (
 	"L0-wb/internal/cache"
 	"L0-wb/internal/models"
 	"L0-wb/internal/repo"
+	"L0-wb/internal/service/feed"
 	"context"
 	"database/sql"
 	"errors"
@@ -16,6 +17,8 @@ var ErrNotFound = errors.New("not found")
 type UserService struct {
 	UserRepo repo.Repository
 	cache    cache.Cache
+	stats    *statsCache
+	feed     *feed.Hub
 }
 
 func NewService(ur repo.Repository) (Service, error) {
@@ -23,6 +26,8 @@ func NewService(ur repo.Repository) (Service, error) {
 	s := &UserService{
 		UserRepo: ur,
 		cache:    cache.NewCache(maxSize),
+		stats:    newStatsCache(config.GetStatsCacheTTL()),
+		feed:     feed.New(feed.DefaultBuffer),
 	}
 
 	if err := s.RestoreCache(context.Background()); err != nil {
@@ -57,6 +62,53 @@ func (s *UserService) GetOrderByUID(ctx context.Context, orderUID string) (*mode
 	return &orderDB, nil
 }
 
+// BatchGetOrders возвращает заказы по списку order_uid в порядке запроса и
+// order_uid, которых нет в базе. Заказы из кэша в базу не запрашиваются,
+// остальные читаются одним вызовом репозитория и попадают в кэш.
+// Повторы order_uid учитываются один раз.
+func (s *UserService) BatchGetOrders(ctx context.Context, uids []string) ([]*models.Order, []string, error) {
+	found := make(map[string]*models.Order, len(uids))
+	var misses []string
+	for _, uid := range uids {
+		if _, seen := found[uid]; seen {
+			continue
+		}
+		order, ok := s.cache.Get(uid)
+		if !ok {
+			misses = append(misses, uid)
+		}
+		found[uid] = order
+	}
+
+	if len(misses) > 0 {
+		fetched, err := s.UserRepo.GetOrders(ctx, misses)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get orders: %w", err)
+		}
+		for i := range fetched {
+			order := &fetched[i]
+			s.cache.Set(order.OrderUID, order)
+			found[order.OrderUID] = order
+		}
+	}
+
+	orders := make([]*models.Order, 0, len(found))
+	missing := []string{}
+	for _, uid := range uids {
+		order, ok := found[uid]
+		if !ok {
+			continue // повтор
+		}
+		delete(found, uid)
+		if order == nil {
+			missing = append(missing, uid)
+			continue
+		}
+		orders = append(orders, order)
+	}
+	return orders, missing, nil
+}
+
 func (s *UserService) GetOrderResponse(ctx context.Context, orderUID string) (*models.OrderResponse, error) {
 	order, err := s.GetOrderByUID(ctx, orderUID)
 	if err != nil {
@@ -67,7 +119,7 @@ func (s *UserService) GetOrderResponse(ctx context.Context, orderUID string) (*m
 
 func (s *UserService) CreateOrder(ctx context.Context, order *models.Order) error {
 	if err := order.Validate(); err != nil {
-		return fmt.Errorf("invalid order: %w", err)
+		return fmt.Errorf("%w: %w", models.ErrInvalidOrder, err)
 	}
 
 	if err := s.UserRepo.CreateOrder(ctx, *order); err != nil {
@@ -75,6 +127,7 @@ func (s *UserService) CreateOrder(ctx context.Context, order *models.Order) erro
 	}
 
 	s.cache.Set(order.OrderUID, order)
+	s.publish(order)
 	return nil
 }
 
@@ -82,21 +135,134 @@ func (s *UserService) SaveOrder(ctx context.Context, order *models.Order) error
 	return s.CreateOrder(ctx, order)
 }
 
+// SaveOrders сохраняет пачку заказов одной транзакцией и возвращает ошибку
+// для каждого заказа (nil - сохранён). Невалидные заказы в пачку не попадают.
+// Если пачка не записалась, её заказы получают models.ErrBatchFailed: сохранять
+// их по одному - забота вызывающего, со своим таймаутом на каждый заказ.
+func (s *UserService) SaveOrders(ctx context.Context, orders []*models.Order) []error {
+	errs := make([]error, len(orders))
+	batch := make([]models.Order, 0, len(orders))
+	idx := make([]int, 0, len(orders))
+
+	for i, order := range orders {
+		if err := order.Validate(); err != nil {
+			errs[i] = fmt.Errorf("%w: %w", models.ErrInvalidOrder, err)
+			continue
+		}
+		batch = append(batch, *order)
+		idx = append(idx, i)
+	}
+	if len(batch) == 0 {
+		return errs
+	}
+
+	if err := s.UserRepo.CreateOrders(ctx, batch); err != nil {
+		for _, i := range idx {
+			errs[i] = fmt.Errorf("%w: %w", models.ErrBatchFailed, err)
+		}
+		return errs
+	}
+
+	for _, i := range idx {
+		s.cache.Set(orders[i].OrderUID, orders[i])
+		s.publish(orders[i])
+	}
+	return errs
+}
+
 func (s *UserService) RestoreCache(ctx context.Context) error {
-	lastOrderQuantity := config.GetLimitCache()
-	orders, err := s.UserRepo.GetLastOrders(ctx, lastOrderQuantity)
+	limit := config.GetLimitCache()
+	if limit <= 0 {
+		// без ORDERS_LIMIT заполняем кэш целиком
+		limit = config.GetCacheStartupSize()
+	}
+	_, err := s.WarmCache(ctx, limit)
+	return err
+}
+
+// WarmCache загружает в кэш последние limit заказов и возвращает их количество
+func (s *UserService) WarmCache(ctx context.Context, limit int) (int, error) {
+	orders, err := s.UserRepo.GetLastOrders(ctx, limit)
 	if err != nil {
-		return fmt.Errorf("failed to restore cache: %w", err)
+		return 0, fmt.Errorf("failed to restore cache: %w", err)
 	}
 
 	for _, order := range orders {
 		orderCopy := order
 		s.cache.Set(order.OrderUID, &orderCopy)
 	}
+	return len(orders), nil
+}
+
+func (s *UserService) CacheStats() cache.Stats {
+	return s.cache.Stats()
+}
+
+// ListOrders возвращает заказы по фильтру напрямую из БД
+func (s *UserService) ListOrders(ctx context.Context, filter models.OrderFilter) ([]models.Order, error) {
+	orders, err := s.UserRepo.ListOrders(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders: %w", err)
+	}
+	return orders, nil
+}
+
+// ListOrderHeaders возвращает заказы по фильтру без связанных данных; их
+// догружает OrderParts одним запросом на часть для всей страницы
+func (s *UserService) ListOrderHeaders(ctx context.Context, filter models.OrderFilter) ([]models.Order, error) {
+	orders, err := s.UserRepo.ListOrderHeaders(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders: %w", err)
+	}
+	return orders, nil
+}
+
+// OrderParts возвращает выбранные части заказов по order_uid
+func (s *UserService) OrderParts(ctx context.Context, uids []string, parts models.OrderParts) (map[string]models.Order, error) {
+	orders, err := s.UserRepo.GetOrderParts(ctx, uids, parts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load order parts: %w", err)
+	}
+	return orders, nil
+}
+
+// OrderTimeline возвращает историю заказа: создание, оплату и доставку вебхуков
+func (s *UserService) OrderTimeline(ctx context.Context, orderUID string) ([]models.TimelineEvent, error) {
+	order, err := s.GetOrderByUID(ctx, orderUID)
+	if err != nil {
+		return nil, err
+	}
+	deliveries, err := s.UserRepo.ListOrderWebhookDeliveries(ctx, order.OrderUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	return models.BuildTimeline(order, deliveries), nil
+}
+
+// StreamOrders передаёт в fn заказы по фильтру, не загружая всю выборку в память.
+// Кэш не используется и не заполняется.
+func (s *UserService) StreamOrders(ctx context.Context, filter models.OrderFilter, fn func(*models.Order) error) error {
+	if err := s.UserRepo.StreamOrders(ctx, filter, fn); err != nil {
+		return fmt.Errorf("failed to stream orders: %w", err)
+	}
 	return nil
 }
 
+// SubscribeOrders подписывает на заказы, сохранённые после вызова
+func (s *UserService) SubscribeOrders(filter feed.Filter) *feed.Subscription {
+	return s.feed.Subscribe(filter)
+}
+
+func (s *UserService) publish(order *models.Order) {
+	if s.feed != nil {
+		s.feed.Publish(order)
+	}
+}
+
 func (s *UserService) Close() error {
+	if s.feed != nil {
+		s.feed.Close()
+	}
 	if s.cache != nil {
 		s.cache.Close()
 	}
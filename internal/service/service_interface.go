@@ -1,15 +1,34 @@
 package service
 
 import (
+	"L0-wb/internal/cache"
 	"L0-wb/internal/models"
+	"L0-wb/internal/service/feed"
 	"context"
 )
 
 type Service interface {
 	GetOrderByUID(ctx context.Context, orderUID string) (*models.Order, error)
+	BatchGetOrders(ctx context.Context, uids []string) ([]*models.Order, []string, error)
 	GetOrderResponse(ctx context.Context, orderUID string) (*models.OrderResponse, error)
 	CreateOrder(ctx context.Context, order *models.Order) error
 	SaveOrder(ctx context.Context, order *models.Order) error
+	SaveOrders(ctx context.Context, orders []*models.Order) []error
+	ListOrders(ctx context.Context, filter models.OrderFilter) ([]models.Order, error)
+	ListOrderHeaders(ctx context.Context, filter models.OrderFilter) ([]models.Order, error)
+	OrderParts(ctx context.Context, uids []string, parts models.OrderParts) (map[string]models.Order, error)
+	OrderTimeline(ctx context.Context, orderUID string) ([]models.TimelineEvent, error)
+	StreamOrders(ctx context.Context, filter models.OrderFilter, fn func(*models.Order) error) error
+	SubscribeOrders(filter feed.Filter) *feed.Subscription
+	OrderStats(ctx context.Context, q models.StatsQuery) (models.OrderStats, error)
+	CreateWebhook(ctx context.Context, sub *models.WebhookSubscription) error
+	UpdateWebhook(ctx context.Context, sub *models.WebhookSubscription) error
+	DeleteWebhook(ctx context.Context, id int64) error
+	GetWebhook(ctx context.Context, id int64) (models.WebhookSubscription, error)
+	ListWebhooks(ctx context.Context) ([]models.WebhookSubscription, error)
+	WebhookDeliveries(ctx context.Context, id int64, limit int) ([]models.WebhookDelivery, error)
 	RestoreCache(ctx context.Context) error
+	WarmCache(ctx context.Context, limit int) (int, error)
+	CacheStats() cache.Stats
 	Close() error
 }
@@ -12,6 +12,7 @@ import (
 
 	"L0-wb/internal/mocks"
 	"L0-wb/internal/models"
+	"L0-wb/internal/service/feed"
 )
 
 func TestService_GetOrderByUID(t *testing.T) {
@@ -89,6 +90,44 @@ func TestService_GetOrderByUID(t *testing.T) {
 	}
 }
 
+func TestUserService_BatchGetOrders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+	svc := &UserService{UserRepo: mockRepo, cache: mockCache}
+
+	cached := &models.Order{OrderUID: "cached"}
+	mockCache.EXPECT().Get("db").Return(nil, false)
+	mockCache.EXPECT().Get("cached").Return(cached, true)
+	mockCache.EXPECT().Get("missing").Return(nil, false)
+	// промахи кэша читаются одним вызовом, повтор "db" не запрашивается
+	mockRepo.EXPECT().GetOrders(gomock.Any(), []string{"db", "missing"}).
+		Return([]models.Order{{OrderUID: "db"}}, nil)
+	mockCache.EXPECT().Set("db", &models.Order{OrderUID: "db"})
+
+	orders, missing, err := svc.BatchGetOrders(context.Background(), []string{"db", "cached", "missing", "db"})
+	assert.NoError(t, err)
+	if assert.Len(t, orders, 2) {
+		assert.Equal(t, "db", orders[0].OrderUID)
+		assert.Same(t, cached, orders[1])
+	}
+	assert.Equal(t, []string{"missing"}, missing)
+
+	// всё в кэше - база не нужна
+	mockCache.EXPECT().Get("cached").Return(cached, true)
+	orders, missing, err = svc.BatchGetOrders(context.Background(), []string{"cached"})
+	assert.NoError(t, err)
+	assert.Len(t, orders, 1)
+	assert.Empty(t, missing)
+
+	mockCache.EXPECT().Get("db").Return(nil, false)
+	mockRepo.EXPECT().GetOrders(gomock.Any(), []string{"db"}).Return(nil, errors.New("db down"))
+	_, _, err = svc.BatchGetOrders(context.Background(), []string{"db"})
+	assert.Error(t, err)
+}
+
 func TestUserService_CreateOrder(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -172,3 +211,221 @@ func TestUserService_RestoreCache(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestUserService_SaveOrders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+	svc := &UserService{UserRepo: mockRepo, cache: mockCache}
+
+	newOrder := func(uid string) *models.Order {
+		return &models.Order{
+			OrderUID:    uid,
+			TrackNumber: "WBIL" + uid,
+			Entry:       "WBIL",
+			Delivery: models.Delivery{
+				Name:    "Test User",
+				Phone:   "+79991234567",
+				Zip:     "123456",
+				City:    "Moscow",
+				Address: "Test St, 1",
+			},
+			Payment: models.Payment{Transaction: "tx-" + uid, Currency: "USD", Provider: "stripe", Amount: 100},
+			Items:   []models.Item{{TrackNumber: "WBIL" + uid, Price: 100, Name: "Item", TotalPrice: 100}},
+		}
+	}
+
+	t.Run("batch saved", func(t *testing.T) {
+		a, b := newOrder("a"), newOrder("b")
+		invalid := &models.Order{OrderUID: "invalid"}
+
+		mockRepo.EXPECT().CreateOrders(gomock.Any(), []models.Order{*a, *b}).Return(nil)
+		mockCache.EXPECT().Set("a", a)
+		mockCache.EXPECT().Set("b", b)
+
+		errs := svc.SaveOrders(context.Background(), []*models.Order{a, invalid, b})
+		assert.Len(t, errs, 3)
+		assert.NoError(t, errs[0])
+		assert.Error(t, errs[1])
+		assert.NoError(t, errs[2])
+	})
+
+	t.Run("batch failed", func(t *testing.T) {
+		a, b := newOrder("a"), newOrder("b")
+		invalid := &models.Order{OrderUID: "invalid"}
+
+		mockRepo.EXPECT().CreateOrders(gomock.Any(), gomock.Any()).Return(errors.New("duplicate key"))
+
+		errs := svc.SaveOrders(context.Background(), []*models.Order{a, invalid, b})
+		assert.ErrorIs(t, errs[0], models.ErrBatchFailed)
+		assert.ErrorIs(t, errs[1], models.ErrInvalidOrder)
+		assert.ErrorIs(t, errs[2], models.ErrBatchFailed)
+	})
+}
+
+func TestUserService_WarmCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+	svc := &UserService{UserRepo: mockRepo, cache: mockCache}
+
+	mockRepo.EXPECT().GetLastOrders(gomock.Any(), 2).
+		Return([]models.Order{{OrderUID: "a"}, {OrderUID: "b"}}, nil)
+	mockCache.EXPECT().Set("a", gomock.Any())
+	mockCache.EXPECT().Set("b", gomock.Any())
+
+	n, err := svc.WarmCache(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+}
+
+func TestUserService_ListOrders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	svc := &UserService{UserRepo: mockRepo, cache: mocks.NewMockCache(ctrl)}
+
+	filter := models.OrderFilter{CustomerID: "test", Limit: 10}
+	mockRepo.EXPECT().ListOrders(gomock.Any(), filter).Return([]models.Order{{OrderUID: "a"}}, nil)
+	orders, err := svc.ListOrders(context.Background(), filter)
+	assert.NoError(t, err)
+	assert.Len(t, orders, 1)
+
+	mockRepo.EXPECT().ListOrders(gomock.Any(), gomock.Any()).Return(nil, errors.New("db down"))
+	_, err = svc.ListOrders(context.Background(), models.OrderFilter{})
+	assert.Error(t, err)
+}
+
+func TestUserService_OrderTimeline(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+	svc := &UserService{UserRepo: mockRepo, cache: mockCache}
+
+	created := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	order := &models.Order{OrderUID: "a", DateCreated: created}
+	mockCache.EXPECT().Get("a").Return(order, true)
+	mockRepo.EXPECT().ListOrderWebhookDeliveries(gomock.Any(), "a").Return([]models.WebhookDelivery{
+		{Status: models.WebhookPending, CreatedAt: created.Add(time.Second)},
+	}, nil)
+
+	events, err := svc.OrderTimeline(context.Background(), "a")
+	assert.NoError(t, err)
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, models.TimelineCreated, events[0].Type)
+		assert.Equal(t, models.WebhookPending, events[1].Status)
+	}
+
+	mockCache.EXPECT().Get("missing").Return(nil, false)
+	mockRepo.EXPECT().GetOrder(gomock.Any(), "missing").Return(models.Order{}, sql.ErrNoRows)
+	_, err = svc.OrderTimeline(context.Background(), "missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestUserService_StreamOrders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	svc := &UserService{UserRepo: mockRepo, cache: mocks.NewMockCache(ctrl)}
+
+	stop := errors.New("stop")
+	mockRepo.EXPECT().StreamOrders(gomock.Any(), models.OrderFilter{Locale: "ru"}, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ models.OrderFilter, fn func(*models.Order) error) error {
+			return fn(&models.Order{OrderUID: "a"})
+		})
+
+	err := svc.StreamOrders(context.Background(), models.OrderFilter{Locale: "ru"}, func(o *models.Order) error {
+		assert.Equal(t, "a", o.OrderUID)
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+}
+
+func TestUserService_OrderStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	stats := newStatsCache(time.Minute)
+	now := time.Date(2025, 9, 8, 12, 0, 0, 0, time.UTC)
+	stats.now = func() time.Time { return now }
+	svc := &UserService{UserRepo: mockRepo, cache: mocks.NewMockCache(ctrl), stats: stats}
+
+	from := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	q := models.StatsQuery{From: from, To: from.AddDate(0, 0, 7)}
+	want := models.StatsQuery{From: q.From, To: q.To, Top: models.DefaultStatsTop}
+
+	mockRepo.EXPECT().OrderStats(gomock.Any(), want).Return(models.OrderStats{Orders: 3}, nil)
+	got, err := svc.OrderStats(context.Background(), q)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, got.Orders)
+
+	// повтор в пределах TTL, в том числе с тем же моментом в другой зоне, берётся из кэша
+	moscow := time.FixedZone("MSK", 3*60*60)
+	got, err = svc.OrderStats(context.Background(), models.StatsQuery{From: from.In(moscow), To: q.To})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, got.Orders)
+
+	now = now.Add(time.Minute)
+	mockRepo.EXPECT().OrderStats(gomock.Any(), want).Return(models.OrderStats{}, errors.New("db down"))
+	_, err = svc.OrderStats(context.Background(), q)
+	assert.Error(t, err)
+}
+
+func TestUserService_PublishesSavedOrders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+	svc := &UserService{UserRepo: mockRepo, cache: mockCache, feed: feed.New(4)}
+	sub := svc.SubscribeOrders(feed.Filter{})
+	defer sub.Close()
+
+	order := &models.Order{
+		OrderUID:    "test-123",
+		TrackNumber: "WBIL123456789",
+		Entry:       "WBIL",
+		Delivery:    models.Delivery{Name: "Test User", Phone: "+79991234567", Zip: "123456", City: "Moscow", Address: "Test St, 1"},
+		Payment:     models.Payment{Transaction: "tx-123", Currency: "USD", Provider: "stripe", Amount: 100},
+		Items:       []models.Item{{TrackNumber: "WBIL123456789", Price: 100, Name: "Item", TotalPrice: 100}},
+	}
+
+	mockRepo.EXPECT().CreateOrders(gomock.Any(), gomock.Any()).Return(errors.New("duplicate key"))
+	svc.SaveOrders(context.Background(), []*models.Order{order})
+	assert.Empty(t, sub.C)
+
+	mockRepo.EXPECT().CreateOrders(gomock.Any(), gomock.Any()).Return(nil)
+	mockCache.EXPECT().Set("test-123", order)
+	svc.SaveOrders(context.Background(), []*models.Order{order})
+
+	published := <-sub.C
+	assert.Equal(t, "test-123", published.OrderUID)
+	assert.Equal(t, "Moscow", published.Delivery.City)
+}
+
+func TestUserService_UpdateWebhookKeepsSecret(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	svc := &UserService{UserRepo: mockRepo}
+
+	sub := &models.WebhookSubscription{ID: 1, URL: "https://partner.example/hook", Active: true}
+	mockRepo.EXPECT().GetWebhook(gomock.Any(), int64(1)).Return(models.WebhookSubscription{ID: 1, Secret: "old"}, nil)
+	mockRepo.EXPECT().UpdateWebhook(gomock.Any(), gomock.Any()).Return(nil)
+	assert.NoError(t, svc.UpdateWebhook(context.Background(), sub))
+	assert.Equal(t, "old", sub.Secret)
+
+	mockRepo.EXPECT().GetWebhook(gomock.Any(), int64(2)).Return(models.WebhookSubscription{}, sql.ErrNoRows)
+	err := svc.UpdateWebhook(context.Background(), &models.WebhookSubscription{ID: 2, URL: "https://partner.example/hook"})
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"L0-wb/internal/models"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// statsCacheSize ограничивает число разных интервалов, хранимых одновременно
+const statsCacheSize = 64
+
+type statsEntry struct {
+	stats   models.OrderStats
+	expires time.Time
+}
+
+// statsCache хранит посчитанную статистику ttl; ключ - интервал и размер топа
+type statsCache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[models.StatsQuery]statsEntry
+	now     func() time.Time
+}
+
+func newStatsCache(ttl time.Duration) *statsCache {
+	return &statsCache{ttl: ttl, entries: make(map[models.StatsQuery]statsEntry), now: time.Now}
+}
+
+func (c *statsCache) get(q models.StatsQuery) (models.OrderStats, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[q]
+	if !ok || !c.now().Before(e.expires) {
+		return models.OrderStats{}, false
+	}
+	return e.stats, true
+}
+
+func (c *statsCache) set(q models.StatsQuery, stats models.OrderStats) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if len(c.entries) >= statsCacheSize {
+		for k, e := range c.entries {
+			if !now.Before(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+	}
+	if len(c.entries) >= statsCacheSize {
+		// все записи ещё живы - вытесняем произвольную
+		for k := range c.entries {
+			delete(c.entries, k)
+			break
+		}
+	}
+	c.entries[q] = statsEntry{stats: stats, expires: now.Add(c.ttl)}
+}
+
+// OrderStats возвращает агрегаты за интервал; результат кэшируется на STATS_CACHE_TTL
+func (s *UserService) OrderStats(ctx context.Context, q models.StatsQuery) (models.OrderStats, error) {
+	if q.Top <= 0 {
+		q.Top = models.DefaultStatsTop
+	}
+	// time.Time с разными Location не равны как ключи map
+	q.From, q.To = q.From.UTC(), q.To.UTC()
+
+	if s.stats != nil {
+		if stats, ok := s.stats.get(q); ok {
+			return stats, nil
+		}
+	}
+	stats, err := s.UserRepo.OrderStats(ctx, q)
+	if err != nil {
+		return models.OrderStats{}, fmt.Errorf("failed to compute stats: %w", err)
+	}
+	if s.stats != nil {
+		s.stats.set(q, stats)
+	}
+	return stats, nil
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"L0-wb/internal/models"
+	"L0-wb/internal/webhook"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// CreateWebhook валидирует и сохраняет подписку; без секрета он генерируется
+func (s *UserService) CreateWebhook(ctx context.Context, sub *models.WebhookSubscription) error {
+	if err := sub.Validate(); err != nil {
+		return fmt.Errorf("invalid webhook: %w", err)
+	}
+	if sub.Secret == "" {
+		secret, err := webhook.NewSecret()
+		if err != nil {
+			return fmt.Errorf("failed to generate secret: %w", err)
+		}
+		sub.Secret = secret
+	}
+	if err := s.UserRepo.CreateWebhook(ctx, sub); err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+	return nil
+}
+
+// UpdateWebhook перезаписывает подписку; пустой секрет оставляет прежний
+func (s *UserService) UpdateWebhook(ctx context.Context, sub *models.WebhookSubscription) error {
+	if err := sub.Validate(); err != nil {
+		return fmt.Errorf("invalid webhook: %w", err)
+	}
+	if sub.Secret == "" {
+		old, err := s.GetWebhook(ctx, sub.ID)
+		if err != nil {
+			return err
+		}
+		sub.Secret = old.Secret
+	}
+	if err := s.UserRepo.UpdateWebhook(ctx, sub); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+	return nil
+}
+
+func (s *UserService) DeleteWebhook(ctx context.Context, id int64) error {
+	if err := s.UserRepo.DeleteWebhook(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	return nil
+}
+
+func (s *UserService) GetWebhook(ctx context.Context, id int64) (models.WebhookSubscription, error) {
+	sub, err := s.UserRepo.GetWebhook(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.WebhookSubscription{}, ErrNotFound
+		}
+		return models.WebhookSubscription{}, fmt.Errorf("failed to get webhook: %w", err)
+	}
+	return sub, nil
+}
+
+func (s *UserService) ListWebhooks(ctx context.Context) ([]models.WebhookSubscription, error) {
+	subs, err := s.UserRepo.ListWebhooks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	return subs, nil
+}
+
+// WebhookDeliveries возвращает журнал доставок подписки, новые первыми
+func (s *UserService) WebhookDeliveries(ctx context.Context, id int64, limit int) ([]models.WebhookDelivery, error) {
+	if _, err := s.GetWebhook(ctx, id); err != nil {
+		return nil, err
+	}
+	deliveries, err := s.UserRepo.ListWebhookDeliveries(ctx, id, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
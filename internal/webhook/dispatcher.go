@@ -0,0 +1,229 @@
+// Package webhook доставляет события о заказах на адреса партнёров.
+// Доставки лежат в Postgres (webhook_deliveries): диспетчер забирает те, чьё
+// время наступило, отправляет подписанный POST и записывает результат попытки.
+package webhook
+
+import (
+	"L0-wb/config"
+	"L0-wb/internal/models"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Store - часть репозитория, нужная диспетчеру
+type Store interface {
+	ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookTask, error)
+	FinishWebhookDelivery(ctx context.Context, id int64, res models.WebhookResult) error
+}
+
+const (
+	retryBase = 10 * time.Second
+	retryMax  = time.Hour
+	// parallelism - одновременных запросов из одной пачки
+	parallelism = 8
+)
+
+// Dispatcher доставляет события с повторами: после неудачной попытки n
+// следующая через retryBase*2^(n-1), но не позже retryMax; после maxAttempts
+// доставка помечается failed.
+type Dispatcher struct {
+	store       Store
+	client      *http.Client
+	batchSize   int
+	interval    time.Duration
+	maxAttempts int
+	lease       time.Duration
+	now         func() time.Time
+}
+
+func NewDispatcher(cfg config.Config, store Store) *Dispatcher {
+	logrus.WithField("max_attempts", cfg.Webhook.MaxAttempts).Info("webhook dispatcher initialized")
+	return newDispatcher(store, newClient(cfg.Webhook.Timeout),
+		cfg.Webhook.BatchSize, cfg.Webhook.Interval, cfg.Webhook.MaxAttempts)
+}
+
+// ErrNonPublicAddr - адрес получателя оказался внутренним (см. models.IsPublicWebhookAddr)
+var ErrNonPublicAddr = errors.New("webhook address is not public")
+
+// newClient - HTTP-клиент доставки. Адрес проверяется при подключении, уже
+// после резолва имени, поэтому ни DNS, ни редирект не ведут во внутреннюю
+// сеть. Прокси из окружения не используется: через него проверка не работает.
+func newClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: dialControl}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// dialControl не даёт подключиться к непубличному адресу
+func dialControl(_, address string, _ syscall.RawConn) error {
+	ap, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrNonPublicAddr, address)
+	}
+	if !models.IsPublicWebhookAddr(ap.Addr()) {
+		return fmt.Errorf("%w: %s", ErrNonPublicAddr, ap.Addr())
+	}
+	return nil
+}
+
+func newDispatcher(store Store, client *http.Client, batchSize int, interval time.Duration, maxAttempts int) *Dispatcher {
+	if batchSize < 1 {
+		batchSize = 50
+	}
+	if interval <= 0 {
+		interval = time.Second
+	}
+	if maxAttempts < 1 {
+		maxAttempts = 8
+	}
+	if client.Timeout <= 0 {
+		client.Timeout = 10 * time.Second
+	}
+	return &Dispatcher{
+		store:       store,
+		client:      client,
+		batchSize:   batchSize,
+		interval:    interval,
+		maxAttempts: maxAttempts,
+		// пачка отправляется параллельно, но с запасом на медленных получателей
+		lease: 2*client.Timeout*time.Duration((batchSize+parallelism-1)/parallelism) + time.Minute,
+		now:   time.Now,
+	}
+}
+
+// Run доставляет события до отмены ctx; после неполной пачки ждёт interval
+func (d *Dispatcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := d.DeliverBatch(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			logrus.WithError(err).Error("webhook dispatcher error")
+		}
+		if n == d.batchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverBatch выполняет одну попытку для каждой наступившей доставки
+// и возвращает их количество
+func (d *Dispatcher) DeliverBatch(ctx context.Context) (int, error) {
+	tasks, err := d.store.ClaimWebhookDeliveries(ctx, d.batchSize, d.lease)
+	if err != nil {
+		return 0, fmt.Errorf("claim webhook deliveries: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, parallelism)
+	errs := make([]error, len(tasks))
+	for i := range tasks {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			task := tasks[i]
+			res := d.attempt(ctx, task)
+			if err := d.store.FinishWebhookDelivery(ctx, task.DeliveryID, res); err != nil {
+				errs[i] = fmt.Errorf("save webhook delivery %d: %w", task.DeliveryID, err)
+				return
+			}
+			logrus.WithFields(logrus.Fields{
+				"delivery": task.DeliveryID,
+				"order":    task.OrderUID,
+				"attempt":  task.Attempt,
+				"status":   res.Status,
+				"code":     res.StatusCode,
+			}).Info("webhook attempt finished")
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return len(tasks), err
+		}
+	}
+	return len(tasks), nil
+}
+
+// attempt отправляет событие и решает, что делать с доставкой дальше
+func (d *Dispatcher) attempt(ctx context.Context, task models.WebhookTask) models.WebhookResult {
+	code, err := d.send(ctx, task)
+	if err == nil {
+		return models.WebhookResult{Status: models.WebhookDelivered, StatusCode: code}
+	}
+
+	res := models.WebhookResult{Status: models.WebhookPending, StatusCode: code, Error: err.Error()}
+	if task.Attempt >= d.maxAttempts {
+		res.Status = models.WebhookFailed
+		return res
+	}
+	res.RetryIn = Backoff(task.Attempt)
+	return res
+}
+
+func (d *Dispatcher) send(ctx context.Context, task models.WebhookTask) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, task.URL, bytes.NewReader(task.Payload))
+	if err != nil {
+		return 0, err
+	}
+	ts := d.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "L0-wb-webhooks/1")
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(task.DeliveryID, 10))
+	req.Header.Set(HeaderEvent, task.EventType)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(ts, 10))
+	req.Header.Set(HeaderSignature, Sign(task.Secret, ts, task.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// тело ответа не читаем в журнал: в нём могут быть чужие данные
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp.StatusCode, nil
+	}
+	return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+}
+
+// Backoff - задержка перед повтором после неудачной попытки attempt (с 1)
+func Backoff(attempt int) time.Duration {
+	delay := retryBase
+	for i := 1; i < attempt && delay < retryMax; i++ {
+		delay *= 2
+	}
+	if delay > retryMax {
+		delay = retryMax
+	}
+	return delay
+}
@@ -0,0 +1,174 @@
+package webhook
+
+import (
+	"L0-wb/internal/models"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStore повторяет семантику webhook_deliveries: попытка увеличивает attempts,
+// pending-доставка снова выдаётся после RetryIn
+type fakeStore struct {
+	mu      sync.Mutex
+	now     time.Time
+	tasks   map[int64]*models.WebhookTask
+	due     map[int64]time.Time
+	results map[int64][]models.WebhookResult
+}
+
+func newFakeStore(now time.Time, tasks ...models.WebhookTask) *fakeStore {
+	s := &fakeStore{now: now, tasks: map[int64]*models.WebhookTask{}, due: map[int64]time.Time{}, results: map[int64][]models.WebhookResult{}}
+	for i := range tasks {
+		t := tasks[i]
+		s.tasks[t.DeliveryID] = &t
+		s.due[t.DeliveryID] = now
+	}
+	return s
+}
+
+func (s *fakeStore) ClaimWebhookDeliveries(_ context.Context, limit int, lease time.Duration) ([]models.WebhookTask, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var claimed []models.WebhookTask
+	for id, t := range s.tasks {
+		if len(claimed) == limit || s.due[id].After(s.now) {
+			continue
+		}
+		t.Attempt++
+		s.due[id] = s.now.Add(lease)
+		claimed = append(claimed, *t)
+	}
+	return claimed, nil
+}
+
+func (s *fakeStore) FinishWebhookDelivery(_ context.Context, id int64, res models.WebhookResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.results[id] = append(s.results[id], res)
+	if res.Status == models.WebhookPending {
+		s.due[id] = s.now.Add(res.RetryIn)
+	} else {
+		delete(s.tasks, id)
+	}
+	return nil
+}
+
+func TestDispatcher_SignedDeliveryWithRetry(t *testing.T) {
+	const secret = "partner-secret"
+	payload := []byte(`{"event_type":"order.saved","order_uid":"a"}`)
+
+	var mu sync.Mutex
+	calls := 0
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.NoError(t, Verify(secret, r.Header.Get(HeaderTimestamp), r.Header.Get(HeaderSignature), body, time.Minute))
+		assert.Equal(t, "order.saved", r.Header.Get(HeaderEvent))
+		assert.Equal(t, "7", r.Header.Get(HeaderDelivery))
+		assert.JSONEq(t, string(payload), string(body))
+
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if calls == 1 {
+			http.Error(w, "temporarily unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	now := time.Now()
+	store := newFakeStore(now, models.WebhookTask{DeliveryID: 7, EventType: "order.saved", OrderUID: "a", Payload: payload, URL: receiver.URL, Secret: secret})
+	d := newDispatcher(store, receiver.Client(), 10, time.Second, 3)
+
+	n, err := d.DeliverBatch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	require.Len(t, store.results[7], 1)
+	first := store.results[7][0]
+	assert.Equal(t, models.WebhookPending, first.Status)
+	assert.Equal(t, http.StatusServiceUnavailable, first.StatusCode)
+	assert.Equal(t, "unexpected status 503", first.Error, "тело ответа в журнал не попадает")
+	assert.Equal(t, Backoff(1), first.RetryIn)
+
+	// до истечения задержки доставка не выдаётся
+	n, err = d.DeliverBatch(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	store.now = now.Add(first.RetryIn)
+	n, err = d.DeliverBatch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, models.WebhookResult{Status: models.WebhookDelivered, StatusCode: http.StatusNoContent}, store.results[7][1])
+}
+
+func TestDispatcher_GivesUpAfterMaxAttempts(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	now := time.Now()
+	store := newFakeStore(now, models.WebhookTask{DeliveryID: 1, Payload: []byte(`{}`), URL: receiver.URL, Secret: "s"})
+	d := newDispatcher(store, receiver.Client(), 10, time.Second, 2)
+
+	for i := 0; i < 2; i++ {
+		_, err := d.DeliverBatch(context.Background())
+		require.NoError(t, err)
+		store.now = store.now.Add(time.Hour)
+	}
+	require.Len(t, store.results[1], 2)
+	assert.Equal(t, models.WebhookPending, store.results[1][0].Status)
+	assert.Equal(t, models.WebhookFailed, store.results[1][1].Status)
+	assert.Empty(t, store.tasks)
+}
+
+func TestDispatcher_RejectsNonPublicAddr(t *testing.T) {
+	called := false
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer receiver.Close()
+
+	store := newFakeStore(time.Now(), models.WebhookTask{DeliveryID: 1, Payload: []byte(`{}`), URL: receiver.URL, Secret: "s"})
+	d := newDispatcher(store, newClient(time.Second), 10, time.Second, 3)
+
+	_, err := d.DeliverBatch(context.Background())
+	require.NoError(t, err)
+	assert.False(t, called)
+	require.Len(t, store.results[1], 1)
+	assert.Equal(t, models.WebhookPending, store.results[1][0].Status)
+	assert.Contains(t, store.results[1][0].Error, ErrNonPublicAddr.Error())
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, 10*time.Second, Backoff(1))
+	assert.Equal(t, 20*time.Second, Backoff(2))
+	assert.Equal(t, 80*time.Second, Backoff(4))
+	assert.Equal(t, time.Hour, Backoff(20))
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{}`)
+	ts := time.Now().Unix()
+	sig := Sign("secret", ts, body)
+	itoa := func(v int64) string { return strconv.FormatInt(v, 10) }
+
+	assert.NoError(t, Verify("secret", itoa(ts), sig, body, time.Minute))
+	assert.Error(t, Verify("other", itoa(ts), sig, body, time.Minute))
+	assert.Error(t, Verify("secret", itoa(ts), sig, []byte(`{"x":1}`), time.Minute))
+	old := ts - 3600
+	assert.Error(t, Verify("secret", itoa(old), Sign("secret", old, body), body, time.Minute))
+}
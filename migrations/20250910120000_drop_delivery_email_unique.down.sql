@@ -0,0 +1,8 @@
+-- После up у повторных покупателей email повторяется, и ограничение не создать.
+-- Email остаётся у самой ранней доставки, у остальных дубликатов обнуляется:
+-- заказы не удаляются, но откат теряет email повторных заказов.
+UPDATE delivery d
+SET email = NULL
+WHERE email IS NOT NULL
+  AND EXISTS (SELECT 1 FROM delivery e WHERE e.email = d.email AND e.id < d.id);
+ALTER TABLE delivery ADD CONSTRAINT delivery_email_key UNIQUE (email);
@@ -0,0 +1 @@
+ALTER TABLE delivery DROP CONSTRAINT IF EXISTS delivery_email_key;
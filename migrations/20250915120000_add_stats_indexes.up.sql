@@ -0,0 +1,4 @@
+-- Диапазон по date_created: статистика, список и выгрузка (ORDER BY date_created DESC, order_uid)
+CREATE INDEX IF NOT EXISTS orders_date_created_idx ON orders (date_created DESC, order_uid);
+-- Товары заказа: GetItemsByOrderUID и соединения item с orders в статистике
+CREATE INDEX IF NOT EXISTS item_order_uid_idx ON item (order_uid);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS webhook_deliveries_order_uid_idx;
+DROP INDEX IF EXISTS orders_customer_id_idx;
@@ -0,0 +1,4 @@
+-- Поиск заказов по покупателю (GET /api/v1/orders?customer_id=...), новые первыми
+CREATE INDEX IF NOT EXISTS orders_customer_id_idx ON orders (customer_id, date_created DESC, order_uid);
+-- История заказа: доставки вебхуков по order_uid
+CREATE INDEX IF NOT EXISTS webhook_deliveries_order_uid_idx ON webhook_deliveries (order_uid, id);
@@ -0,0 +1,2 @@
+-- Аренда записи реле: публикация идёт вне транзакции, запись повторяется после locked_until
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS locked_until TIMESTAMP;
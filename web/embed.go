@@ -0,0 +1,12 @@
+// Package web - страницы сервиса: поиск заказов (index.html) и Swagger UI.
+// Файлы встроены в бинарник, поэтому сервис не зависит от рабочей директории.
+// swagger-ui/ - файлы dist Swagger UI 5.18.2 без изменений (Apache 2.0, см.
+// swagger-ui/LICENSE); для обновления они заменяются файлами новой версии.
+package web
+
+import "embed"
+
+// FS - встроенные файлы; новые файлы нужно добавить в шаблон go:embed
+//
+//go:embed *.html swagger-ui
+var FS embed.FS